@@ -0,0 +1,534 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/admin"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/api"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/archive"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/backup"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/evidence"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/snapshot"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/store"
+)
+
+const usage = `Verwendung: server [Befehl]
+
+Ohne Befehl wird der HTTP-Server gestartet.
+
+Befehle:
+  projects list [Optionen]     Listet IPA-Projekte auf
+  projects archive <id>        Archiviert ein IPA-Projekt
+  projects unarchive <id>      Stellt ein archiviertes IPA-Projekt wieder her
+  projects delete <id>         Löscht ein IPA-Projekt endgültig
+  projects export [Optionen]   Exportiert IPA-Projekte als JSON- oder ZIP-Archiv
+  projects import <datei>      Importiert IPA-Projekte aus einem Archiv
+  projects verify [id...]      Prüft die Momentaufnahmen der Bewertung auf nachträgliche Änderungen,
+                               ohne IDs für alle Projekte; bei einem Befund mit Exit-Code 1.
+                               Das Entfernen der letzten Momentaufnahme samt Verlaufseintrag
+                               wird nicht erkannt
+  backup                       Erstellt sofort einen Snapshot im Sicherungsverzeichnis
+  restore [Optionen] [datei]   Stellt einen Snapshot vollständig oder für ein Projekt wieder her
+  healthcheck [-live]          Prüft den laufenden Server über /readyz (mit -live über /healthz),
+                               z.B. für den HEALTHCHECK im Container ohne curl
+`
+
+// runCommand führt ein Unterkommando des Server-Binaries aus.
+func runCommand(ctx context.Context, cfg common.Config, args []string) error {
+	switch args[0] {
+	case "projects":
+		return runProjectsCommand(ctx, cfg, args[1:])
+	case "backup":
+		return runBackupCommand(ctx, cfg)
+	case "restore":
+		return runRestoreCommand(ctx, cfg, args[1:])
+	case "healthcheck":
+		return runHealthcheckCommand(ctx, cfg, args[1:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return nil
+	}
+	fmt.Fprint(os.Stderr, usage)
+	return fmt.Errorf("unbekannter Befehl %q", args[0])
+}
+
+func runProjectsCommand(ctx context.Context, cfg common.Config, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return errors.New("projects: Unterbefehl fehlt")
+	}
+
+	mongoStore, err := store.NewMongoStore(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("Fehler beim Initialisieren des MongoStores: %w", err)
+	}
+	defer disconnect(mongoStore)
+
+	switch args[0] {
+	case "list":
+		return listProjects(ctx, mongoStore, args[1:])
+	case "archive", "unarchive":
+		id, err := projectIDArgument(cfg, args)
+		if err != nil {
+			return err
+		}
+		_, err = mongoStore.SetIpaProjectArchived(ctx, id, args[0] == "archive")
+		if errors.Is(err, store.ErrNotFound) {
+			return fmt.Errorf("kein IPA-Projekt mit ID %s gefunden", id)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("IPA-Projekt %s: archived=%t\n", id, args[0] == "archive")
+		return nil
+	case "delete":
+		id, err := projectIDArgument(cfg, args)
+		if err != nil {
+			return err
+		}
+		project, err := mongoStore.GetIpaProject(ctx, id)
+		if err == nil {
+			_, err = mongoStore.DeleteIpaProject(ctx, id)
+		}
+		if errors.Is(err, store.ErrNotFound) {
+			return fmt.Errorf("kein IPA-Projekt mit ID %s gefunden", id)
+		}
+		if err != nil {
+			return err
+		}
+		// Die Dateien der Belege liegen nicht in der Datenbank
+		blobs := evidence.DiskStore{Dir: cfg.EvidenceDir}
+		for _, item := range project.Evidence {
+			if item.Kind != models.EvidenceFile {
+				continue
+			}
+			if err := blobs.Delete(ctx, item.ID); err != nil {
+				slog.Error("deleting evidence file failed", "evidence_id", item.ID, "error", err)
+			}
+		}
+		fmt.Printf("IPA-Projekt %s gelöscht\n", id)
+		return nil
+	case "export":
+		return exportProjects(ctx, cfg, mongoStore, args[1:])
+	case "import":
+		if len(args) != 2 {
+			return errors.New("projects import: genau eine Archivdatei erwartet")
+		}
+		return importProjects(ctx, cfg, mongoStore, args[1])
+	case "verify":
+		return verifyProjects(ctx, cfg, mongoStore, args[1:])
+	}
+	fmt.Fprint(os.Stderr, usage)
+	return fmt.Errorf("projects: unbekannter Unterbefehl %q", args[0])
+}
+
+func projectIDArgument(cfg common.Config, args []string) (string, error) {
+	if len(args) != 2 {
+		return "", fmt.Errorf("projects %s: genau eine Projekt-ID erwartet", args[0])
+	}
+	return parseProjectID(cfg, args[1])
+}
+
+func parseProjectID(cfg common.Config, value string) (string, error) {
+	id := common.NormalizeProjectID(value)
+	if err := cfg.ProjectIDScheme().Validate(id); err != nil {
+		return "", fmt.Errorf("ungültige Projekt-ID %q: %w", value, err)
+	}
+	return id, nil
+}
+
+// gradeFlag ist ein optionaler Notenwert für die Kommandozeile.
+type gradeFlag struct{ value *float64 }
+
+func (f *gradeFlag) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return fmt.Sprint(*f.value)
+}
+
+func (f *gradeFlag) Set(s string) error {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	f.value = &v
+	return nil
+}
+
+func listProjects(ctx context.Context, mongoStore *store.MongoStore, args []string) error {
+	var filter store.ProjectFilter
+	var query admin.ProjectQuery
+	var minGrade, maxGrade gradeFlag
+	var asJSON bool
+
+	fs := flag.NewFlagSet("projects list", flag.ContinueOnError)
+	fs.StringVar(&filter.DateFrom, "from", "", "nur Projekte mit Abgabe ab diesem Tag (YYYY-MM-DD)")
+	fs.StringVar(&filter.DateTo, "to", "", "nur Projekte mit Abgabe bis zu diesem Tag (YYYY-MM-DD)")
+	fs.StringVar(&filter.Topic, "topic", "", "Teilstring des Themas")
+	fs.BoolVar(&filter.IncludeArchived, "archived", false, "archivierte Projekte einschliessen")
+	fs.Var(&minGrade, "min-grade", "minimale Note")
+	fs.Var(&maxGrade, "max-grade", "maximale Note")
+	fs.StringVar(&query.Sort, "sort", "id", "Sortierfeld (id, firstname, lastname, topic, date, grade), \"-\" für absteigend")
+	fs.IntVar(&query.Page, "page", 1, "nur diese Seite ausgeben, ohne Angabe alle Seiten")
+	fs.IntVar(&query.PageSize, "page-size", admin.MaxPageSize, "Einträge pro Seite")
+	fs.BoolVar(&asJSON, "json", false, "Ausgabe als JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query.MinGrade = minGrade.value
+	query.MaxGrade = maxGrade.value
+	if err := query.Validate(); err != nil {
+		return err
+	}
+	singlePage := false
+	fs.Visit(func(f *flag.Flag) { singlePage = singlePage || f.Name == "page" })
+
+	page, err := admin.FindProjects(ctx, mongoStore, filter, query)
+	if err != nil {
+		return err
+	}
+	// Ohne -page werden alle Seiten geladen und gemeinsam ausgegeben
+	for !singlePage && len(page.Items) < page.Total {
+		query.Page++
+		next, err := admin.FindProjects(ctx, mongoStore, filter, query)
+		if err != nil {
+			return err
+		}
+		if len(next.Items) == 0 {
+			break // Projekte wurden inzwischen gelöscht
+		}
+		page.Items = append(page.Items, next.Items...)
+		page.Total = next.Total
+	}
+	if !singlePage {
+		page.PageSize = len(page.Items)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(page)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNACHNAME\tVORNAME\tDATUM\tTEIL 1\tTEIL 2\tNOTE\tARCHIVIERT\tTHEMA")
+	for _, p := range page.Items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.2f\t%.2f\t%.2f\t%t\t%s\n",
+			p.ID, p.Lastname, p.Firstname, p.Date, p.Part1Grade, p.Part2Grade, p.Grade, p.Archived, p.Topic)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if singlePage {
+		fmt.Printf("%d von %d Projekten (Seite %d)\n", len(page.Items), page.Total, page.Page)
+	} else {
+		fmt.Printf("%d von %d Projekten\n", len(page.Items), page.Total)
+	}
+	return nil
+}
+
+func exportProjects(ctx context.Context, cfg common.Config, mongoStore *store.MongoStore, args []string) error {
+	var output, ids, format string
+	var includePasswords bool
+
+	fs := flag.NewFlagSet("projects export", flag.ContinueOnError)
+	fs.StringVar(&output, "o", "-", "Zieldatei, \"-\" für die Standardausgabe")
+	fs.StringVar(&ids, "ids", "", "kommagetrennte Projekt-IDs, ohne Angabe alle Projekte")
+	fs.StringVar(&format, "format", "json", "Archivformat (json, zip)")
+	fs.BoolVar(&includePasswords, "include-passwords", false, "Passwort-Hashes exportieren")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if format != "json" && format != "zip" {
+		return fmt.Errorf("ungültiges Format %q", format)
+	}
+
+	criteriaStore, err := store.NewCriteriaStore(cfg)
+	if err != nil {
+		return err
+	}
+	var idList []string
+	if ids != "" {
+		idList = strings.Split(ids, ",")
+	}
+	projects, err := mongoStore.GetIpaProjectsByIDs(ctx, idList)
+	if err != nil {
+		return err
+	}
+	if len(idList) > 0 && len(projects) != len(idList) {
+		return errors.New("nicht alle IPA-Projekte wurden gefunden")
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "-" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	a := archive.New(projects, criteriaStore.GetVersion(), includePasswords)
+	if format == "zip" {
+		err = a.WriteZIP(w)
+	} else {
+		err = a.WriteJSON(w)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d IPA-Projekte exportiert\n", len(projects))
+	return nil
+}
+
+func importProjects(ctx context.Context, cfg common.Config, mongoStore *store.MongoStore, filename string) error {
+	criteriaStore, err := store.NewCriteriaStore(cfg)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	a, err := archive.Read(data)
+	if err != nil {
+		return err
+	}
+
+	importer := archive.Importer{
+		Store:            mongoStore,
+		IDScheme:         cfg.ProjectIDScheme(),
+		CatalogueVersion: criteriaStore.GetVersion(),
+		HashPassword:     api.HashPassword,
+	}
+	report, err := importer.Import(ctx, a)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "URSPRÜNGLICHE ID\tID\tKONFLIKT\tEINMAL-PASSWORT")
+	for _, p := range report.Projects {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.OriginalID, p.ID, p.Conflict, p.Password)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, warning := range report.Warnings {
+		fmt.Fprintln(os.Stderr, "Warnung:", warning)
+	}
+	fmt.Printf("%d IPA-Projekte importiert, %d mit neuer ID\n", len(report.Projects), report.Conflicts)
+	return nil
+}
+
+func verifyProjects(ctx context.Context, cfg common.Config, mongoStore *store.MongoStore, ids []string) error {
+	for i, value := range ids {
+		id, err := parseProjectID(cfg, value)
+		if err != nil {
+			return err
+		}
+		ids[i] = id
+	}
+	projects, err := mongoStore.GetIpaProjectsByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 && len(projects) != len(ids) {
+		return errors.New("nicht alle IPA-Projekte wurden gefunden")
+	}
+
+	signer := snapshot.Signer{Key: cfg.SnapshotKey()}
+	invalid := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tMOMENTAUFNAHME\tBEFUND")
+	for _, project := range projects {
+		verification := signer.VerifyProject(project)
+		if !verification.Valid {
+			invalid++
+		}
+		for _, check := range verification.Snapshots {
+			finding := "ok"
+			if !check.Valid {
+				problems := make([]string, len(check.Problems))
+				for i, problem := range check.Problems {
+					problems[i] = string(problem)
+				}
+				finding = strings.Join(problems, ", ")
+			}
+			fmt.Fprintf(w, "%s\t%d (%s)\t%s\n", project.ID, check.Sequence, check.ID, finding)
+		}
+		if verification.Missing > 0 {
+			fmt.Fprintf(w, "%s\t-\t%d fehlend\n", project.ID, verification.Missing)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d von %d IPA-Projekten mit veränderten Bewertungen", invalid, len(projects))
+	}
+	fmt.Printf("%d IPA-Projekte geprüft, keine Änderungen gefunden\n", len(projects))
+	return nil
+}
+
+func runBackupCommand(ctx context.Context, cfg common.Config) error {
+	mongoStore, err := store.NewMongoStore(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("Fehler beim Initialisieren des MongoStores: %w", err)
+	}
+	defer disconnect(mongoStore)
+
+	path, err := backup.Create(ctx, mongoStore, cfg.BackupDir, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Snapshot erstellt: %s\n", path)
+
+	removed, err := backup.Prune(cfg.BackupDir, cfg.BackupRetention)
+	for _, path := range removed {
+		fmt.Printf("Alter Snapshot gelöscht: %s\n", path)
+	}
+	return err
+}
+
+func runRestoreCommand(ctx context.Context, cfg common.Config, args []string) error {
+	var at, projectID string
+	var confirmed bool
+
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	fs.StringVar(&at, "at", "", "neuester Snapshot zu diesem Zeitpunkt (RFC 3339 oder YYYY-MM-DD), ohne Angabe der neueste")
+	fs.StringVar(&projectID, "project", "", "nur dieses Projekt wiederherstellen")
+	fs.BoolVar(&confirmed, "yes", false, "vollständige Wiederherstellung bestätigen (überschreibt alle Projekte)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path, err := snapshotPath(cfg.BackupDir, at, fs.Args())
+	if err != nil {
+		return err
+	}
+	snapshot, err := backup.Load(path)
+	if err != nil {
+		return err
+	}
+	if projectID == "" && !confirmed {
+		return fmt.Errorf("vollständige Wiederherstellung aus %s überschreibt alle Projekte, mit -yes bestätigen", path)
+	}
+
+	mongoStore, err := store.NewMongoStore(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("Fehler beim Initialisieren des MongoStores: %w", err)
+	}
+	defer disconnect(mongoStore)
+
+	if projectID != "" {
+		id, err := parseProjectID(cfg, projectID)
+		if err != nil {
+			return err
+		}
+		if err := backup.RestoreProject(ctx, mongoStore, snapshot, id); err != nil {
+			return err
+		}
+		fmt.Printf("IPA-Projekt %s aus %s wiederhergestellt\n", id, path)
+		return nil
+	}
+
+	if err := backup.RestoreAll(ctx, mongoStore, snapshot); err != nil {
+		return err
+	}
+	fmt.Printf("Snapshot %s vom %s vollständig wiederhergestellt\n", path, snapshot.CreatedAt.Format(time.RFC3339))
+	return nil
+}
+
+// snapshotPath bestimmt den wiederherzustellenden Snapshot: die angegebene Datei,
+// den neuesten Snapshot zum Zeitpunkt at oder den neuesten Snapshot überhaupt.
+func snapshotPath(dir, at string, files []string) (string, error) {
+	if len(files) > 1 {
+		return "", errors.New("restore: höchstens eine Snapshot-Datei erwartet")
+	}
+	if len(files) == 1 {
+		if at != "" {
+			return "", errors.New("restore: entweder eine Datei oder -at angeben")
+		}
+		return files[0], nil
+	}
+
+	point := time.Now()
+	if at != "" {
+		var err error
+		if point, err = time.Parse(time.RFC3339, at); err != nil {
+			date, dateErr := time.ParseInLocation(time.DateOnly, at, time.Local)
+			if dateErr != nil {
+				return "", fmt.Errorf("ungültiger Zeitpunkt %q", at)
+			}
+			point = date.AddDate(0, 0, 1).Add(-time.Nanosecond) // Ende des Tages
+		}
+	}
+	entry, err := backup.FindAt(dir, point)
+	if err != nil {
+		return "", fmt.Errorf("kein Snapshot in %s zum Zeitpunkt %s: %w", dir, point.Format(time.RFC3339), err)
+	}
+	return entry.Path, nil
+}
+
+// healthcheckTimeout begrenzt die Dauer des healthcheck-Befehls.
+const healthcheckTimeout = 5 * time.Second
+
+func runHealthcheckCommand(ctx context.Context, cfg common.Config, args []string) error {
+	var live bool
+	fs := flag.NewFlagSet("healthcheck", flag.ContinueOnError)
+	fs.BoolVar(&live, "live", false, "nur prüfen, ob der Prozess antwortet (/healthz)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	path := "/readyz"
+	if live {
+		path = "/healthz"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthcheckTimeout)
+	defer cancel()
+	url := fmt.Sprintf("http://127.0.0.1%s%s", common.FormatServerAddress(cfg.ServerPort), path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("healthcheck: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("healthcheck: %s antwortet mit %s: %s", path, res.Status, strings.TrimSpace(string(body)))
+	}
+	fmt.Println(strings.TrimSpace(string(body)))
+	return nil
+}
+
+// disconnect trennt die Verbindung eines Unterkommandos zur Datenbank.
+func disconnect(mongoStore *store.MongoStore) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := mongoStore.Disconnect(ctx); err != nil {
+		slog.Warn("disconnecting from MongoDB failed", "error", err)
+	}
+}
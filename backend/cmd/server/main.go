@@ -1,11 +1,24 @@
 package main
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	_ "time/tzdata" // Zeitzonen auch in Images ohne tzdata
 
 	"github.com/Liuuner/criteria-catalogue/backend/internal/api"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/backup"
 	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/evidence"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/metrics"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/snapshot"
 	"github.com/Liuuner/criteria-catalogue/backend/internal/store"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/static"
@@ -14,37 +27,113 @@ import (
 
 var version = "dev"
 
+// fatal protokolliert einen Fehler und beendet das Programm.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
 func main() {
-	log.Printf("Version: %s", version)
 	cfg, err := common.LoadConfig()
 	if err != nil {
-		log.Fatalf("Fehler beim Laden der Konfiguration: %v", err)
+		fatal("loading configuration failed", err)
+	}
+
+	logger, err := common.NewLogger(cfg, os.Stderr)
+	if err != nil {
+		fatal("configuring logger failed", err)
+	}
+	slog.SetDefault(logger)
+
+	// SIGINT und SIGTERM beenden den Server geordnet bzw. brechen ein Unterkommando ab
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Unterkommandos wie "projects list" für die Administration per Skript
+	if len(os.Args) > 1 {
+		err := runCommand(ctx, cfg, os.Args[1:])
+		stop()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
 	}
 
+	slog.Info("starting criteria catalogue", "version", version)
+
 	// Set the token secret for authentication
 	api.SetTokenSecret(cfg.TokenSecret)
 
 	// Initialisiere den Datenspeicher mit der Kriteriendatei
 	dataStore, err := store.NewCriteriaStore(cfg)
 	if err != nil {
-		log.Fatalf("Fehler beim Initialisieren des CriteriaStores: %v", err)
+		fatal("loading criteria catalogue failed", err)
+	}
+	slog.Info("criteria catalogue loaded",
+		"version", dataStore.GetVersion(),
+		"criteria", len(dataStore.GetAllCriteria(i18n.Default)),
+		"mandatory", len(dataStore.GetMandatoryCriteria(i18n.Default)),
+		"selection_rules", len(dataStore.GetSelectionRules(i18n.Default)))
+
+	// SIGHUP lädt den Kriterienkatalog neu, z.B. nach einer Anpassung der Kriteriendatei
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			if err := dataStore.Reload(); err != nil {
+				slog.Error("reloading criteria catalogue failed", "error", err)
+				continue
+			}
+			slog.Info("criteria catalogue reloaded", "version", dataStore.GetVersion(), "criteria", len(dataStore.GetAllCriteria(i18n.Default)))
+		}
+	}()
+
+	mongoStore, err := store.NewMongoStore(ctx, cfg)
+	if err != nil {
+		fatal("connecting to MongoDB failed", err)
+	}
+
+	if err := metrics.RegisterProjectCount(mongoStore.CountIpaProjects); err != nil {
+		fatal("registering project metrics failed", err)
 	}
-	log.Printf("Loaded %d criteria from file, %d are mandatory", len(dataStore.GetAllCriteria()), len(dataStore.GetMandatoryCriteria()))
 
-	mongoStore, err := store.NewMongoStore(cfg)
+	// Der Scheduler endet mit dem Signal, ein laufender Snapshot wird vor dem Trennen der Datenbank abgeschlossen
+	var background sync.WaitGroup
+	if cfg.BackupInterval > 0 {
+		scheduler := backup.Scheduler{
+			Source:    mongoStore,
+			Dir:       cfg.BackupDir,
+			Interval:  cfg.BackupInterval,
+			Retention: cfg.BackupRetention,
+		}
+		background.Go(func() { scheduler.Run(ctx) })
+	}
+
+	location, err := cfg.Location()
 	if err != nil {
-		log.Fatalf("Fehler beim Initialisieren des MongoStores: %v", err)
+		fatal("loading time zone failed", err)
 	}
-	defer mongoStore.Disconnect()
 
 	// Initialisiere die Handler mit dem Store
 	handlers := &api.Handlers{
-		JsonStore:    dataStore,
-		MongoStore:   mongoStore,
-		SecureCookie: cfg.SecureCookie,
+		JsonStore:          dataStore,
+		MongoStore:         mongoStore,
+		SecureCookie:       cfg.SecureCookie,
+		AdminToken:         cfg.AdminToken,
+		ProjectIDScheme:    cfg.ProjectIDScheme(),
+		ReadinessTimeout:   cfg.ReadinessTimeout,
+		Location:           location,
+		PlannedHoursPerDay: cfg.PlannedHoursPerDay,
+		Blobs:              evidence.DiskStore{Dir: cfg.EvidenceDir},
+		Scanner:            evidence.NewCommandScanner(cfg.EvidenceScanCommand),
+		EvidenceLimits:     evidence.Limits{MaxSize: cfg.EvidenceMaxSize, Types: cfg.EvidenceTypes},
+		Snapshots:          snapshot.Signer{Key: cfg.SnapshotKey()},
 	}
 
-	router := gin.Default()
+	router := gin.New()
+	// Der Request-Logger läuft zuerst, damit auch abgefangene Panics mit Request-ID protokolliert werden
+	router.Use(api.RequestLoggerMiddleware(), api.MetricsMiddleware(), gin.CustomRecoveryWithWriter(io.Discard, api.RecoverPanic))
 
 	// CORS-Middleware für die Kommunikation mit dem Frontend
 	config := cors.DefaultConfig()
@@ -53,18 +142,57 @@ func main() {
 	config.AllowCredentials = true // Required for cookies
 	router.Use(cors.New(config))
 
-	router.GET("/version", func(c *gin.Context) {
-		c.String(http.StatusOK, version)
-	})
+	// Optional werden Anfragen gegen die OpenAPI-Beschreibung geprüft
+	if cfg.OpenAPIValidation {
+		doc, err := api.LoadOpenAPI()
+		if err != nil {
+			fatal("loading OpenAPI document failed", err)
+		}
+		validation, err := api.RequestValidationMiddleware(doc)
+		if err != nil {
+			fatal("configuring OpenAPI validation failed", err)
+		}
+		router.Use(validation)
+	}
 
 	// Richte den Router ein
+	api.SetupSystemRoutes(router, handlers, version)
 	api.SetupRouter(router, handlers, mongoStore)
 
 	router.NoRoute(static.Serve("/", static.LocalFile("./static", true)))
 
+	server := &http.Server{
+		Addr:              common.FormatServerAddress(cfg.ServerPort),
+		Handler:           router,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+	}
+
 	// Starte den Server
-	log.Printf("Server wird auf Port %d gestartet...", cfg.ServerPort)
-	if err := router.Run(common.FormatServerAddress(cfg.ServerPort)); err != nil {
-		log.Fatalf("Laufzeitfehler des Servers: %v", err)
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("starting server", "port", cfg.ServerPort)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		fatal("server failed", err)
+	case <-ctx.Done():
+	}
+
+	// Keine neuen Verbindungen annehmen und laufende Anfragen abschliessen lassen
+	slog.Info("shutting down server", "timeout", cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("draining requests failed", "error", err)
+	}
+	background.Wait()
+	if err := mongoStore.Disconnect(shutdownCtx); err != nil {
+		slog.Error("disconnecting from MongoDB failed", "error", err)
 	}
+	slog.Info("server stopped")
 }
@@ -0,0 +1,38 @@
+package admin
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+var credentialsSheet = template.Must(template.New("credentials").Parse(`<!DOCTYPE html>
+<html lang="de">
+<head>
+<meta charset="utf-8">
+<title>Zugangsdaten IPA-Projekte</title>
+<style>
+  body { font-family: sans-serif; margin: 1cm; }
+  .card { border: 1px dashed #888; padding: 0.5cm; margin-bottom: 0.5cm; page-break-inside: avoid; }
+  .card h2 { margin: 0 0 0.2cm; font-size: 1.1em; }
+  .credential { font-family: monospace; font-size: 1.3em; }
+  .hint { font-size: 0.85em; color: #555; }
+</style>
+</head>
+<body>
+{{range .}}<div class="card">
+  <h2>{{.Firstname}} {{.Lastname}}</h2>
+  <div>{{.Topic}} &middot; {{.Date}}</div>
+  <p>Projekt-ID: <span class="credential">{{.ID}}</span><br>
+  Passwort: <span class="credential">{{.Password}}</span></p>
+  <div class="hint">Das Passwort ist nur für den ersten Login gültig und muss danach geändert werden.</div>
+</div>
+{{end}}</body>
+</html>
+`))
+
+// WriteCredentialsSheet schreibt ein druckbares HTML-Blatt mit den Zugangsdaten aller importierten Projekte.
+func WriteCredentialsSheet(w io.Writer, credentials []models.ProjectCredentials) error {
+	return credentialsSheet.Execute(w, credentials)
+}
@@ -0,0 +1,167 @@
+package admin
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/grade"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/store"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 200
+)
+
+// ProjectQuery enthält Notenbereich, Sortierung und Paginierung der Projektliste.
+type ProjectQuery struct {
+	MinGrade *float64
+	MaxGrade *float64
+	Sort     string // Sortierfeld, mit "-" für absteigende Reihenfolge, z. B. "-grade"
+	Page     int    // beginnt bei 1
+	PageSize int
+}
+
+var sortFields = map[string]func(a, b models.ProjectSummary) int{
+	"id":        func(a, b models.ProjectSummary) int { return strings.Compare(a.ID, b.ID) },
+	"firstname": func(a, b models.ProjectSummary) int { return compareFold(a.Firstname, b.Firstname) },
+	"lastname":  func(a, b models.ProjectSummary) int { return compareFold(a.Lastname, b.Lastname) },
+	"topic":     func(a, b models.ProjectSummary) int { return compareFold(a.Topic, b.Topic) },
+	"date":      func(a, b models.ProjectSummary) int { return strings.Compare(a.Date, b.Date) },
+	"grade":     func(a, b models.ProjectSummary) int { return cmp.Compare(a.Grade, b.Grade) },
+}
+
+var (
+	ErrInvalidPage     = errors.New("page must be at least 1")
+	ErrInvalidPageSize = errors.New("pageSize must be between 1 and %d")
+	ErrGradeRange      = errors.New("minGrade must not be greater than maxGrade")
+	ErrUnknownSort     = errors.New("unknown sort field %q")
+)
+
+// Validate prüft die Parameter und setzt Standardwerte für die Paginierung.
+func (q *ProjectQuery) Validate() error {
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = DefaultPageSize
+	}
+	if q.Page < 1 {
+		return ErrInvalidPage
+	}
+	if q.PageSize < 1 || q.PageSize > MaxPageSize {
+		return NewContentError(ErrInvalidPageSize, MaxPageSize)
+	}
+	if q.MinGrade != nil && q.MaxGrade != nil && *q.MinGrade > *q.MaxGrade {
+		return ErrGradeRange
+	}
+	if q.Sort != "" {
+		if _, ok := sortFields[strings.TrimPrefix(q.Sort, "-")]; !ok {
+			return NewContentError(ErrUnknownSort, strings.TrimPrefix(q.Sort, "-"))
+		}
+	}
+	return nil
+}
+
+// Summarize erstellt die Kurzansicht eines Projekts inklusive der aktuellen Noten.
+func Summarize(project models.MongoIpaProject) models.ProjectSummary {
+	dto := project.Map()
+	result := grade.CalculateGrade(project.Criteria)
+	return models.ProjectSummary{
+		ID:            dto.ID,
+		Firstname:     project.Firstname,
+		Lastname:      project.Lastname,
+		Topic:         project.Topic,
+		Date:          project.Date,
+		Archived:      project.Archived,
+		CriteriaCount: len(project.Criteria),
+		Part1Grade:    result.Part1.Grade,
+		Part2Grade:    result.Part2.Grade,
+		Grade:         math.Round((result.Part1.Grade+result.Part2.Grade)/2*100) / 100,
+	}
+}
+
+// ProjectStore lädt die Projekte für FindProjects.
+type ProjectStore interface {
+	FindIpaProjects(ctx context.Context, filter store.ProjectFilter) ([]models.MongoIpaProject, error)
+	FindIpaProjectPage(ctx context.Context, filter store.ProjectFilter, page store.ProjectPage) ([]models.MongoIpaProject, int, error)
+}
+
+// FindProjects liefert die angeforderte Seite der Projekte. Ohne Notenbereich und Sortierung nach
+// Note übernimmt die Datenbank Sortierung und Paginierung. Die Note wird aus den Kriterien
+// berechnet, dafür werden alle Projekte des Filters geladen und mit ListProjects ausgewählt.
+// Die Query muss vorher mit Validate geprüft worden sein.
+func FindProjects(ctx context.Context, projects ProjectStore, filter store.ProjectFilter, q ProjectQuery) (models.ProjectPage, error) {
+	if q.MinGrade != nil || q.MaxGrade != nil || strings.TrimPrefix(q.Sort, "-") == "grade" {
+		all, err := projects.FindIpaProjects(ctx, filter)
+		if err != nil {
+			return models.ProjectPage{}, err
+		}
+		return ListProjects(all, q), nil
+	}
+
+	found, total, err := projects.FindIpaProjectPage(ctx, filter, store.ProjectPage{
+		Sort:  q.Sort,
+		Skip:  (q.Page - 1) * q.PageSize,
+		Limit: q.PageSize,
+	})
+	if err != nil {
+		return models.ProjectPage{}, err
+	}
+	summaries := make([]models.ProjectSummary, len(found))
+	for i, project := range found {
+		summaries[i] = Summarize(project)
+	}
+	return models.ProjectPage{Items: summaries, Total: total, Page: q.Page, PageSize: q.PageSize}, nil
+}
+
+// ListProjects filtert die Projekte nach Note, sortiert sie und gibt die angeforderte Seite zurück.
+// Die Query muss vorher mit Validate geprüft worden sein.
+func ListProjects(projects []models.MongoIpaProject, q ProjectQuery) models.ProjectPage {
+	summaries := make([]models.ProjectSummary, 0, len(projects))
+	for _, project := range projects {
+		summary := Summarize(project)
+		if q.MinGrade != nil && summary.Grade < *q.MinGrade {
+			continue
+		}
+		if q.MaxGrade != nil && summary.Grade > *q.MaxGrade {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sortKey := q.Sort
+	if sortKey == "" {
+		sortKey = "id"
+	}
+	descending := strings.HasPrefix(sortKey, "-")
+	compare := sortFields[strings.TrimPrefix(sortKey, "-")]
+	slices.SortFunc(summaries, func(a, b models.ProjectSummary) int {
+		c := compare(a, b)
+		if c == 0 {
+			c = strings.Compare(a.ID, b.ID)
+		}
+		if descending {
+			return -c
+		}
+		return c
+	})
+
+	start := min((q.Page-1)*q.PageSize, len(summaries))
+	end := min(start+q.PageSize, len(summaries))
+	return models.ProjectPage{
+		Items:    summaries[start:end],
+		Total:    len(summaries),
+		Page:     q.Page,
+		PageSize: q.PageSize,
+	}
+}
+
+func compareFold(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
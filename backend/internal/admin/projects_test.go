@@ -0,0 +1,162 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/store"
+)
+
+func project(id string, lastname, date string, checked int) models.MongoIpaProject {
+	requirements := []string{"R1", "R2", "R3"}
+	return models.MongoIpaProject{
+		ID:       id,
+		Lastname: lastname,
+		Date:     date,
+		Criteria: []models.Criterion{{
+			ID:            "A01",
+			Requirements:  requirements,
+			Checked:       []int{1, 2, 3}[:checked],
+			QualityLevels: map[string]models.QualityLevel{"2": {MinRequirements: 2}, "1": {MinRequirements: 1}},
+		}},
+	}
+}
+
+func ptr(f float64) *float64 { return &f }
+
+func ids(page models.ProjectPage) []string {
+	result := make([]string, len(page.Items))
+	for i, item := range page.Items {
+		result[i] = item.ID
+	}
+	return result
+}
+
+func TestProjectQueryValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   ProjectQuery
+		wantErr error
+	}{
+		{"defaults", ProjectQuery{}, nil},
+		{"descending sort", ProjectQuery{Sort: "-grade"}, nil},
+		{"unknown sort field", ProjectQuery{Sort: "password"}, ErrUnknownSort},
+		{"negative page", ProjectQuery{Page: -1}, ErrInvalidPage},
+		{"page size too large", ProjectQuery{PageSize: MaxPageSize + 1}, ErrInvalidPageSize},
+		{"inverted grade range", ProjectQuery{MinGrade: ptr(5), MaxGrade: ptr(4)}, ErrGradeRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.query.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListProjects(t *testing.T) {
+	projects := []models.MongoIpaProject{
+		project("AA02", "Meier", "2025-05-01", 1),
+		project("AA00", "Zürcher", "2025-03-01", 3),
+		project("AA01", "Ammann", "2025-04-01", 0),
+	}
+	tests := []struct {
+		name      string
+		query     ProjectQuery
+		wantIDs   []string
+		wantTotal int
+	}{
+		{"default sort by id", ProjectQuery{}, []string{"AA00", "AA01", "AA02"}, 3},
+		{"sort by lastname", ProjectQuery{Sort: "lastname"}, []string{"AA01", "AA02", "AA00"}, 3},
+		{"sort by date descending", ProjectQuery{Sort: "-date"}, []string{"AA02", "AA01", "AA00"}, 3},
+		{"sort by grade descending", ProjectQuery{Sort: "-grade"}, []string{"AA00", "AA02", "AA01"}, 3},
+		{"minimum grade", ProjectQuery{MinGrade: ptr(4)}, []string{"AA00", "AA02"}, 2},
+		{"grade range", ProjectQuery{MinGrade: ptr(4), MaxGrade: ptr(5)}, []string{"AA02"}, 1},
+		{"second page", ProjectQuery{Page: 2, PageSize: 2}, []string{"AA02"}, 3},
+		{"page out of range", ProjectQuery{Page: 5, PageSize: 2}, []string{}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.query.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			got := ListProjects(projects, tt.query)
+			gotIDs := ids(got)
+			if got.Total != tt.wantTotal || len(gotIDs) != len(tt.wantIDs) {
+				t.Fatalf("ListProjects() = %v (total %d), want %v (total %d)", gotIDs, got.Total, tt.wantIDs, tt.wantTotal)
+			}
+			for i := range gotIDs {
+				if gotIDs[i] != tt.wantIDs[i] {
+					t.Errorf("ListProjects() = %v, want %v", gotIDs, tt.wantIDs)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	got := Summarize(project("AA05", "Muster", "2025-01-01", 3))
+	if got.ID != "AA05" || got.CriteriaCount != 1 || got.Part1Grade != 6 || got.Part2Grade != 6 || got.Grade != 6 {
+		t.Errorf("Summarize() = %+v", got)
+	}
+}
+
+// fakeProjectStore hält die Projekte im Speicher und merkt sich die angeforderte Seite.
+type fakeProjectStore struct {
+	projects []models.MongoIpaProject
+	page     *store.ProjectPage
+}
+
+func (f *fakeProjectStore) FindIpaProjects(context.Context, store.ProjectFilter) ([]models.MongoIpaProject, error) {
+	return f.projects, nil
+}
+
+func (f *fakeProjectStore) FindIpaProjectPage(_ context.Context, _ store.ProjectFilter, page store.ProjectPage) ([]models.MongoIpaProject, int, error) {
+	f.page = &page
+	end := min(page.Skip+page.Limit, len(f.projects))
+	return f.projects[min(page.Skip, end):end], len(f.projects), nil
+}
+
+func TestFindProjects(t *testing.T) {
+	projects := []models.MongoIpaProject{
+		project("AA00", "Zürcher", "2025-03-01", 3),
+		project("AA01", "Ammann", "2025-04-01", 0),
+		project("AA02", "Meier", "2025-05-01", 1),
+	}
+
+	db := &fakeProjectStore{projects: projects}
+	query := ProjectQuery{Sort: "-lastname", Page: 2, PageSize: 2}
+	if err := query.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	page, err := FindProjects(context.Background(), db, store.ProjectFilter{}, query)
+	if err != nil {
+		t.Fatalf("FindProjects() error = %v", err)
+	}
+	if db.page == nil || *db.page != (store.ProjectPage{Sort: "-lastname", Skip: 2, Limit: 2}) {
+		t.Errorf("FindIpaProjectPage() called with %+v, want sort, skip and limit of page 2", db.page)
+	}
+	if got := ids(page); len(got) != 1 || got[0] != "AA02" || page.Total != 3 || page.Page != 2 || page.Items[0].Grade == 0 {
+		t.Errorf("FindProjects() = %+v, want summary of AA02 of 3", page)
+	}
+
+	// Die Note wird aus den Kriterien berechnet, Filter und Sortierung danach erfolgen im Speicher
+	db = &fakeProjectStore{projects: projects}
+	query = ProjectQuery{Sort: "-grade", MinGrade: ptr(4)}
+	if err := query.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	page, err = FindProjects(context.Background(), db, store.ProjectFilter{}, query)
+	if err != nil {
+		t.Fatalf("FindProjects() error = %v", err)
+	}
+	if db.page != nil {
+		t.Errorf("FindIpaProjectPage() called for a grade query")
+	}
+	if got := ids(page); len(got) != 2 || got[0] != "AA00" || got[1] != "AA02" || page.Total != 2 {
+		t.Errorf("FindProjects() = %v (total %d), want [AA00 AA02] (total 2)", got, page.Total)
+	}
+}
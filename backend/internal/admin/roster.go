@@ -0,0 +1,240 @@
+package admin
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// RosterEntry ist eine Zeile der Klassenliste.
+type RosterEntry struct {
+	Row       int // Zeilennummer in der CSV-Datei, die Kopfzeile ist Zeile 1
+	Firstname string
+	Lastname  string
+	Topic     string
+	Date      string // YYYY-MM-DD
+}
+
+var (
+	ErrRosterEmpty      = errors.New("class roster is empty")
+	ErrRosterNoEntries  = errors.New("class roster contains no entries")
+	ErrInvalidCSV       = errors.New("invalid CSV")
+	ErrMissingColumn    = errors.New("missing column %q")
+	ErrFirstnameMissing = errors.New("firstname is missing")
+	ErrLastnameMissing  = errors.New("lastname is missing")
+	ErrDateMissing      = errors.New("date is missing")
+	ErrInvalidDate      = errors.New("invalid date %q")
+	ErrDuplicateRow     = errors.New("duplicate of row %d")
+)
+
+// ContentError ist ein Fehler im Inhalt einer hochgeladenen Datei oder einer Anfrage. Der Text
+// von Err ist ein Format, in das Args eingesetzt werden, etwa die Nummer einer anderen Zeile.
+// Die API setzt Args ebenso in die übersetzte Meldung ein.
+type ContentError struct {
+	Err  error
+	Args []any
+}
+
+// NewContentError erstellt einen ContentError zum Fehler err.
+func NewContentError(err error, args ...any) *ContentError {
+	return &ContentError{Err: err, Args: args}
+}
+
+func (e *ContentError) Error() string {
+	return fmt.Sprintf(e.Err.Error(), e.Args...)
+}
+
+func (e *ContentError) Unwrap() error {
+	return e.Err
+}
+
+// RowError beschreibt die Fehler in einer Zeile der Klassenliste oder einer Tabelle. Message
+// fasst Problems auf Englisch zusammen, die API ersetzt sie durch die Übersetzung.
+type RowError struct {
+	Row      int     `json:"row"`
+	Message  string  `json:"message"`
+	Problems []error `json:"-"`
+}
+
+// NewRowError erstellt den RowError für die Fehler problems in der Zeile row.
+func NewRowError(row int, problems []error) RowError {
+	messages := make([]string, len(problems))
+	for i, problem := range problems {
+		messages[i] = problem.Error()
+	}
+	return RowError{Row: row, Message: strings.Join(messages, "; "), Problems: problems}
+}
+
+// RosterError enthält alle Fehler, die beim Prüfen der Klassenliste gefunden wurden.
+type RosterError struct {
+	Rows []RowError
+}
+
+func (e *RosterError) Error() string {
+	return fmt.Sprintf("class roster contains %d invalid rows", len(e.Rows))
+}
+
+// rosterColumns ordnet die akzeptierten Spaltennamen den Feldern zu.
+var rosterColumns = map[string]string{
+	"firstname": "firstname", "vorname": "firstname",
+	"lastname": "lastname", "nachname": "lastname", "name": "lastname",
+	"topic": "topic", "thema": "topic",
+	"date": "date", "datum": "date",
+}
+
+// ParseRoster liest eine Klassenliste im CSV-Format mit den Spalten firstname,
+// lastname, topic und date. Als Trennzeichen werden Komma und Semikolon
+// akzeptiert, Datumswerte im Format YYYY-MM-DD oder DD.MM.YYYY.
+// Die gesamte Datei wird geprüft, bevor ein Fehler zurückgegeben wird. Fehler im Inhalt der
+// Datei enthalten einen der Err-Fehler dieses Pakets.
+func ParseRoster(r io.Reader) ([]RosterEntry, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimPrefix(string(content), "\ufeff") // BOM von Excel entfernen
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = detectDelimiter(text)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var records [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCSV, err)
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+	if len(records) == 0 {
+		return nil, ErrRosterEmpty
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		if field, ok := rosterColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[field] = i
+		}
+	}
+	for _, field := range []string{"firstname", "lastname", "topic", "date"} {
+		if _, ok := columns[field]; !ok {
+			return nil, NewContentError(ErrMissingColumn, field)
+		}
+	}
+
+	entries := make([]RosterEntry, 0, len(records)-1)
+	rosterErr := &RosterError{}
+	seen := make(map[string]int)
+	for i, record := range records[1:] {
+		row := lines[i+1]
+		if isBlank(record) {
+			continue
+		}
+		value := func(field string) string {
+			if idx := columns[field]; idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+		entry := RosterEntry{
+			Row:       row,
+			Firstname: value("firstname"),
+			Lastname:  value("lastname"),
+			Topic:     value("topic"),
+		}
+
+		var problems []error
+		if entry.Firstname == "" {
+			problems = append(problems, ErrFirstnameMissing)
+		}
+		if entry.Lastname == "" {
+			problems = append(problems, ErrLastnameMissing)
+		}
+		date, err := normalizeDate(value("date"))
+		if err != nil {
+			problems = append(problems, err)
+		}
+		entry.Date = date
+		key := strings.ToLower(entry.Firstname + "\x00" + entry.Lastname)
+		if first, ok := seen[key]; ok && entry.Firstname != "" && entry.Lastname != "" {
+			problems = append(problems, NewContentError(ErrDuplicateRow, first))
+		} else {
+			seen[key] = row
+		}
+
+		if len(problems) > 0 {
+			rosterErr.Rows = append(rosterErr.Rows, NewRowError(row, problems))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(rosterErr.Rows) > 0 {
+		return nil, rosterErr
+	}
+	if len(entries) == 0 {
+		return nil, ErrRosterNoEntries
+	}
+	return entries, nil
+}
+
+func detectDelimiter(text string) rune {
+	header, _, _ := strings.Cut(text, "\n")
+	if strings.Count(header, ";") > strings.Count(header, ",") {
+		return ';'
+	}
+	return ','
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeDate(value string) (string, error) {
+	if value == "" {
+		return "", ErrDateMissing
+	}
+	for _, layout := range []string{time.DateOnly, "02.01.2006", "2.1.2006"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date.Format(time.DateOnly), nil
+		}
+	}
+	return "", NewContentError(ErrInvalidDate, value)
+}
+
+// passwordAlphabet enthält keine leicht verwechselbaren Zeichen wie 0/O oder 1/l/I.
+const passwordAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// InitialPasswordLength ist die Länge der generierten Einmal-Passwörter.
+const InitialPasswordLength = 10
+
+// GeneratePassword erzeugt ein zufälliges Einmal-Passwort für den ersten Login.
+func GeneratePassword() (string, error) {
+	password := make([]byte, InitialPasswordLength)
+	limit := big.NewInt(int64(len(passwordAlphabet)))
+	for i := range password {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		password[i] = passwordAlphabet[n.Int64()]
+	}
+	return string(password), nil
+}
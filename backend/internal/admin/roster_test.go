@@ -0,0 +1,89 @@
+package admin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseRoster(t *testing.T) {
+	tests := []struct {
+		name     string
+		csv      string
+		want     []RosterEntry
+		wantErr  bool
+		wantRows []int
+	}{
+		{"comma separated",
+			"firstname,lastname,topic,date\nAnna,Muster,Webshop,2025-05-12\n",
+			[]RosterEntry{{Row: 2, Firstname: "Anna", Lastname: "Muster", Topic: "Webshop", Date: "2025-05-12"}},
+			false, nil},
+		{"semicolon separated with german headers and swiss dates",
+			"\ufeffVorname;Nachname;Thema;Datum\nBeat;Meier;API;12.05.2025\n\nCarla;Rossi;;1.6.2025\n",
+			[]RosterEntry{
+				{Row: 2, Firstname: "Beat", Lastname: "Meier", Topic: "API", Date: "2025-05-12"},
+				{Row: 4, Firstname: "Carla", Lastname: "Rossi", Topic: "", Date: "2025-06-01"},
+			},
+			false, nil},
+		{"columns in different order",
+			"date,topic,lastname,firstname\n2025-05-12,Webshop,Muster,Anna\n",
+			[]RosterEntry{{Row: 2, Firstname: "Anna", Lastname: "Muster", Topic: "Webshop", Date: "2025-05-12"}},
+			false, nil},
+		{"missing column", "firstname,lastname,topic\nAnna,Muster,Webshop\n", nil, true, nil},
+		{"empty file", "", nil, true, nil},
+		{"header only", "firstname,lastname,topic,date\n", nil, true, nil},
+		{"all invalid rows are reported",
+			"firstname,lastname,topic,date\n,Muster,Webshop,2025-05-12\nAnna,Muster,Webshop,morgen\nBeat,Meier,API,2025-05-12\nbeat,meier,API,2025-05-12\n",
+			nil, true, []int{2, 3, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRoster(strings.NewReader(tt.csv))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRoster() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantRows != nil {
+				var rosterErr *RosterError
+				if !errors.As(err, &rosterErr) {
+					t.Fatalf("ParseRoster() error = %v, want *RosterError", err)
+				}
+				if len(rosterErr.Rows) != len(tt.wantRows) {
+					t.Fatalf("ParseRoster() row errors = %+v, want rows %v", rosterErr.Rows, tt.wantRows)
+				}
+				for i, row := range tt.wantRows {
+					if rosterErr.Rows[i].Row != row {
+						t.Errorf("ParseRoster() row errors = %+v, want rows %v", rosterErr.Rows, tt.wantRows)
+					}
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseRoster() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseRoster()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 50 {
+		password, err := GeneratePassword()
+		if err != nil {
+			t.Fatalf("GeneratePassword() error = %v", err)
+		}
+		if len(password) != InitialPasswordLength {
+			t.Errorf("GeneratePassword() = %q, want length %d", password, InitialPasswordLength)
+		}
+		if strings.ContainsAny(password, "0O1lI") {
+			t.Errorf("GeneratePassword() = %q contains ambiguous characters", password)
+		}
+		if seen[password] {
+			t.Errorf("GeneratePassword() returned %q twice", password)
+		}
+		seen[password] = true
+	}
+}
@@ -0,0 +1,576 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/admin"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/archive"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/cohort"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/store"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxRosterSize begrenzt die Grösse einer hochgeladenen Klassenliste.
+	maxRosterSize = 1 << 20
+	// maxArchiveSize begrenzt die Grösse eines hochgeladenen Projektarchivs.
+	maxArchiveSize = 64 << 20
+	// maxFormOverhead ist der Platz für die Formularfelder neben einer hochgeladenen Datei.
+	maxFormOverhead = 1 << 20
+)
+
+// listProjectsQuery beschreibt die Query-Parameter von GET /api/admin/projects.
+type listProjectsQuery struct {
+	From     string   `form:"from"` // Abgabetag, YYYY-MM-DD
+	To       string   `form:"to"`   // Abgabetag, YYYY-MM-DD
+	Topic    string   `form:"topic"`
+	MinGrade *float64 `form:"minGrade"`
+	MaxGrade *float64 `form:"maxGrade"`
+	Sort     string   `form:"sort"`
+	Page     int      `form:"page"`
+	PageSize int      `form:"pageSize"`
+	Archived bool     `form:"archived"` // Archivierte Projekte einschliessen
+}
+
+// ListIpaProjectsHandler listet alle IPA-Projekte mit Filter, Sortierung und Paginierung.
+func (h *Handlers) ListIpaProjectsHandler(c *gin.Context) {
+	var params listProjectsQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidRequest, localize(c, msgInvalidQuery))
+		return
+	}
+	for _, date := range []string{params.From, params.To} {
+		if _, err := time.Parse(time.DateOnly, date); date != "" && err != nil {
+			respondProblem(c, http.StatusBadRequest, CodeInvalidRequest, localize(c, msgInvalidDate, date))
+			return
+		}
+	}
+
+	query := admin.ProjectQuery{
+		MinGrade: params.MinGrade,
+		MaxGrade: params.MaxGrade,
+		Sort:     params.Sort,
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	}
+	if err := query.Validate(); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidRequest, localize(c, msgInvalidQuery)+": "+contentDetail(c, err, queryMessages))
+		return
+	}
+
+	page, err := admin.FindProjects(c.Request.Context(), h.MongoStore, store.ProjectFilter{
+		DateFrom:        params.From,
+		DateTo:          params.To,
+		Topic:           params.Topic,
+		IncludeArchived: params.Archived,
+	}, query)
+	if err != nil {
+		respondInternalError(c, "listing ipa projects failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// statisticsQuery beschreibt die Query-Parameter von GET /api/admin/statistics.
+type statisticsQuery struct {
+	IDs      string `form:"ids"`  // Kommagetrennte Projekt-IDs, ersetzt die übrigen Filter
+	From     string `form:"from"` // Abgabetag, YYYY-MM-DD
+	To       string `form:"to"`   // Abgabetag, YYYY-MM-DD
+	Topic    string `form:"topic"`
+	Archived bool   `form:"archived"` // Archivierte Projekte einschliessen
+	Top      int    `form:"top"`      // Anzahl der am häufigsten nicht erfüllten Anforderungen
+	Format   string `form:"format"`   // json oder csv
+	Table    string `form:"table"`    // Tabelle der CSV-Ausgabe
+}
+
+// GetStatisticsHandler wertet die Bewertung der ausgewählten Projekte aus: Notenverteilung je
+// Teil, Gütestufen und Wahlhäufigkeit je Kriterium und die am häufigsten nicht erfüllten
+// Anforderungen. Mit ?format=csv wird eine Tabelle (?table=grades|criteria|requirements) als
+// CSV geliefert.
+func (h *Handlers) GetStatisticsHandler(c *gin.Context) {
+	params := statisticsQuery{Top: cohort.DefaultTop, Format: "json", Table: cohort.TableCriteria}
+	if err := c.ShouldBindQuery(&params); err != nil || params.Top < 1 {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidRequest, localize(c, msgInvalidQuery))
+		return
+	}
+	if params.Format != "json" && params.Format != "csv" {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidRequest, localize(c, msgInvalidFormat, params.Format))
+		return
+	}
+	if !slices.Contains([]string{cohort.TableGrades, cohort.TableCriteria, cohort.TableRequirements}, params.Table) {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidRequest, localize(c, msgUnknownTable, params.Table))
+		return
+	}
+	for _, date := range []string{params.From, params.To} {
+		if _, err := time.Parse(time.DateOnly, date); date != "" && err != nil {
+			respondProblem(c, http.StatusBadRequest, CodeInvalidRequest, localize(c, msgInvalidDate, date))
+			return
+		}
+	}
+
+	var projects []models.MongoIpaProject
+	var err error
+	if ids := parseProjectIDs(params.IDs); len(ids) > 0 {
+		projects, err = h.MongoStore.GetIpaProjectsByIDs(c.Request.Context(), ids)
+		if missing := missingProjectIDs(ids, projects); err == nil && len(missing) > 0 {
+			respondProblem(c, http.StatusNotFound, CodeProjectNotFound, localize(c, msgProjectsMissing, strings.Join(missing, ", ")))
+			return
+		}
+	} else {
+		projects, err = h.MongoStore.FindIpaProjects(c.Request.Context(), store.ProjectFilter{
+			DateFrom:        params.From,
+			DateTo:          params.To,
+			Topic:           params.Topic,
+			IncludeArchived: params.Archived,
+		})
+	}
+	if err != nil {
+		respondInternalError(c, "retrieving ipa projects for statistics failed", err)
+		return
+	}
+
+	lang := requestLanguage(c)
+	for i := range projects {
+		projects[i].Criteria = h.JsonStore.LocalizeCriteria(projects[i].Criteria, lang)
+	}
+	stats := cohort.Build(projects, params.Top)
+	if params.Format == "json" {
+		c.JSON(http.StatusOK, stats)
+		return
+	}
+
+	filename := fmt.Sprintf("ipa-statistics-%s-%s.csv", params.Table, time.Now().UTC().Format("20060102-150405"))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := cohort.WriteCSV(c.Writer, stats, params.Table); err != nil {
+		requestLogger(c).Error("writing statistics failed", "error", err)
+	}
+}
+
+// ArchiveIpaProjectHandler archiviert ein IPA-Projekt.
+func (h *Handlers) ArchiveIpaProjectHandler(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// UnarchiveIpaProjectHandler stellt ein archiviertes IPA-Projekt wieder her.
+func (h *Handlers) UnarchiveIpaProjectHandler(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+func (h *Handlers) setArchived(c *gin.Context, archived bool) {
+	personId := c.Param("id")
+	if _, err := h.MongoStore.SetIpaProjectArchived(c.Request.Context(), personId, archived); err != nil {
+		respondStoreError(c, "changing archive status failed", err, CodeProjectNotFound)
+		return
+	}
+	requestLogger(c).Info("ipa project archive status changed", "archived", archived)
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteIpaProjectHandler löscht ein IPA-Projekt endgültig, samt den Dateien seiner Belege.
+func (h *Handlers) DeleteIpaProjectHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	if _, err := h.MongoStore.DeleteIpaProject(c.Request.Context(), project.ID); err != nil {
+		respondStoreError(c, "deleting ipa project failed", err, CodeProjectNotFound)
+		return
+	}
+	h.deleteEvidenceFiles(c, project.Evidence)
+	requestLogger(c).Info("ipa project deleted")
+	c.Status(http.StatusNoContent)
+}
+
+// IssueExpertTokenHandler stellt einer Expertin oder einem Experten einen Token für ein Projekt
+// aus. Mit dem Token als Bearer-Token kommentieren sie unter ihrem Namen.
+func (h *Handlers) IssueExpertTokenHandler(c *gin.Context) {
+	var req models.ExpertTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidRequest, localize(c, msgInvalidFields, "name"))
+		return
+	}
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+
+	token, err := GenerateExpertToken(project.ID, name)
+	if err != nil {
+		respondInternalError(c, "generating expert token failed", err)
+		return
+	}
+	requestLogger(c).Info("expert token issued", "expert", name)
+	c.JSON(http.StatusCreated, models.ExpertToken{
+		ProjectID: project.ID,
+		Name:      name,
+		Token:     token,
+		ExpiresAt: time.Now().Add(TokenValidityDuration).UTC().Truncate(time.Second),
+	})
+}
+
+// ImportRosterHandler erstellt für jede Zeile einer Klassenliste (CSV) ein IPA-Projekt mit
+// den Pflichtkriterien und einem Einmal-Passwort. Die Liste wird vollständig geprüft,
+// bevor ein Projekt angelegt wird; schlägt das Speichern fehl, wird nichts importiert.
+// Mit ?format=html wird ein druckbares Zugangsdatenblatt zurückgegeben.
+func (h *Handlers) ImportRosterHandler(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxRosterSize+maxFormOverhead)
+	var input io.Reader = c.Request.Body
+	file, err := c.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		respondProblem(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "")
+		return
+	}
+	if err == nil {
+		if file.Size > maxRosterSize {
+			respondProblem(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "")
+			return
+		}
+		opened, err := file.Open()
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, CodeInvalidRoster, localize(c, msgUnreadableFile))
+			return
+		}
+		defer opened.Close()
+		input = opened
+	}
+
+	data, err := io.ReadAll(input)
+	if errors.As(err, &maxBytesErr) || len(data) > maxRosterSize {
+		respondProblem(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "")
+		return
+	}
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidRoster, localize(c, msgUnreadableFile))
+		return
+	}
+
+	entries, err := admin.ParseRoster(bytes.NewReader(data))
+	var rosterErr *admin.RosterError
+	if errors.As(err, &rosterErr) {
+		problem := newProblem(c, http.StatusBadRequest, CodeInvalidRoster, localize(c, msgInvalidRosterRows))
+		problem.Rows = localizedRows(c, rosterErr.Rows, rosterMessages)
+		writeProblem(c, problem)
+		return
+	}
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidRoster, contentDetail(c, err, rosterMessages))
+		return
+	}
+
+	projects := make([]models.MongoIpaProject, len(entries))
+	credentials := make([]models.ProjectCredentials, len(entries))
+	for i, entry := range entries {
+		password, err := admin.GeneratePassword()
+		if err != nil {
+			respondInternalError(c, "generating password failed", err)
+			return
+		}
+		hashedPassword, err := HashPassword(password)
+		if err != nil {
+			respondInternalError(c, "hashing password failed", err)
+			return
+		}
+		projects[i] = models.MongoIpaProject{
+			Firstname:              entry.Firstname,
+			Lastname:               entry.Lastname,
+			Topic:                  entry.Topic,
+			Date:                   entry.Date,
+			PasswordHash:           hashedPassword,
+			PasswordChangeRequired: true,
+			CatalogueVersion:       h.JsonStore.GetVersion(),
+			Criteria:               h.JsonStore.GetMandatoryCriteria(i18n.Default),
+		}
+		credentials[i] = models.ProjectCredentials{
+			Firstname: entry.Firstname,
+			Lastname:  entry.Lastname,
+			Topic:     entry.Topic,
+			Date:      entry.Date,
+			Password:  password,
+		}
+	}
+
+	ids, err := h.MongoStore.CreateIpaProjects(c.Request.Context(), projects)
+	if err != nil {
+		respondStoreError(c, "importing class roster failed", err, CodeProjectNotFound)
+		return
+	}
+	for i, id := range ids {
+		credentials[i].ID = id
+	}
+	requestLogger(c).Info("class roster imported", "projects", len(projects))
+
+	if c.Query("format") == "html" {
+		c.Status(http.StatusCreated)
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		if err := admin.WriteCredentialsSheet(c.Writer, credentials); err != nil {
+			requestLogger(c).Error("writing credentials sheet failed", "error", err)
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"projects": credentials})
+}
+
+// ExportProjectsHandler exportiert die Projekte mit den IDs aus ?ids=AA01,AA02 (ohne Angabe alle)
+// als JSON- oder mit ?format=zip als ZIP-Archiv. Passwort-Hashes werden nur mit
+// ?includePasswords=true exportiert.
+func (h *Handlers) ExportProjectsHandler(c *gin.Context) {
+	ids := parseProjectIDs(c.Query("ids"))
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidRequest, localize(c, msgInvalidFormat, format))
+		return
+	}
+
+	projects, err := h.MongoStore.GetIpaProjectsByIDs(c.Request.Context(), ids)
+	if err != nil {
+		respondInternalError(c, "retrieving ipa projects for export failed", err)
+		return
+	}
+	if missing := missingProjectIDs(ids, projects); len(missing) > 0 {
+		respondProblem(c, http.StatusNotFound, CodeProjectNotFound, localize(c, msgProjectsMissing, strings.Join(missing, ", ")))
+		return
+	}
+
+	a := archive.New(projects, h.JsonStore.GetVersion(), c.Query("includePasswords") == "true")
+	filename := fmt.Sprintf("ipa-projects-%s.%s", a.ExportedAt.Format("20060102-150405"), format)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+	if format == "zip" {
+		c.Header("Content-Type", "application/zip")
+		err = a.WriteZIP(c.Writer)
+	} else {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		err = a.WriteJSON(c.Writer)
+	}
+	if err != nil {
+		requestLogger(c).Error("writing project archive failed", "error", err)
+		return
+	}
+	requestLogger(c).Info("ipa projects exported", "projects", len(projects), "passwords_included", a.IncludesPasswords)
+}
+
+// ImportProjectsHandler importiert ein Projektarchiv (JSON oder ZIP). Kollidierende IDs
+// werden neu vergeben und im Bericht ausgewiesen.
+func (h *Handlers) ImportProjectsHandler(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxArchiveSize+maxFormOverhead)
+	var input io.Reader = c.Request.Body
+	file, err := c.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		respondProblem(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "")
+		return
+	}
+	if err == nil {
+		if file.Size > maxArchiveSize {
+			respondProblem(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "")
+			return
+		}
+		opened, err := file.Open()
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, CodeInvalidArchive, localize(c, msgUnreadableFile))
+			return
+		}
+		defer opened.Close()
+		input = opened
+	}
+	data, err := io.ReadAll(input)
+	if errors.As(err, &maxBytesErr) || len(data) > maxArchiveSize {
+		respondProblem(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "")
+		return
+	}
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidArchive, localize(c, msgUnreadableFile))
+		return
+	}
+
+	a, err := archive.Read(data)
+	if err != nil {
+		requestLogger(c).Info("rejected project archive", "error", err)
+		respondProblem(c, http.StatusBadRequest, CodeInvalidArchive, archiveDetail(c, err))
+		return
+	}
+
+	importer := archive.Importer{
+		Store:            h.MongoStore,
+		IDScheme:         h.ProjectIDScheme,
+		CatalogueVersion: h.JsonStore.GetVersion(),
+		HashPassword:     HashPassword,
+	}
+	report, err := importer.Import(c.Request.Context(), a)
+	if err != nil {
+		respondStoreError(c, "importing project archive failed", err, CodeProjectNotFound)
+		return
+	}
+	requestLogger(c).Info("project archive imported", "projects", len(report.Projects), "new_ids", report.Conflicts)
+	c.JSON(http.StatusCreated, report)
+}
+
+// ReloadCatalogueHandler lädt die Kriteriendatei neu. Ist die Datei ungültig, bleibt der
+// bisherige Katalog aktiv.
+func (h *Handlers) ReloadCatalogueHandler(c *gin.Context) {
+	if err := h.JsonStore.Reload(); err != nil {
+		requestLogger(c).Error("reloading criteria catalogue failed", "error", err)
+		var detail string
+		if errors.Is(err, store.ErrValidation) {
+			detail = err.Error() // Nur Fehler im Inhalt der Datei, keine Pfade oder Systemfehler
+		}
+		respondProblem(c, http.StatusUnprocessableEntity, CodeInvalidCatalogue, detail)
+		return
+	}
+	requestLogger(c).Info("criteria catalogue reloaded", "version", h.JsonStore.GetVersion())
+	c.JSON(http.StatusOK, gin.H{"version": h.JsonStore.GetVersion(), "criteria": len(h.JsonStore.GetAllCriteria(i18n.Default))})
+}
+
+// parseProjectIDs liest kommagetrennte Projekt-IDs. Die IDs werden normalisiert, leere und
+// doppelte Angaben entfallen.
+func parseProjectIDs(param string) []string {
+	var ids []string
+	for id := range strings.SplitSeq(param, ",") {
+		if id = common.NormalizeProjectID(id); id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// missingProjectIDs liefert die IDs aus ids, zu denen projects kein Projekt enthält.
+func missingProjectIDs(ids []string, projects []models.MongoIpaProject) []string {
+	var missing []string
+	for _, id := range ids {
+		if !slices.ContainsFunc(projects, func(project models.MongoIpaProject) bool { return project.ID == id }) {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// archiveDetail beschreibt einen Fehler aus archive.Read in der Sprache der Anfrage.
+func archiveDetail(c *gin.Context, err error) string {
+	for cause, msg := range archiveMessages {
+		if errors.Is(err, cause) {
+			return localize(c, msg)
+		}
+	}
+	return ""
+}
+
+// archiveMessages enthält die Meldungen zu den Fehlern aus archive.Read.
+var archiveMessages = map[error]i18n.Text{
+	archive.ErrInvalid: {
+		i18n.German:  "Die Datei ist kein gültiges Projektarchiv",
+		i18n.French:  "Le fichier n'est pas une archive de projets valide",
+		i18n.Italian: "Il file non è un archivio di progetti valido",
+	},
+	archive.ErrUnsupportedVersion: {
+		i18n.German:  "Die Version des Archivformats wird nicht unterstützt",
+		i18n.French:  "La version du format d'archive n'est pas prise en charge",
+		i18n.Italian: "La versione del formato dell'archivio non è supportata",
+	},
+	archive.ErrManifestMissing: {
+		i18n.German:  "Im ZIP-Archiv fehlt manifest.json",
+		i18n.French:  "Le fichier manifest.json manque dans l'archive ZIP",
+		i18n.Italian: "Nell'archivio ZIP manca manifest.json",
+	},
+	archive.ErrManifestMismatch: {
+		i18n.German:  "Die Anzahl Projekte im ZIP-Archiv stimmt nicht mit manifest.json überein",
+		i18n.French:  "Le nombre de projets dans l'archive ZIP ne correspond pas à manifest.json",
+		i18n.Italian: "Il numero di progetti nell'archivio ZIP non corrisponde a manifest.json",
+	},
+	archive.ErrEntryTooLarge: {
+		i18n.German:  "Eine Datei im ZIP-Archiv ist zu gross",
+		i18n.French:  "Un fichier de l'archive ZIP est trop volumineux",
+		i18n.Italian: "Un file nell'archivio ZIP è troppo grande",
+	},
+}
+
+// queryMessages enthält die Meldungen zu den Fehlern aus admin.ProjectQuery.Validate.
+var queryMessages = map[error]i18n.Text{
+	admin.ErrInvalidPage: {
+		i18n.German:  "page muss mindestens 1 sein",
+		i18n.French:  "page doit valoir au moins 1",
+		i18n.Italian: "page deve essere almeno 1",
+	},
+	admin.ErrInvalidPageSize: {
+		i18n.German:  "pageSize muss zwischen 1 und %d liegen",
+		i18n.French:  "pageSize doit être compris entre 1 et %d",
+		i18n.Italian: "pageSize deve essere compreso tra 1 e %d",
+	},
+	admin.ErrGradeRange: {
+		i18n.German:  "minGrade darf nicht grösser als maxGrade sein",
+		i18n.French:  "minGrade ne doit pas être supérieur à maxGrade",
+		i18n.Italian: "minGrade non può essere maggiore di maxGrade",
+	},
+	admin.ErrUnknownSort: {
+		i18n.German:  "Unbekanntes Sortierfeld %q",
+		i18n.French:  "Champ de tri inconnu %q",
+		i18n.Italian: "Campo di ordinamento sconosciuto %q",
+	},
+}
+
+// rosterMessages enthält die Meldungen zu den Fehlern aus admin.ParseRoster.
+var rosterMessages = map[error]i18n.Text{
+	admin.ErrRosterEmpty: {
+		i18n.German:  "Die Klassenliste ist leer",
+		i18n.French:  "La liste de classe est vide",
+		i18n.Italian: "L'elenco della classe è vuoto",
+	},
+	admin.ErrRosterNoEntries: {
+		i18n.German:  "Die Klassenliste enthält keine Einträge",
+		i18n.French:  "La liste de classe ne contient aucune entrée",
+		i18n.Italian: "L'elenco della classe non contiene voci",
+	},
+	admin.ErrInvalidCSV: {
+		i18n.German:  "Die Datei ist keine gültige CSV-Datei",
+		i18n.French:  "Le fichier n'est pas un fichier CSV valide",
+		i18n.Italian: "Il file non è un file CSV valido",
+	},
+	admin.ErrMissingColumn: {
+		i18n.German:  "Die Spalte %q fehlt",
+		i18n.French:  "La colonne %q est manquante",
+		i18n.Italian: "Manca la colonna %q",
+	},
+	admin.ErrFirstnameMissing: {
+		i18n.German:  "Der Vorname fehlt",
+		i18n.French:  "Le prénom est manquant",
+		i18n.Italian: "Manca il nome",
+	},
+	admin.ErrLastnameMissing: {
+		i18n.German:  "Der Nachname fehlt",
+		i18n.French:  "Le nom est manquant",
+		i18n.Italian: "Manca il cognome",
+	},
+	admin.ErrDateMissing: {
+		i18n.German:  "Das Datum fehlt",
+		i18n.French:  "La date est manquante",
+		i18n.Italian: "Manca la data",
+	},
+	admin.ErrInvalidDate: {
+		i18n.German:  "Ungültiges Datum %q",
+		i18n.French:  "Date invalide %q",
+		i18n.Italian: "Data non valida %q",
+	},
+	admin.ErrDuplicateRow: {
+		i18n.German:  "Doppelter Eintrag, siehe Zeile %d",
+		i18n.French:  "Entrée en double, voir ligne %d",
+		i18n.Italian: "Voce duplicata, vedi riga %d",
+	},
+}
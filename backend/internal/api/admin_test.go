@@ -0,0 +1,19 @@
+package api
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+func TestMissingProjectIDs(t *testing.T) {
+	ids := parseProjectIDs("aa01, AA01,,K7QX-2M3,BB02")
+	if want := []string{"AA01", "K7QX2M3", "BB02"}; !slices.Equal(ids, want) {
+		t.Fatalf("parseProjectIDs() = %v, want %v", ids, want)
+	}
+	projects := []models.MongoIpaProject{{ID: "AA01"}, {ID: "K7QX2M3"}}
+	if missing := missingProjectIDs(ids, projects); !slices.Equal(missing, []string{"BB02"}) {
+		t.Errorf("missingProjectIDs() = %v, want [BB02]", missing)
+	}
+}
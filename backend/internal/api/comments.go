@@ -0,0 +1,229 @@
+package api
+
+import (
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/comment"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetCommentSummaryHandler zählt die offenen Diskussionen und die ungelesenen Kommentare pro Kriterium.
+func (h *Handlers) GetCommentSummaryHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	c.JSON(http.StatusOK, comment.Summary(*project, currentUser(c, *project)))
+}
+
+// ListCommentsHandler liefert die Diskussionen zu einem Kriterium.
+func (h *Handlers) ListCommentsHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	criterionId := c.Param("criteriaId")
+	if !slices.ContainsFunc(project.Criteria, func(criterion models.Criterion) bool { return criterion.ID == criterionId }) {
+		respondProblem(c, http.StatusNotFound, CodeCriterionNotFound, "")
+		return
+	}
+	c.JSON(http.StatusOK, comment.Threads(*project, criterionId, currentUser(c, *project)))
+}
+
+// CreateCommentHandler eröffnet eine Diskussion zu einem Kriterium oder antwortet in einer Diskussion.
+func (h *Handlers) CreateCommentHandler(c *gin.Context) {
+	var input models.CommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+
+	criterionId := c.Param("criteriaId")
+	if err := comment.Validate(input, criterionId, *project); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidComment, commentDetail(c, err))
+		return
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond) // Genauigkeit von MongoDB
+	item := comment.New(rand.Text(), input, criterionId, currentUser(c, *project), now)
+
+	if _, err := h.MongoStore.AddComment(c.Request.Context(), project.ID, item); err != nil {
+		respondStoreError(c, "adding comment failed", err, CodeProjectNotFound)
+		return
+	}
+	c.JSON(http.StatusCreated, models.CommentView{Comment: item, Own: true, Read: true})
+}
+
+// UpdateCommentHandler ändert den Text eines eigenen Kommentars. Für die anderen Benutzer gilt
+// der Kommentar danach wieder als ungelesen.
+func (h *Handlers) UpdateCommentHandler(c *gin.Context) {
+	var input models.CommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+	project, item, ok := h.ownComment(c)
+	if !ok {
+		return
+	}
+	if err := comment.ValidateText(input.Text); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidComment, commentDetail(c, err))
+		return
+	}
+
+	item.Text = input.Text
+	item.UpdatedAt = time.Now().UTC().Truncate(time.Millisecond)
+	item.ReadBy = []string{item.AuthorKey}
+	if _, err := h.MongoStore.EditComment(c.Request.Context(), project.ID, item.ID, item.Text, item.UpdatedAt, item.AuthorKey); err != nil {
+		respondStoreError(c, "updating comment failed", err, CodeCommentNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, models.CommentView{Comment: item, Own: true, Read: true})
+}
+
+// DeleteCommentHandler löscht den Text eines eigenen Kommentars. Der Kommentar bleibt als
+// gelöscht markiert erhalten, damit die Antworten der Diskussion verständlich bleiben.
+func (h *Handlers) DeleteCommentHandler(c *gin.Context) {
+	project, item, ok := h.ownComment(c)
+	if !ok {
+		return
+	}
+
+	item.Text = ""
+	item.Deleted = true
+	item.UpdatedAt = time.Now().UTC().Truncate(time.Millisecond)
+	if _, err := h.MongoStore.DeleteComment(c.Request.Context(), project.ID, item.ID, item.UpdatedAt); err != nil {
+		respondStoreError(c, "deleting comment failed", err, CodeCommentNotFound)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// UpdateThreadStatusHandler markiert eine Diskussion als erledigt oder wieder als offen.
+func (h *Handlers) UpdateThreadStatusHandler(c *gin.Context) {
+	var input models.ThreadStatusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	item, ok := findComment(c, *project)
+	if !ok {
+		return
+	}
+	if item.ID != item.ThreadID {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidComment, commentDetail(c, comment.ErrNotThread))
+		return
+	}
+
+	item.Resolved = input.Resolved
+	if _, err := h.MongoStore.ResolveThread(c.Request.Context(), project.ID, item.ID, item.Resolved); err != nil {
+		respondStoreError(c, "updating thread status failed", err, CodeCommentNotFound)
+		return
+	}
+	user := currentUser(c, *project)
+	c.JSON(http.StatusOK, models.CommentView{
+		Comment: item,
+		Own:     item.AuthorKey == user.Key(),
+		Read:    item.AuthorKey == user.Key() || slices.Contains(item.ReadBy, user.Key()),
+	})
+}
+
+// MarkCommentsReadHandler vermerkt alle Kommentare zu einem Kriterium als gelesen.
+func (h *Handlers) MarkCommentsReadHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	criterionId := c.Param("criteriaId")
+	if !comment.HasComments(*project, criterionId) {
+		c.Status(http.StatusNoContent) // Nichts zu lesen
+		return
+	}
+
+	user := currentUser(c, *project)
+	if _, err := h.MongoStore.MarkCommentsRead(c.Request.Context(), project.ID, criterionId, user.Key()); err != nil {
+		respondStoreError(c, "marking comments as read failed", err, CodeProjectNotFound)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ownComment lädt den Kommentar aus der URL und prüft, ob ihn der anfragende Benutzer verfasst hat.
+func (h *Handlers) ownComment(c *gin.Context) (*models.MongoIpaProject, models.Comment, bool) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return nil, models.Comment{}, false // Error is already handled by helper
+	}
+	item, ok := findComment(c, *project)
+	if !ok {
+		return nil, models.Comment{}, false
+	}
+	if item.AuthorKey != currentUser(c, *project).Key() {
+		respondProblem(c, http.StatusForbidden, CodeNotCommentAuthor, "")
+		return nil, models.Comment{}, false
+	}
+	return project, item, true
+}
+
+// findComment liefert den Kommentar aus der URL. Gelöschte Kommentare und Kommentare zu
+// einem anderen Kriterium gelten als nicht vorhanden.
+func findComment(c *gin.Context, project models.MongoIpaProject) (models.Comment, bool) {
+	item, ok := comment.Find(project, c.Param("commentId"))
+	if !ok || item.Deleted || item.CriterionID != c.Param("criteriaId") {
+		respondProblem(c, http.StatusNotFound, CodeCommentNotFound, "")
+		return models.Comment{}, false
+	}
+	return item, true
+}
+
+// commentDetail beschreibt einen Fehler aus dem Paket comment in der Sprache der Anfrage.
+func commentDetail(c *gin.Context, err error) string {
+	for cause, msg := range commentMessages {
+		if errors.Is(err, cause) {
+			return localize(c, msg)
+		}
+	}
+	return ""
+}
+
+// commentMessages enthält die Meldungen zu den Fehlern aus dem Paket comment.
+var commentMessages = map[error]i18n.Text{
+	comment.ErrTextMissing: {
+		i18n.German:  "Der Text fehlt",
+		i18n.French:  "Le texte est manquant",
+		i18n.Italian: "Il testo manca",
+	},
+	comment.ErrTextTooLong: {
+		i18n.German:  "Der Text ist zu lang",
+		i18n.French:  "Le texte est trop long",
+		i18n.Italian: "Il testo è troppo lungo",
+	},
+	comment.ErrCriterionUnknown: {
+		i18n.German:  "Das Kriterium gehört nicht zum Projekt",
+		i18n.French:  "Le critère n'appartient pas au projet",
+		i18n.Italian: "Il criterio non appartiene al progetto",
+	},
+	comment.ErrThreadUnknown: {
+		i18n.German:  "Die Diskussion gehört nicht zu diesem Kriterium",
+		i18n.French:  "La discussion n'appartient pas à ce critère",
+		i18n.Italian: "La discussione non appartiene a questo criterio",
+	},
+	comment.ErrNotThread: {
+		i18n.German:  "Nur der erste Kommentar einer Diskussion trägt ihren Status",
+		i18n.French:  "Seul le premier commentaire d'une discussion porte son statut",
+		i18n.Italian: "Solo il primo commento di una discussione ne porta lo stato",
+	},
+}
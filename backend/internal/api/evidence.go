@@ -0,0 +1,282 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/evidence"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ListEvidenceHandler liefert alle Belege des Projekts.
+func (h *Handlers) ListEvidenceHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	items := project.Evidence
+	if items == nil {
+		items = make([]models.Evidence, 0)
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// GetEvidenceReportHandler liefert die Belege pro Kriterium und Anforderung.
+func (h *Handlers) GetEvidenceReportHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	c.JSON(http.StatusOK, evidence.Report(*project))
+}
+
+// CreateEvidenceFileHandler lädt eine Datei als Beleg zu einer Anforderung hoch. Die Datei wird
+// vor dem Speichern auf Grösse und Typ und, falls ein Virenscanner konfiguriert ist, auf
+// Schadsoftware geprüft.
+func (h *Handlers) CreateEvidenceFileHandler(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.EvidenceLimits.MaxSize+maxFormOverhead)
+	file, err := c.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		respondProblem(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "")
+		return
+	}
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidEvidence, localize(c, msgFileMissing))
+		return
+	}
+	requirement, err := strconv.Atoi(c.PostForm("requirement"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidEvidence, evidenceDetail(c, evidence.ErrRequirement))
+		return
+	}
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+
+	item := models.Evidence{
+		ID:          rand.Text(),
+		CriterionID: c.PostForm("criterionId"),
+		Requirement: requirement,
+		Kind:        models.EvidenceFile,
+		Title:       c.PostForm("title"),
+		FileName:    filepath.Base(file.Filename),
+		Size:        file.Size,
+		CreatedAt:   time.Now().UTC().Truncate(time.Millisecond), // Genauigkeit von MongoDB
+	}
+	if item.Title == "" {
+		item.Title = item.FileName
+	}
+	if err := evidence.Validate(item, *project); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidEvidence, evidenceDetail(c, err))
+		return
+	}
+
+	opened, err := file.Open()
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidEvidence, localize(c, msgUnreadableFile))
+		return
+	}
+	defer opened.Close()
+	head := make([]byte, evidence.SniffLength)
+	n, _ := io.ReadFull(opened, head)
+	item.ContentType, err = h.EvidenceLimits.Check(file.Size, head[:n])
+	if errors.Is(err, evidence.ErrTooLarge) {
+		respondProblem(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "")
+		return
+	}
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidEvidence, evidenceDetail(c, err))
+		return
+	}
+
+	if h.Scanner != nil {
+		if _, err := opened.Seek(0, io.SeekStart); err != nil {
+			respondInternalError(c, "rewinding evidence file failed", err)
+			return
+		}
+		err := h.Scanner.Scan(c.Request.Context(), opened)
+		if errors.Is(err, evidence.ErrInfected) {
+			requestLogger(c).Warn("infected evidence file rejected", "file", item.FileName, "error", err)
+			respondProblem(c, http.StatusBadRequest, CodeInvalidEvidence, evidenceDetail(c, err))
+			return
+		}
+		if err != nil {
+			respondInternalError(c, "scanning evidence file failed", err)
+			return
+		}
+	}
+
+	if _, err := opened.Seek(0, io.SeekStart); err != nil {
+		respondInternalError(c, "rewinding evidence file failed", err)
+		return
+	}
+	hash := sha256.New()
+	if err := h.Blobs.Put(c.Request.Context(), item.ID, io.TeeReader(opened, hash)); err != nil {
+		respondInternalError(c, "storing evidence file failed", err)
+		return
+	}
+	item.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	if _, err := h.MongoStore.AddEvidence(c.Request.Context(), project.ID, item); err != nil {
+		h.deleteEvidenceFiles(c, []models.Evidence{item})
+		respondStoreError(c, "adding evidence failed", err, CodeProjectNotFound)
+		return
+	}
+	requestLogger(c).Info("evidence file uploaded", "evidence_id", item.ID, "size", item.Size, "content_type", item.ContentType)
+	c.JSON(http.StatusCreated, item)
+}
+
+// CreateEvidenceLinkHandler verweist als Beleg zu einer Anforderung auf eine Seite eines Dokuments.
+func (h *Handlers) CreateEvidenceLinkHandler(c *gin.Context) {
+	var item models.Evidence
+	if err := c.ShouldBindJSON(&item); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+
+	item.ID = rand.Text()
+	item.Kind = models.EvidenceLink
+	item.FileName, item.ContentType, item.Size, item.SHA256 = "", "", 0, ""
+	item.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+	if item.Title == "" {
+		item.Title = item.Document
+	}
+	if err := evidence.Validate(item, *project); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidEvidence, evidenceDetail(c, err))
+		return
+	}
+
+	if _, err := h.MongoStore.AddEvidence(c.Request.Context(), project.ID, item); err != nil {
+		respondStoreError(c, "adding evidence failed", err, CodeProjectNotFound)
+		return
+	}
+	c.JSON(http.StatusCreated, item)
+}
+
+// DownloadEvidenceHandler liefert die Datei eines Belegs.
+func (h *Handlers) DownloadEvidenceHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	item, ok := evidence.Find(*project, c.Param("evidenceId"))
+	if !ok || item.Kind != models.EvidenceFile {
+		respondProblem(c, http.StatusNotFound, CodeEvidenceNotFound, "")
+		return
+	}
+
+	file, err := h.Blobs.Open(c.Request.Context(), item.ID)
+	if errors.Is(err, evidence.ErrBlobNotFound) {
+		requestLogger(c).Error("evidence file missing in blob store", "evidence_id", item.ID)
+		respondProblem(c, http.StatusNotFound, CodeEvidenceNotFound, "")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "opening evidence file failed", err)
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, item.Size, item.ContentType, file, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": item.FileName}),
+		"X-Content-Type-Options": "nosniff", // Der Browser soll den erkannten Typ nicht überschreiben
+	})
+}
+
+// DeleteEvidenceHandler löscht einen Beleg und seine Datei.
+func (h *Handlers) DeleteEvidenceHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	item, ok := evidence.Find(*project, c.Param("evidenceId"))
+	if !ok {
+		respondProblem(c, http.StatusNotFound, CodeEvidenceNotFound, "")
+		return
+	}
+
+	if _, err := h.MongoStore.DeleteEvidence(c.Request.Context(), project.ID, item.ID); err != nil {
+		respondStoreError(c, "deleting evidence failed", err, CodeEvidenceNotFound)
+		return
+	}
+	h.deleteEvidenceFiles(c, []models.Evidence{item})
+	c.Status(http.StatusNoContent)
+}
+
+// deleteEvidenceFiles löscht die Dateien der Belege aus dem BlobStore. Fehler werden nur
+// protokolliert, der Beleg selbst ist bereits gelöscht.
+func (h *Handlers) deleteEvidenceFiles(c *gin.Context, items []models.Evidence) {
+	for _, item := range items {
+		if item.Kind != models.EvidenceFile {
+			continue
+		}
+		if err := h.Blobs.Delete(c.Request.Context(), item.ID); err != nil {
+			requestLogger(c).Error("deleting evidence file failed", "evidence_id", item.ID, "error", err)
+		}
+	}
+}
+
+// evidenceDetail beschreibt einen Fehler aus dem Paket evidence in der Sprache der Anfrage.
+func evidenceDetail(c *gin.Context, err error) string {
+	for cause, msg := range evidenceMessages {
+		if errors.Is(err, cause) {
+			return localize(c, msg)
+		}
+	}
+	return ""
+}
+
+// evidenceMessages enthält die Meldungen zu den Fehlern aus dem Paket evidence.
+var evidenceMessages = map[error]i18n.Text{
+	evidence.ErrCriterionUnknown: {
+		i18n.German:  "Das Kriterium gehört nicht zum Projekt",
+		i18n.French:  "Le critère n'appartient pas au projet",
+		i18n.Italian: "Il criterio non appartiene al progetto",
+	},
+	evidence.ErrRequirement: {
+		i18n.German:  "Die Anforderung gehört nicht zum Kriterium",
+		i18n.French:  "L'exigence n'appartient pas au critère",
+		i18n.Italian: "Il requisito non appartiene al criterio",
+	},
+	evidence.ErrDocumentMissing: {
+		i18n.German:  "Das Dokument fehlt",
+		i18n.French:  "Le document est manquant",
+		i18n.Italian: "Il documento manca",
+	},
+	evidence.ErrPage: {
+		i18n.German:  "Die Seite darf nicht negativ sein",
+		i18n.French:  "La page ne doit pas être négative",
+		i18n.Italian: "La pagina non deve essere negativa",
+	},
+	evidence.ErrURL: {
+		i18n.German:  "Die URL muss mit http:// oder https:// beginnen",
+		i18n.French:  "L'URL doit commencer par http:// ou https://",
+		i18n.Italian: "L'URL deve iniziare con http:// o https://",
+	},
+	evidence.ErrType: {
+		i18n.German:  "Dieser Dateityp ist nicht erlaubt",
+		i18n.French:  "Ce type de fichier n'est pas autorisé",
+		i18n.Italian: "Questo tipo di file non è consentito",
+	},
+	evidence.ErrInfected: {
+		i18n.German:  "Der Virenscanner hat in der Datei Schadsoftware gefunden",
+		i18n.French:  "L'antivirus a détecté un logiciel malveillant dans le fichier",
+		i18n.Italian: "L'antivirus ha trovato software dannoso nel file",
+	},
+}
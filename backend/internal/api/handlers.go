@@ -1,42 +1,53 @@
 package api
 
 import (
+	"crypto/rand"
 	"errors"
-	"log"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/evidence"
 	"github.com/Liuuner/criteria-catalogue/backend/internal/grade"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/metrics"
 	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/notes"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/selection"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/snapshot"
 	"github.com/Liuuner/criteria-catalogue/backend/internal/store"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/timeline"
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 // Handlers enthält den Store für den Zugriff in den Handlern.
 type Handlers struct {
-	MongoStore   *store.MongoStore
-	JsonStore    *store.CriteriaStore
-	SecureCookie bool // Whether to use secure cookies (HTTPS)
+	MongoStore         *store.MongoStore
+	JsonStore          *store.CriteriaStore
+	SecureCookie       bool   // Whether to use secure cookies (HTTPS)
+	AdminToken         string // Bearer token for the admin API
+	ProjectIDScheme    common.ProjectIDScheme
+	ReadinessTimeout   time.Duration      // Timeout per dependency check of /readyz
+	Location           *time.Location     // Time zone of "today" in project timelines, UTC if nil
+	PlannedHoursPerDay float64            // Planned hours per workday, compared with the work journal
+	Blobs              evidence.BlobStore // Files of evidence
+	Scanner            evidence.Scanner   // Virus scanner for evidence files, no scan if nil
+	EvidenceLimits     evidence.Limits
+	Snapshots          snapshot.Signer // Signs the grade when a project is graded
 }
 
 func (h *Handlers) NotImplementedHandler(c *gin.Context) {
-	log.Printf("Not implemented endpoint: %s %s", c.Request.Method, c.Request.URL.Path)
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "This endpoint is not implemented yet."})
+	requestLogger(c).Warn("endpoint not implemented")
+	respondProblem(c, http.StatusNotImplemented, CodeNotImplemented, "")
 }
 
 // getIpaProjectFromRequest is a helper function to get an IPA project from a request.
 func (h *Handlers) getIpaProjectFromRequest(c *gin.Context) (*models.MongoIpaProject, error) {
 	personId := c.Param("id")
-	log.Printf("Request IpaProject with ID: %s", personId)
-	project, err := h.MongoStore.GetIpaProject(personId)
-	if errors.Is(err, mongo.ErrNoDocuments) {
-		log.Printf("No IpaProject found with ID: %s", personId)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Kein IPA-Projekt gefunden."})
-		return nil, err
-	}
+	project, err := h.MongoStore.GetIpaProject(c.Request.Context(), personId)
 	if err != nil {
-		log.Printf("Error retrieving IpaProject with ID %s: %v", personId, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Fehler beim Abrufen des IPA-Projekts: " + err.Error()})
+		respondStoreError(c, "retrieving ipa project failed", err, CodeProjectNotFound)
 		return nil, err
 	}
 	return &project, nil
@@ -56,53 +67,57 @@ func (h *Handlers) GetPersonDataHandler(c *gin.Context) {
 func (h *Handlers) CreateIpaProjectHandler(c *gin.Context) {
 	var personData models.IpaProject
 	if err := c.ShouldBindJSON(&personData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Ungültige Eingabedaten: " + err.Error()})
+		respondBindingError(c, err)
 		return
 	}
 
 	// Validate password is provided
 	if personData.Password == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Passwort ist erforderlich"})
+		respondProblem(c, http.StatusBadRequest, CodePasswordRequired, "")
 		return
 	}
 
 	// Hash the password
 	hashedPassword, err := HashPassword(personData.Password)
 	if err != nil {
-		log.Printf("Error hashing password: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Fehler beim Verarbeiten des Passworts"})
+		respondInternalError(c, "hashing password failed", err)
 		return
 	}
 
-	personData.Criteria = h.JsonStore.GetMandatoryCriteria()
+	if personData.Milestones == nil {
+		personData.Milestones = timeline.DefaultMilestones(personData.StartDate, personData.EndDate)
+	}
+	if err := timeline.Validate(personData.StartDate, personData.EndDate, personData.Milestones); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidTimeline, timelineDetail(c, err))
+		return
+	}
+	personData.Date = legacyDate(personData)
+	personData.Criteria = h.JsonStore.GetMandatoryCriteria(i18n.Default)
 
 	mongoPersonData := personData.MapWithoutId()
 	mongoPersonData.PasswordHash = hashedPassword
+	mongoPersonData.CatalogueVersion = h.JsonStore.GetVersion()
 
-	mongoPersonData.ID, err = h.MongoStore.GetNewID()
+	mongoPersonData.ID, err = h.MongoStore.CreateIpaProject(c.Request.Context(), mongoPersonData)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondStoreError(c, "saving ipa project failed", err, CodeProjectNotFound)
 		return
 	}
 
-	_, err = h.MongoStore.SavePersonData(mongoPersonData)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
+	logger := requestLogger(c).With("project_id", mongoPersonData.ID)
+	logger.Info("ipa project created")
 
 	// Generate token for immediate use after creation
 	token, err := GenerateToken(mongoPersonData.Map().ID)
 	if err != nil {
-		log.Printf("Error generating token after creation: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Fehler beim Erstellen des Tokens"})
+		respondInternalError(c, "generating token after creation failed", err)
 		return
 	}
 
 	// Set the auth cookie
 	SetAuthCookie(c, token, h.SecureCookie)
 
-	c.JSON(http.StatusOK, mongoPersonData.Map())
+	c.JSON(http.StatusOK, h.localizedProject(c, mongoPersonData))
 }
 
 func (h *Handlers) GetIpaProjectHandler(c *gin.Context) {
@@ -110,7 +125,7 @@ func (h *Handlers) GetIpaProjectHandler(c *gin.Context) {
 	if err != nil {
 		return // Error is already handled by helper
 	}
-	c.JSON(http.StatusOK, project.Map())
+	c.JSON(http.StatusOK, h.localizedProject(c, *project))
 }
 
 func (h *Handlers) GetIpaCriteriaHandler(c *gin.Context) {
@@ -118,55 +133,131 @@ func (h *Handlers) GetIpaCriteriaHandler(c *gin.Context) {
 	if err != nil {
 		return // Error is already handled by helper
 	}
-	c.JSON(http.StatusOK, project.Criteria)
+	c.JSON(http.StatusOK, notes.RenderCriteria(h.JsonStore.LocalizeCriteria(project.Criteria, requestLanguage(c))))
 }
 
-// GetPredefinedCriteriaHandler liefert alle Kriterien.
+// GetPredefinedCriteriaHandler liefert alle Kriterien in der Sprache der Anfrage.
 func (h *Handlers) GetPredefinedCriteriaHandler(c *gin.Context) {
-	criteria := h.JsonStore.GetAllCriteria()
+	criteria := h.JsonStore.GetAllCriteria(requestLanguage(c))
 	c.JSON(http.StatusOK, criteria)
 }
 
+// searchCriteriaQuery sind die Abfrageparameter der Katalogsuche.
+type searchCriteriaQuery struct {
+	Q     string `form:"q" binding:"required"`
+	Limit int    `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// SearchCriteriaHandler durchsucht den Katalog in der Sprache der Anfrage nach ID, Titel, Leitfrage
+// und Anforderungen.
+func (h *Handlers) SearchCriteriaHandler(c *gin.Context) {
+	var params searchCriteriaQuery
+	if err := c.ShouldBindQuery(&params); err != nil || strings.TrimSpace(params.Q) == "" {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidRequest, localize(c, msgInvalidQuery))
+		return
+	}
+	c.JSON(http.StatusOK, models.SearchResult{
+		Query: params.Q,
+		Hits:  h.JsonStore.SearchCriteria(params.Q, requestLanguage(c), params.Limit),
+	})
+}
+
+// GetSelectionRulesHandler liefert die Auswahlregeln für optionale Kriterien.
+func (h *Handlers) GetSelectionRulesHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, h.JsonStore.GetSelectionRules(requestLanguage(c)))
+}
+
 func (h *Handlers) CreateIpaCriteriaHandler(c *gin.Context) {
-	personId := c.Param("id")
 	var criterion models.Criterion
 	if err := c.ShouldBindJSON(&criterion); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Ungültige Eingabedaten: " + err.Error()})
+		respondBindingError(c, err)
 		return
 	}
 
-	_, err := h.MongoStore.AddCriterionToIpaProject(personId, criterion)
+	if err := notes.Validate(criterion.Notes); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidNotes, notesDetail(c, err))
+		return
+	}
+	project, err := h.getIpaProjectFromRequest(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Fehler beim Hinzufügen des Kriteriums: " + err.Error()})
+		return // Error is already handled by helper
+	}
+	if !requireEditable(c, *project) {
 		return
 	}
+
+	// Katalogkriterien kommen in der Sprache des Clients zurück, gespeichert wird die Standardsprache
+	stored := h.JsonStore.CanonicalCriterion(criterion, requestLanguage(c))
+	_, err = h.MongoStore.AddCriterionToIpaProject(c.Request.Context(), project.ID, stored, editableStatuses(c, *project))
+	if errors.Is(err, store.ErrConflict) {
+		respondProblem(c, http.StatusConflict, CodeCriterionExists, "")
+		return
+	}
+	if err != nil {
+		respondStoreError(c, "adding criterion failed", err, CodeProjectNotFound)
+		return
+	}
+	criterion.NotesHTML = notes.Render(criterion.Notes)
 	c.JSON(http.StatusCreated, criterion)
 }
 
+// UpdateIpaCriteriaHandler ersetzt ein Kriterium. Ändern sich die Notizen, bleibt die bisherige
+// Fassung in der Versionsgeschichte erhalten.
 func (h *Handlers) UpdateIpaCriteriaHandler(c *gin.Context) {
-	personId := c.Param("id")
 	criterionId := c.Param("criteriaId")
 	var criterion models.Criterion
 	if err := c.ShouldBindJSON(&criterion); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Ungültige Eingabedaten: " + err.Error()})
+		respondBindingError(c, err)
 		return
 	}
-
-	_, err := h.MongoStore.UpdateCriterionInIpaProject(personId, criterionId, criterion)
+	if err := notes.Validate(criterion.Notes); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidNotes, notesDetail(c, err))
+		return
+	}
+	project, err := h.getIpaProjectFromRequest(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Fehler beim Aktualisieren des Kriteriums: " + err.Error()})
+		return // Error is already handled by helper
+	}
+	if !requireEditable(c, *project) {
+		return
+	}
+
+	stored := h.JsonStore.CanonicalCriterion(criterion, requestLanguage(c))
+	if _, err := h.updateCriterion(c, *project, criterionId, stored); err != nil {
+		respondStoreError(c, "updating criterion failed", err, CodeCriterionNotFound)
 		return
 	}
+	criterion.NotesHTML = notes.Render(criterion.Notes)
 	c.JSON(http.StatusOK, criterion)
 }
 
+// updateCriterion speichert ein Kriterium. Überschreibt es die Notizen, wird die bisherige Fassung
+// aufbewahrt und zurückgegeben.
+func (h *Handlers) updateCriterion(c *gin.Context, project models.MongoIpaProject, criterionId string, criterion models.Criterion) (*models.NoteRevision, error) {
+	now := time.Now().UTC().Truncate(time.Millisecond) // Genauigkeit von MongoDB
+	var revision *models.NoteRevision
+	if previous, ok := notes.Revision(rand.Text(), project, criterionId, criterion.Notes, currentUser(c, project), now); ok {
+		revision = &previous
+	}
+	if _, err := h.MongoStore.UpdateCriterionInIpaProject(c.Request.Context(), project.ID, criterionId, criterion, revision, editableStatuses(c, project)); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
 func (h *Handlers) DeleteIpaCriteriaHandler(c *gin.Context) {
-	personId := c.Param("id")
 	criterionId := c.Param("criteriaId")
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	if !requireEditable(c, *project) {
+		return
+	}
 
-	_, err := h.MongoStore.DeleteCriterionFromIpaProject(personId, criterionId)
+	_, err = h.MongoStore.DeleteCriterionFromIpaProject(c.Request.Context(), project.ID, criterionId, editableStatuses(c, *project))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Fehler beim Löschen des Kriteriums: " + err.Error()})
+		respondStoreError(c, "deleting criterion failed", err, CodeCriterionNotFound)
 		return
 	}
 	c.Status(http.StatusNoContent)
@@ -176,79 +267,181 @@ func (h *Handlers) UpdatePersonDataHandler(c *gin.Context) {
 	personId := c.Param("id")
 	var personData models.IpaProject
 	if err := c.ShouldBindJSON(&personData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Ungültige Eingabedaten: " + err.Error()})
+		respondBindingError(c, err)
 		return
 	}
 
-	personData.ID = personId // Ensure the ID cannot be changed
-	mongoPersonData, err := personData.Map()
+	// Die Meilensteine bleiben unverändert und müssen im neuen Zeitraum liegen,
+	// Zeitraum und Meilensteine gemeinsam ändert UpdateTimelineHandler
+	project, err := h.getIpaProjectFromRequest(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Fehler beim Parsen der ID: " + err.Error()})
+		return // Error is already handled by helper
+	}
+	if err := timeline.Validate(personData.StartDate, personData.EndDate, project.Milestones); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidTimeline, timelineDetail(c, err))
 		return
 	}
 
-	_, err = h.MongoStore.UpdateIpaProject(personId, mongoPersonData)
+	personData.ID = personId // Ensure the ID cannot be changed
+	personData.Date = legacyDate(personData)
+	personData.Milestones = project.Milestones
+	mongoPersonData := personData.Map()
+
+	_, err = h.MongoStore.UpdateIpaProject(c.Request.Context(), personId, mongoPersonData)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Fehler beim Aktualisieren der Personendaten: " + err.Error()})
+		respondStoreError(c, "updating person data failed", err, CodeProjectNotFound)
 		return
 	}
 	c.JSON(http.StatusOK, personData)
 }
 
+// legacyDate liefert das Feld date, das ältere Clients als Abgabetag anzeigen und nach dem die
+// Projektliste filtert und sortiert: mit IPA-Zeitraum den Abgabetag, sonst die eigene Angabe.
+func legacyDate(project models.IpaProject) string {
+	if !project.EndDate.IsZero() {
+		return project.EndDate.String()
+	}
+	return project.Date
+}
+
+// GetGradeHandler liefert die Note des Projekts. Nach der Bewertung gilt die Note der letzten
+// Momentaufnahme, vorher wird sie aus den aktuellen Kriterien berechnet.
 func (h *Handlers) GetGradeHandler(c *gin.Context) {
 	project, err := h.getIpaProjectFromRequest(c)
 	if err != nil {
 		return // Error is already handled by helper
 	}
 
-	gradeResult := grade.CalculateGrade(project.Criteria)
-	c.JSON(http.StatusOK, gradeResult)
+	c.JSON(http.StatusOK, h.projectGrade(*project, requestLanguage(c)))
+}
+
+// projectGrade liefert die Note des Projekts in lang, nach der Bewertung aus der letzten
+// Momentaufnahme.
+func (h *Handlers) projectGrade(project models.MongoIpaProject, lang i18n.Lang) models.GradeResult {
+	if snapshot, ok := gradedSnapshot(project); ok {
+		return h.localizeGrade(snapshot.Grade, project.Criteria, lang)
+	}
+	gradeResult := grade.CalculateGrade(h.JsonStore.LocalizeCriteria(project.Criteria, lang))
+	gradeResult.Provisional = !selection.Validate(h.JsonStore.GetSelectionRules(lang), project.Criteria).Valid
+	return gradeResult
+}
+
+// GetSelectionHandler prüft die Kriterienauswahl des Projekts und listet alle Regelverletzungen auf.
+func (h *Handlers) GetSelectionHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+
+	c.JSON(http.StatusOK, selection.Validate(h.JsonStore.GetSelectionRules(requestLanguage(c)), project.Criteria))
 }
 
 // LoginHandler authenticates a user and returns a token
 func (h *Handlers) LoginHandler(c *gin.Context) {
 	var loginReq models.LoginRequest
 	if err := c.ShouldBindJSON(&loginReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Ungültige Eingabedaten: " + err.Error()})
+		respondBindingError(c, err)
+		return
+	}
+
+	// Reject typos early, the check character of the project ID makes them detectable
+	if err := h.MongoStore.ValidateProjectID(loginReq.ID); err != nil {
+		metrics.ObserveLogin(false)
+		respondProblem(c, http.StatusBadRequest, CodeInvalidProjectID, "")
 		return
 	}
 
+	// The login route has no :id parameter, so the project ID is added here
+	logger := requestLogger(c).With("project_id", common.NormalizeProjectID(loginReq.ID))
+
 	// Get the project
-	project, err := h.MongoStore.GetIpaProject(loginReq.ID)
-	if errors.Is(err, mongo.ErrNoDocuments) {
-		log.Printf("Login attempt for non-existent project: %s", loginReq.ID)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Ungültige Anmeldedaten"})
+	project, err := h.MongoStore.GetIpaProject(c.Request.Context(), loginReq.ID)
+	if errors.Is(err, store.ErrNotFound) {
+		logger.Warn("login attempt for non-existent project")
+		metrics.ObserveLogin(false)
+		respondProblem(c, http.StatusUnauthorized, CodeInvalidCredentials, "")
 		return
 	}
 	if err != nil {
-		log.Printf("Error retrieving project for login: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Fehler bei der Anmeldung"})
+		respondInternalError(c, "retrieving project for login failed", err)
 		return
 	}
 
 	// Check password
 	if !CheckPasswordHash(loginReq.Password, project.PasswordHash) {
-		log.Printf("Invalid password attempt for project: %s", loginReq.ID)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Ungültige Anmeldedaten"})
+		logger.Warn("login with invalid password")
+		metrics.ObserveLogin(false)
+		respondProblem(c, http.StatusUnauthorized, CodeInvalidCredentials, "")
 		return
 	}
 
-	// Generate token
-	token, err := GenerateToken(loginReq.ID)
+	// Generate token, a one-time password only allows replacing it
+	generate := GenerateToken
+	if project.PasswordChangeRequired {
+		generate = GeneratePasswordChangeToken
+	}
+	token, err := generate(project.ID)
 	if err != nil {
-		log.Printf("Error generating token: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Fehler beim Erstellen des Tokens"})
+		respondInternalError(c, "generating token failed", err)
 		return
 	}
+	logger.Info("login successful")
+	metrics.ObserveLogin(true)
 
 	// Set the auth cookie
 	SetAuthCookie(c, token, h.SecureCookie)
 
-	c.JSON(http.StatusOK, gin.H{"project": project.Map()})
+	c.JSON(http.StatusOK, gin.H{"project": h.localizedProject(c, project)})
+}
+
+// ChangePasswordHandler replaces the password of a project, e.g. the one-time password from a class import
+func (h *Handlers) ChangePasswordHandler(c *gin.Context) {
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+
+	if !CheckPasswordHash(req.CurrentPassword, project.PasswordHash) {
+		requestLogger(c).Warn("password change with invalid current password")
+		respondProblem(c, http.StatusUnauthorized, CodeWrongPassword, "")
+		return
+	}
+	if req.NewPassword == req.CurrentPassword {
+		respondProblem(c, http.StatusBadRequest, CodePasswordUnchanged, "")
+		return
+	}
+
+	hashedPassword, err := HashPassword(req.NewPassword)
+	if err != nil {
+		respondInternalError(c, "hashing password failed", err)
+		return
+	}
+
+	if _, err := h.MongoStore.UpdatePassword(c.Request.Context(), c.Param("id"), hashedPassword); err != nil {
+		respondStoreError(c, "changing password failed", err, CodeProjectNotFound)
+		return
+	}
+
+	// The token of the login with the one-time password only allowed the password change
+	if project.PasswordChangeRequired && currentUser(c, *project).Role == models.RoleCandidate {
+		token, err := GenerateToken(project.ID)
+		if err != nil {
+			respondInternalError(c, "generating token failed", err)
+			return
+		}
+		SetAuthCookie(c, token, h.SecureCookie)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": localize(c, msgPasswordChanged)})
 }
 
 // LogoutHandler clears the authentication cookie
 func (h *Handlers) LogoutHandler(c *gin.Context) {
 	ClearAuthCookie(c)
-	c.JSON(http.StatusOK, gin.H{"message": "Erfolgreich abgemeldet"})
+	c.JSON(http.StatusOK, gin.H{"message": localize(c, msgLoggedOut)})
 }
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/health"
+	"github.com/gin-gonic/gin"
+)
+
+// HealthzHandler reports that the process is alive. It checks no dependencies, so a
+// database outage does not cause the orchestrator to restart the container.
+func (h *Handlers) HealthzHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// ReadyzHandler checks MongoDB and the criteria catalogue and returns the result per
+// dependency. It responds with 503 as long as one of them is unavailable. The endpoint is
+// public, so the causes of failures are only logged.
+func (h *Handlers) ReadyzHandler(c *gin.Context) {
+	report := health.Run(c.Request.Context(), h.ReadinessTimeout,
+		health.Check{Name: "mongodb", Check: h.MongoStore.Ping},
+		health.Check{Name: "catalogue", Check: func(context.Context) error { return h.JsonStore.Validate() }},
+	)
+	status := http.StatusOK
+	if !report.OK() {
+		for name, result := range report.Checks {
+			if result.Cause != nil {
+				requestLogger(c).Warn("readiness check failed", "check", name, "error", result.Cause)
+			}
+		}
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/journal"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetJournalHandler liefert das Arbeitsjournal mit den Stunden pro Tag im Vergleich zur Planung.
+func (h *Handlers) GetJournalHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	c.JSON(http.StatusOK, journal.Summarize(*project, h.PlannedHoursPerDay))
+}
+
+// CreateJournalEntryHandler fügt dem Arbeitsjournal einen Eintrag hinzu.
+func (h *Handlers) CreateJournalEntryHandler(c *gin.Context) {
+	var entry models.JournalEntry
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+
+	entry.ID = rand.Text()
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Millisecond) // Genauigkeit von MongoDB
+	entry.UpdatedAt = entry.CreatedAt
+	if err := journal.Validate(entry, *project); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidJournalEntry, journalDetail(c, err))
+		return
+	}
+
+	if _, err := h.MongoStore.AddJournalEntry(c.Request.Context(), project.ID, entry); err != nil {
+		respondStoreError(c, "adding journal entry failed", err, CodeProjectNotFound)
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// UpdateJournalEntryHandler ersetzt einen Eintrag im Arbeitsjournal.
+func (h *Handlers) UpdateJournalEntryHandler(c *gin.Context) {
+	var entry models.JournalEntry
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+
+	entry.ID = c.Param("entryId") // Ensure the ID cannot be changed
+	index := -1
+	for i, existing := range project.Journal {
+		if existing.ID == entry.ID {
+			index = i
+		}
+	}
+	if index < 0 {
+		respondProblem(c, http.StatusNotFound, CodeJournalEntryNotFound, "")
+		return
+	}
+	entry.CreatedAt = project.Journal[index].CreatedAt
+	entry.UpdatedAt = time.Now().UTC().Truncate(time.Millisecond)
+	if err := journal.Validate(entry, *project); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidJournalEntry, journalDetail(c, err))
+		return
+	}
+
+	if _, err := h.MongoStore.UpdateJournalEntry(c.Request.Context(), project.ID, entry); err != nil {
+		respondStoreError(c, "updating journal entry failed", err, CodeJournalEntryNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// DeleteJournalEntryHandler löscht einen Eintrag aus dem Arbeitsjournal.
+func (h *Handlers) DeleteJournalEntryHandler(c *gin.Context) {
+	_, err := h.MongoStore.DeleteJournalEntry(c.Request.Context(), c.Param("id"), c.Param("entryId"))
+	if err != nil {
+		respondStoreError(c, "deleting journal entry failed", err, CodeJournalEntryNotFound)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// journalDetail beschreibt einen Fehler aus journal.Validate in der Sprache der Anfrage.
+func journalDetail(c *gin.Context, err error) string {
+	for cause, msg := range journalMessages {
+		if errors.Is(err, cause) {
+			return localize(c, msg)
+		}
+	}
+	return ""
+}
+
+// journalMessages enthält die Meldungen zu den Fehlern aus journal.Validate.
+var journalMessages = map[error]i18n.Text{
+	journal.ErrDateMissing: {
+		i18n.German:  "Das Datum fehlt",
+		i18n.French:  "La date est manquante",
+		i18n.Italian: "La data manca",
+	},
+	journal.ErrOutsidePeriod: {
+		i18n.German:  "Das Datum liegt ausserhalb des IPA-Zeitraums",
+		i18n.French:  "La date est en dehors de la période TPI",
+		i18n.Italian: "La data è al di fuori del periodo LPI",
+	},
+	journal.ErrActivitiesMissing: {
+		i18n.German:  "Die ausgeführten Arbeiten fehlen",
+		i18n.French:  "Les travaux effectués sont manquants",
+		i18n.Italian: "Mancano i lavori svolti",
+	},
+	journal.ErrHours: {
+		i18n.German:  "Die Stunden müssen grösser als 0 und höchstens 24 sein",
+		i18n.French:  "Les heures doivent être supérieures à 0 et au maximum 24",
+		i18n.Italian: "Le ore devono essere maggiori di 0 e al massimo 24",
+	},
+	journal.ErrDayTooLong: {
+		i18n.German:  "Die Einträge dieses Tages ergeben mehr als 24 Stunden",
+		i18n.French:  "Les entrées de ce jour dépassent 24 heures",
+		i18n.Italian: "Le voci di questo giorno superano le 24 ore",
+	},
+	journal.ErrUnknownTask: {
+		i18n.German:  "Die Aufgabe steht nicht im Zeitplan",
+		i18n.French:  "La tâche ne figure pas dans le planning",
+		i18n.Italian: "Il compito non figura nella pianificazione",
+	},
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/notes"
+	"github.com/gin-gonic/gin"
+)
+
+// languageKey speichert die ausgehandelte Sprache im gin.Context.
+const languageKey = "language"
+
+// requestLanguage liefert die über Accept-Language ausgehandelte Sprache der Anfrage und
+// kennzeichnet die Antwort mit Content-Language.
+func requestLanguage(c *gin.Context) i18n.Lang {
+	if lang, ok := c.Get(languageKey); ok {
+		return lang.(i18n.Lang)
+	}
+	lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+	c.Set(languageKey, lang)
+	c.Header("Content-Language", string(lang))
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	return lang
+}
+
+// localize liefert msg in der Sprache der Anfrage, formatiert mit args.
+func localize(c *gin.Context, msg i18n.Text, args ...any) string {
+	text := msg.In(requestLanguage(c))
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// localizedProject liefert das Projekt mit den Kriterien in der Sprache der Anfrage und den
+// Notizen als HTML.
+func (h *Handlers) localizedProject(c *gin.Context, project models.MongoIpaProject) models.IpaProject {
+	dto := project.Map()
+	dto.Criteria = notes.RenderCriteria(h.JsonStore.LocalizeCriteria(dto.Criteria, requestLanguage(c)))
+	return dto
+}
+
+// Meldungen der Handler, die über die Titel der Fehlercodes hinausgehen.
+var (
+	msgInvalidFields = i18n.Text{
+		i18n.German:  "Fehlende oder ungültige Felder: %s",
+		i18n.French:  "Champs manquants ou invalides : %s",
+		i18n.Italian: "Campi mancanti o non validi: %s",
+	}
+	msgInvalidFieldType = i18n.Text{
+		i18n.German:  "Feld %s hat einen ungültigen Typ",
+		i18n.French:  "Le champ %s a un type invalide",
+		i18n.Italian: "Il campo %s ha un tipo non valido",
+	}
+	msgInvalidJSON = i18n.Text{
+		i18n.German:  "Der Inhalt ist kein gültiges JSON",
+		i18n.French:  "Le contenu n'est pas un JSON valide",
+		i18n.Italian: "Il contenuto non è un JSON valido",
+	}
+	msgBearerExpected = i18n.Text{
+		i18n.German:  "Erwartet wird ein Bearer-Token",
+		i18n.French:  "Un jeton Bearer est attendu",
+		i18n.Italian: "È previsto un token Bearer",
+	}
+	msgTokenOtherProject = i18n.Text{
+		i18n.German:  "Der Token gehört zu einem anderen Projekt",
+		i18n.French:  "Le jeton appartient à un autre projet",
+		i18n.Italian: "Il token appartiene a un altro progetto",
+	}
+	msgInvalidQuery = i18n.Text{
+		i18n.German:  "Ungültige Abfrageparameter",
+		i18n.French:  "Paramètres de requête invalides",
+		i18n.Italian: "Parametri di query non validi",
+	}
+	msgUnknownTable = i18n.Text{
+		i18n.German:  "Unbekannte Tabelle: %s",
+		i18n.French:  "Tableau inconnu : %s",
+		i18n.Italian: "Tabella sconosciuta: %s",
+	}
+	msgInvalidDate = i18n.Text{
+		i18n.German:  "Ungültiges Datum: %s",
+		i18n.French:  "Date invalide : %s",
+		i18n.Italian: "Data non valida: %s",
+	}
+	msgInvalidFormat = i18n.Text{
+		i18n.German:  "Ungültiges Format: %s",
+		i18n.French:  "Format invalide : %s",
+		i18n.Italian: "Formato non valido: %s",
+	}
+	msgUnreadableFile = i18n.Text{
+		i18n.German:  "Die Datei kann nicht gelesen werden",
+		i18n.French:  "Le fichier ne peut pas être lu",
+		i18n.Italian: "Impossibile leggere il file",
+	}
+	msgFileMissing = i18n.Text{
+		i18n.German:  "Die Datei fehlt",
+		i18n.French:  "Le fichier est manquant",
+		i18n.Italian: "Il file manca",
+	}
+	msgInvalidRosterRows = i18n.Text{
+		i18n.German:  "Klassenliste enthält ungültige Zeilen",
+		i18n.French:  "La liste de classe contient des lignes invalides",
+		i18n.Italian: "L'elenco della classe contiene righe non valide",
+	}
+	msgInvalidSpreadsheetRows = i18n.Text{
+		i18n.German:  "Tabelle enthält ungültige Zeilen",
+		i18n.French:  "Le tableau contient des lignes invalides",
+		i18n.Italian: "La tabella contiene righe non valide",
+	}
+	msgProjectsMissing = i18n.Text{
+		i18n.German:  "Nicht gefundene IPA-Projekte: %s",
+		i18n.French:  "Projets TPI introuvables : %s",
+		i18n.Italian: "Progetti LPI non trovati: %s",
+	}
+	msgPasswordChanged = i18n.Text{
+		i18n.German:  "Passwort geändert",
+		i18n.French:  "Mot de passe modifié",
+		i18n.Italian: "Password modificata",
+	}
+	msgLoggedOut = i18n.Text{
+		i18n.German:  "Erfolgreich abgemeldet",
+		i18n.French:  "Déconnexion réussie",
+		i18n.Italian: "Disconnessione riuscita",
+	}
+	msgProjectLocked = i18n.Text{
+		i18n.German:  "Im Status %s können die Kriterien nicht geändert werden",
+		i18n.French:  "Les critères ne peuvent pas être modifiés dans le statut %s",
+		i18n.Italian: "Nello stato %s i criteri non possono essere modificati",
+	}
+)
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+)
+
+func TestErrorTitlesAreTranslated(t *testing.T) {
+	for code, title := range errorTitles {
+		for _, lang := range i18n.Supported {
+			if title[lang] == "" {
+				t.Errorf("title of %s lacks language %s", code, lang)
+			}
+		}
+	}
+}
+
+func TestResponsesUseRequestLanguage(t *testing.T) {
+	engine := newTestRouter(t)
+	tests := []struct {
+		acceptLanguage string
+		want           i18n.Lang
+	}{
+		{"", i18n.German},
+		{"fr-CH, de;q=0.8", i18n.French},
+		{"it", i18n.Italian},
+		{"en-GB", i18n.German},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.want)+" "+tt.acceptLanguage, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/ipa/K7QX2M3", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+
+			var problem Problem
+			if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("decoding problem %q: %v", recorder.Body, err)
+			}
+			if recorder.Code != http.StatusUnauthorized || problem.Title != errorTitles[CodeUnauthorized][tt.want] {
+				t.Errorf("got %d %+v, want title in %s", recorder.Code, problem, tt.want)
+			}
+			if got := recorder.Header().Get("Content-Language"); got != string(tt.want) {
+				t.Errorf("Content-Language = %q, want %q", got, tt.want)
+			}
+			if got := recorder.Header().Get("Vary"); got != "Accept-Language" {
+				t.Errorf("Vary = %q, want Accept-Language", got)
+			}
+		})
+	}
+}
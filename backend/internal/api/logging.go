@@ -0,0 +1,88 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RequestIDHeader is the header used to pass the request ID from and to clients and proxies
+	RequestIDHeader = "X-Request-ID"
+	// maxRequestIDLength limits request IDs taken over from clients
+	maxRequestIDLength = 128
+)
+
+// quietRoutes are polled by probes and scrapers, successful requests are only logged at debug level
+var quietRoutes = map[string]bool{"/healthz": true, "/readyz": true, "/metrics": true}
+
+// RequestLoggerMiddleware assigns every request an ID (or takes it over from the X-Request-ID
+// header), returns it in the response and stores a logger with request ID, route and project ID
+// in the request context. When the request is done, method, status and latency are logged.
+// Query strings, headers and bodies are never logged, they may contain passwords or tokens.
+func RequestLoggerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		attrs := []any{"request_id", requestID, "method", c.Request.Method, "route", c.FullPath()}
+		if projectID := c.Param("id"); projectID != "" {
+			attrs = append(attrs, "project_id", common.NormalizeProjectID(projectID))
+		}
+		logger := slog.Default().With(attrs...)
+		c.Request = c.Request.WithContext(common.WithLogger(c.Request.Context(), logger))
+
+		c.Next()
+
+		level := slog.LevelInfo
+		switch status := c.Writer.Status(); {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		case quietRoutes[c.FullPath()]:
+			level = slog.LevelDebug
+		}
+		logger.LogAttrs(c.Request.Context(), level, "request completed",
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", c.Writer.Status()),
+			slog.Duration("latency", time.Since(start)),
+			slog.Int("size", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+		)
+	}
+}
+
+// requestLogger returns the logger of the current request.
+func requestLogger(c *gin.Context) *slog.Logger {
+	return common.Logger(c.Request.Context())
+}
+
+// validRequestID accepts short IDs made of printable ASCII characters, so forwarded IDs
+// cannot inject line breaks or arbitrary data into the log.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // crypto/rand.Read never returns an error
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware counts requests and their latency per route template, e.g. /api/ipa/:id,
+// so the number of label values does not grow with the number of projects.
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		metrics.ObserveHTTPRequest(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start))
+	}
+}
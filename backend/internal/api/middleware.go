@@ -3,13 +3,15 @@ package api
 import (
 	"crypto/hmac"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
-	"log"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
 	"github.com/Liuuner/criteria-catalogue/backend/internal/store"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
@@ -20,6 +22,12 @@ const (
 	TokenValidityDuration = 14 * 24 * time.Hour // 3 weeks
 	// CookieName is the name of the authentication cookie
 	CookieName = "ipa_auth_token"
+	// userKey is the context key of the authenticated user
+	userKey = "user"
+	// passwordChangeClaim marks tokens of candidates who still have to replace their one-time password
+	passwordChangeClaim = "password-change"
+	// passwordRoute is the only route a token with passwordChangeClaim grants access to
+	passwordRoute = "/api/ipa/:id/password"
 )
 
 var tokenSecret = []byte("change-this-secret-in-production")
@@ -46,8 +54,25 @@ func CheckPasswordHash(password, hash string) bool {
 // GenerateToken creates a simple token for authentication
 // Token format: base64(projectId:timestamp):signature
 func GenerateToken(projectID string) (string, error) {
-	timestamp := time.Now().Unix()
-	payload := fmt.Sprintf("%s:%d", projectID, timestamp)
+	return signToken(fmt.Sprintf("%s:%d", projectID, time.Now().Unix())), nil
+}
+
+// GeneratePasswordChangeToken creates a token for a candidate who logged in with a one-time
+// password. It only grants access to PUT /password, see AuthMiddleware.
+// Token format: base64(projectId:timestamp:password-change):signature
+func GeneratePasswordChangeToken(projectID string) (string, error) {
+	return signToken(fmt.Sprintf("%s:%d:%s", projectID, time.Now().Unix(), passwordChangeClaim)), nil
+}
+
+// GenerateExpertToken creates a token for an expert of the project. The name of the expert
+// is the author of their comments.
+// Token format: base64(projectId:timestamp:expert:base64(name)):signature
+func GenerateExpertToken(projectID string, name string) (string, error) {
+	encodedName := base64.RawURLEncoding.EncodeToString([]byte(name))
+	return signToken(fmt.Sprintf("%s:%d:%s:%s", projectID, time.Now().Unix(), models.RoleExpert, encodedName)), nil
+}
+
+func signToken(payload string) string {
 	encodedPayload := base64.StdEncoding.EncodeToString([]byte(payload))
 
 	// Create HMAC signature
@@ -55,14 +80,29 @@ func GenerateToken(projectID string) (string, error) {
 	h.Write([]byte(encodedPayload))
 	signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
 
-	return fmt.Sprintf("%s.%s", encodedPayload, signature), nil
+	return fmt.Sprintf("%s.%s", encodedPayload, signature)
+}
+
+// TokenClaims are the contents of a valid token.
+type TokenClaims struct {
+	ProjectID string
+	Role      models.Role
+	Name      string // Name of the expert, empty for the candidate
+
+	PasswordChangeRequired bool // Only PUT /password is allowed until the one-time password is replaced
 }
 
 // ValidateToken validates the token and returns the project ID if valid
 func ValidateToken(token string) (string, error) {
+	claims, err := ParseToken(token)
+	return claims.ProjectID, err
+}
+
+// ParseToken validates the token and returns its claims. Tokens without a role belong to the candidate.
+func ParseToken(token string) (TokenClaims, error) {
 	parts := strings.Split(token, ".")
 	if len(parts) != 2 {
-		return "", fmt.Errorf("invalid token format")
+		return TokenClaims{}, fmt.Errorf("invalid token format")
 	}
 
 	encodedPayload := parts[0]
@@ -74,35 +114,48 @@ func ValidateToken(token string) (string, error) {
 	expectedSignature := base64.StdEncoding.EncodeToString(h.Sum(nil))
 
 	if !hmac.Equal([]byte(providedSignature), []byte(expectedSignature)) {
-		return "", fmt.Errorf("invalid token signature")
+		return TokenClaims{}, fmt.Errorf("invalid token signature")
 	}
 
 	// Decode payload
 	payloadBytes, err := base64.StdEncoding.DecodeString(encodedPayload)
 	if err != nil {
-		return "", fmt.Errorf("invalid token encoding")
+		return TokenClaims{}, fmt.Errorf("invalid token encoding")
 	}
 
 	payload := string(payloadBytes)
 	parts = strings.Split(payload, ":")
-	if len(parts) != 2 {
-		return "", fmt.Errorf("invalid token payload")
+	if len(parts) < 2 || len(parts) > 4 {
+		return TokenClaims{}, fmt.Errorf("invalid token payload")
 	}
 
-	projectID := parts[0]
+	claims := TokenClaims{ProjectID: parts[0], Role: models.RoleCandidate}
 	var timestamp int64
 	_, err = fmt.Sscanf(parts[1], "%d", &timestamp)
 	if err != nil {
-		return "", fmt.Errorf("invalid token timestamp")
+		return TokenClaims{}, fmt.Errorf("invalid token timestamp")
 	}
 
 	// Check if token has expired
 	tokenTime := time.Unix(timestamp, 0)
 	if time.Since(tokenTime) > TokenValidityDuration {
-		return "", fmt.Errorf("token expired")
+		return TokenClaims{}, fmt.Errorf("token expired")
 	}
 
-	return projectID, nil
+	if len(parts) == 3 {
+		if parts[2] != passwordChangeClaim {
+			return TokenClaims{}, fmt.Errorf("invalid token claim")
+		}
+		claims.PasswordChangeRequired = true
+	}
+	if len(parts) == 4 {
+		name, err := base64.RawURLEncoding.DecodeString(parts[3])
+		if models.Role(parts[2]) != models.RoleExpert || err != nil || len(name) == 0 {
+			return TokenClaims{}, fmt.Errorf("invalid token role")
+		}
+		claims.Role, claims.Name = models.RoleExpert, string(name)
+	}
+	return claims, nil
 }
 
 // SetAuthCookie sets the authentication cookie
@@ -131,8 +184,7 @@ func AuthMiddleware(_ *store.MongoStore) gin.HandlerFunc {
 		// Get the project ID from the URL parameter
 		projectID := c.Param("id")
 		if projectID == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Projekt-ID fehlt"})
-			c.Abort()
+			respondProblem(c, http.StatusBadRequest, CodeInvalidProjectID, "")
 			return
 		}
 
@@ -145,15 +197,13 @@ func AuthMiddleware(_ *store.MongoStore) gin.HandlerFunc {
 			// Fall back to Bearer token
 			authHeader := c.GetHeader("Authorization")
 			if authHeader == "" {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Autorisierung erforderlich"})
-				c.Abort()
+				respondProblem(c, http.StatusUnauthorized, CodeUnauthorized, "")
 				return
 			}
 
 			// Check Bearer token format
 			if !strings.HasPrefix(authHeader, "Bearer ") {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Ungültiges Autorisierungsformat"})
-				c.Abort()
+				respondProblem(c, http.StatusUnauthorized, CodeUnauthorized, localize(c, msgBearerExpected))
 				return
 			}
 
@@ -161,23 +211,67 @@ func AuthMiddleware(_ *store.MongoStore) gin.HandlerFunc {
 		}
 
 		// Validate the token
-		tokenProjectID, err := ValidateToken(token)
+		claims, err := ParseToken(token)
 		if err != nil {
-			log.Printf("Token validation failed: %v", err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Ungültiger oder abgelaufener Token"})
-			c.Abort()
+			requestLogger(c).Warn("token validation failed", "error", err)
+			respondProblem(c, http.StatusUnauthorized, CodeInvalidToken, "")
 			return
 		}
 
 		// Ensure the token is for the correct project
-		if tokenProjectID != projectID {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Token gehört nicht zu diesem Projekt"})
-			c.Abort()
+		if claims.ProjectID != common.NormalizeProjectID(projectID) {
+			respondProblem(c, http.StatusForbidden, CodeForbidden, localize(c, msgTokenOtherProject))
+			return
+		}
+
+		// A one-time password only allows replacing it
+		if claims.PasswordChangeRequired && !(c.Request.Method == http.MethodPut && c.FullPath() == passwordRoute) {
+			respondProblem(c, http.StatusForbidden, CodePasswordChangeRequired, "")
 			return
 		}
 
-		// Store the project ID in context for handlers to use
+		// Store the project ID and the user in context for handlers to use
 		c.Set("projectID", projectID)
+		c.Set(userKey, models.User{Role: claims.Role, Name: claims.Name})
+		c.Next()
+	}
+}
+
+// currentUser returns the user authenticated by AuthMiddleware. The name of the candidate
+// is taken from the project.
+func currentUser(c *gin.Context, project models.MongoIpaProject) models.User {
+	user, ok := c.Value(userKey).(models.User)
+	if !ok {
+		user = models.User{Role: models.RoleCandidate}
+	}
+	if user.Role == models.RoleCandidate {
+		user.Name = strings.TrimSpace(project.Firstname + " " + project.Lastname)
+	}
+	return user
+}
+
+// AdminMiddleware checks that the request carries the configured admin token as Bearer token.
+// The admin API is disabled when no admin token is configured.
+func AdminMiddleware(adminToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if adminToken == "" {
+			respondProblem(c, http.StatusForbidden, CodeAdminDisabled, "")
+			return
+		}
+
+		authHeader := c.GetHeader("Authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			respondProblem(c, http.StatusUnauthorized, CodeUnauthorized, "")
+			return
+		}
+
+		token := strings.TrimPrefix(authHeader, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			requestLogger(c).Warn("invalid admin token")
+			respondProblem(c, http.StatusUnauthorized, CodeInvalidToken, "")
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseToken(t *testing.T) {
+	candidateToken, _ := GenerateToken("AA01")
+	claims, err := ParseToken(candidateToken)
+	if err != nil || claims != (TokenClaims{ProjectID: "AA01", Role: models.RoleCandidate}) {
+		t.Errorf("ParseToken(candidate) = %+v, %v", claims, err)
+	}
+
+	expertToken, _ := GenerateExpertToken("AA01", "Eva Experte: Prüfung")
+	claims, err = ParseToken(expertToken)
+	if err != nil || claims != (TokenClaims{ProjectID: "AA01", Role: models.RoleExpert, Name: "Eva Experte: Prüfung"}) {
+		t.Errorf("ParseToken(expert) = %+v, %v", claims, err)
+	}
+	if id, err := ValidateToken(expertToken); err != nil || id != "AA01" {
+		t.Errorf("ValidateToken(expert) = %q, %v", id, err)
+	}
+
+	passwordChangeToken, _ := GeneratePasswordChangeToken("AA01")
+	claims, err = ParseToken(passwordChangeToken)
+	if err != nil || claims != (TokenClaims{ProjectID: "AA01", Role: models.RoleCandidate, PasswordChangeRequired: true}) {
+		t.Errorf("ParseToken(password change) = %+v, %v", claims, err)
+	}
+
+	payload, signature, _ := strings.Cut(expertToken, ".")
+	for name, token := range map[string]string{
+		"tampered payload":  "X" + payload[1:] + "." + signature,
+		"missing signature": payload,
+		"unknown role":      signToken(fmt.Sprintf("AA01:%d:admin:RXZh", time.Now().Unix())),
+		"expired":           signToken("AA01:1"),
+		"unknown claim":     signToken(fmt.Sprintf("AA01:%d:admin", time.Now().Unix())),
+	} {
+		if _, err := ParseToken(token); err == nil {
+			t.Errorf("ParseToken(%s) accepted token", name)
+		}
+	}
+}
+
+func TestAuthMiddlewareRequiresPasswordChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestLoggerMiddleware())
+	protected := r.Group("/api/ipa/:id", AuthMiddleware(nil))
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	protected.GET("", ok)
+	protected.GET("/password", ok)
+	protected.PUT("/password", ok)
+	protected.PUT("/criteria/:criteriaId", ok)
+
+	oneTime, _ := GeneratePasswordChangeToken("K7QX2M3")
+	regular, _ := GenerateToken("K7QX2M3")
+	tests := []struct {
+		name, token, method, path string
+		wantStatus                int
+	}{
+		{"change password", oneTime, "PUT", "/api/ipa/K7QX2M3/password", http.StatusNoContent},
+		{"read project", oneTime, "GET", "/api/ipa/K7QX2M3", http.StatusForbidden},
+		{"update criterion", oneTime, "PUT", "/api/ipa/K7QX2M3/criteria/A01", http.StatusForbidden},
+		{"other method on password route", oneTime, "GET", "/api/ipa/K7QX2M3/password", http.StatusForbidden},
+		{"regular token", regular, "GET", "/api/ipa/K7QX2M3", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+			if tt.wantStatus == http.StatusForbidden {
+				var problem Problem
+				if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil || problem.Code != CodePasswordChangeRequired {
+					t.Errorf("problem = %+v, %v, want code %s", problem, err, CodePasswordChangeRequired)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/notes"
+	"github.com/gin-gonic/gin"
+)
+
+// GetNotesHandler liefert die Notizen zu einem Kriterium als Markdown und HTML mit ihren
+// früheren Fassungen.
+func (h *Handlers) GetNotesHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	criterion, ok := findCriterion(*project, c.Param("criteriaId"))
+	if !ok {
+		respondProblem(c, http.StatusNotFound, CodeCriterionNotFound, "")
+		return
+	}
+	c.JSON(http.StatusOK, criterionNotes(*project, criterion))
+}
+
+// RestoreNoteRevisionHandler stellt eine frühere Fassung der Notizen wieder her. Die aktuellen
+// Notizen werden dabei selbst zu einer früheren Fassung.
+func (h *Handlers) RestoreNoteRevisionHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	if !requireEditable(c, *project) {
+		return
+	}
+	criterion, ok := findCriterion(*project, c.Param("criteriaId"))
+	if !ok {
+		respondProblem(c, http.StatusNotFound, CodeCriterionNotFound, "")
+		return
+	}
+	revision, ok := notes.Find(*project, criterion.ID, c.Param("revisionId"))
+	if !ok {
+		respondProblem(c, http.StatusNotFound, CodeRevisionNotFound, "")
+		return
+	}
+
+	criterion.Notes = revision.Notes
+	replaced, err := h.updateCriterion(c, *project, criterion.ID, criterion)
+	if err != nil {
+		respondStoreError(c, "restoring notes failed", err, CodeCriterionNotFound)
+		return
+	}
+	if replaced != nil {
+		project.NoteRevisions = append(project.NoteRevisions, *replaced)
+	}
+	c.JSON(http.StatusOK, criterionNotes(*project, criterion))
+}
+
+// findCriterion liefert das Kriterium mit der ID id.
+func findCriterion(project models.MongoIpaProject, id string) (models.Criterion, bool) {
+	index := slices.IndexFunc(project.Criteria, func(criterion models.Criterion) bool {
+		return criterion.ID == id
+	})
+	if index < 0 {
+		return models.Criterion{}, false
+	}
+	return project.Criteria[index], true
+}
+
+// criterionNotes stellt die Notizen zu einem Kriterium mit ihren früheren Fassungen zusammen.
+func criterionNotes(project models.MongoIpaProject, criterion models.Criterion) models.CriterionNotes {
+	return models.CriterionNotes{
+		CriterionID: criterion.ID,
+		Notes:       criterion.Notes,
+		NotesHTML:   notes.Render(criterion.Notes),
+		Revisions:   notes.History(project, criterion.ID),
+	}
+}
+
+// notesDetail beschreibt einen Fehler aus notes.Validate in der Sprache der Anfrage.
+func notesDetail(c *gin.Context, err error) string {
+	for cause, msg := range notesMessages {
+		if errors.Is(err, cause) {
+			return localize(c, msg)
+		}
+	}
+	return ""
+}
+
+// notesMessages enthält die Meldungen zu den Fehlern aus notes.Validate.
+var notesMessages = map[error]i18n.Text{
+	notes.ErrTooLong: {
+		i18n.German:  "Die Notizen dürfen höchstens 20000 Zeichen lang sein",
+		i18n.French:  "Les notes ne doivent pas dépasser 20000 caractères",
+		i18n.Italian: "Le note possono contenere al massimo 20000 caratteri",
+	},
+}
@@ -0,0 +1,365 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/admin"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/archive"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/cohort"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/comment"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/evidence"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/grade"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/health"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/journal"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/notes"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/plan"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/selection"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/snapshot"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/spreadsheet"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/store"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/timeline"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/workflow"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+const testAdminToken = "test-admin-token"
+
+func loadTestDocument(t *testing.T) *openapi3.T {
+	t.Helper()
+	doc, err := LoadOpenAPI()
+	if err != nil {
+		t.Fatalf("LoadOpenAPI() error = %v", err)
+	}
+	return doc
+}
+
+// newTestRouter registers all routes. Handlers that need MongoDB must not be called,
+// the MongoStore is nil.
+func newTestRouter(t *testing.T, middleware ...gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	criteriaStore, err := store.NewCriteriaStore(common.Config{CriteriaFilePath: "../../criteria.json"})
+	if err != nil {
+		t.Fatalf("NewCriteriaStore() error = %v", err)
+	}
+	h := &Handlers{JsonStore: criteriaStore, AdminToken: testAdminToken, ReadinessTimeout: time.Second}
+
+	r := gin.New()
+	r.Use(middleware...)
+	SetupSystemRoutes(r, h, "test")
+	SetupRouter(r, h, nil)
+	return r
+}
+
+var ginParam = regexp.MustCompile(`:(\w+)`)
+
+func TestRoutesMatchOpenAPI(t *testing.T) {
+	doc := loadTestDocument(t)
+
+	var documented []string
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			documented = append(documented, method+" "+path)
+		}
+	}
+	var registered []string
+	for _, route := range newTestRouter(t).Routes() {
+		registered = append(registered, route.Method+" "+ginParam.ReplaceAllString(route.Path, "{$1}"))
+	}
+
+	for _, route := range registered {
+		if !slices.Contains(documented, route) {
+			t.Errorf("route %s is not documented in openapi.json", route)
+		}
+	}
+	for _, operation := range documented {
+		if !slices.Contains(registered, operation) {
+			t.Errorf("openapi.json documents %s, but no such route is registered", operation)
+		}
+	}
+}
+
+func TestResponsesMatchOpenAPI(t *testing.T) {
+	doc := loadTestDocument(t)
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatalf("gorillamux.NewRouter() error = %v", err)
+	}
+	engine := newTestRouter(t)
+	otherProjectToken, _ := GenerateToken("AB02")
+	projectToken, _ := GenerateToken("AA01")
+	oneTimeToken, _ := GeneratePasswordChangeToken("AA01")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		header     map[string]string
+		wantStatus int
+	}{
+		{"criteria", "GET", "/api/criteria", "", nil, http.StatusOK},
+		{"selection rules", "GET", "/api/criteria/rules", "", nil, http.StatusOK},
+		{"search criteria", "GET", "/api/criteria/search?q=Doku&limit=2", "", nil, http.StatusOK},
+		{"search criteria without query", "GET", "/api/criteria/search", "", nil, http.StatusBadRequest},
+		{"openapi document", "GET", "/api/openapi.json", "", nil, http.StatusOK},
+		{"logout", "POST", "/api/ipa/logout", "", nil, http.StatusOK},
+		{"create without password", "POST", "/api/ipa", `{"firstname":"Anna"}`, nil, http.StatusBadRequest},
+		{"login without password", "POST", "/api/ipa/login", `{"id":"AA01"}`, nil, http.StatusBadRequest},
+		{"create with impossible date", "POST", "/api/ipa", `{"firstname":"Anna","password":"geheim","startDate":"2026-02-30"}`, nil, http.StatusBadRequest},
+		{"create with end before start", "POST", "/api/ipa", `{"firstname":"Anna","password":"geheim","startDate":"2026-03-13","endDate":"2026-03-02"}`, nil, http.StatusBadRequest},
+		{"timeline without token", "PUT", "/api/ipa/AA01/timeline", `{"milestones":[]}`, nil, http.StatusUnauthorized},
+		{"task without token", "DELETE", "/api/ipa/AA01/plan/tasks/T1", "", nil, http.StatusUnauthorized},
+		{"journal entry without token", "POST", "/api/ipa/AA01/journal", `{"date":"2026-05-04","activities":"Kick-off","hours":8}`, nil, http.StatusUnauthorized},
+		{"evidence report without token", "GET", "/api/ipa/AA01/evidence/report", "", nil, http.StatusUnauthorized},
+		{"evidence upload without file", "POST", "/api/ipa/AA01/evidence/files", "", map[string]string{"Authorization": "Bearer " + projectToken}, http.StatusBadRequest},
+		{"notes without token", "GET", "/api/ipa/AA01/criteria/A01/notes", "", nil, http.StatusUnauthorized},
+		{"criterion with too long notes", "POST", "/api/ipa/AA01/criteria", `{"id":"A01","notes":"` + strings.Repeat("a", notes.MaxLength+1) + `"}`, map[string]string{"Authorization": "Bearer " + projectToken}, http.StatusBadRequest},
+		{"status without token", "GET", "/api/ipa/AA01/status", "", nil, http.StatusUnauthorized},
+		{"export with unknown format", "GET", "/api/ipa/AA01/export?format=pdf", "", map[string]string{"Authorization": "Bearer " + projectToken}, http.StatusBadRequest},
+		{"import without criteria", "POST", "/api/ipa/AA01/import?dryRun=true", "id;checked\n", map[string]string{"Authorization": "Bearer " + projectToken}, http.StatusBadRequest},
+		{"grade snapshots without token", "GET", "/api/ipa/AA01/grade/snapshots/verify", "", nil, http.StatusUnauthorized},
+		{"status change without status", "POST", "/api/ipa/AA01/status", `{}`, map[string]string{"Authorization": "Bearer " + projectToken}, http.StatusBadRequest},
+		{"comments without token", "GET", "/api/ipa/AA01/comments", "", nil, http.StatusUnauthorized},
+		{"statistics with unknown table", "GET", "/api/admin/statistics?format=csv&table=projects", "", map[string]string{"Authorization": "Bearer " + testAdminToken}, http.StatusBadRequest},
+		{"expert token without name", "POST", "/api/admin/projects/AA01/expert-token", `{"name":" "}`, map[string]string{"Authorization": "Bearer " + testAdminToken}, http.StatusBadRequest},
+		{"project without token", "GET", "/api/ipa/AA01", "", nil, http.StatusUnauthorized},
+		{"project with token of other project", "GET", "/api/ipa/AA01/grade", "", map[string]string{"Authorization": "Bearer " + otherProjectToken}, http.StatusForbidden},
+		{"project before password change", "GET", "/api/ipa/AA01/grade", "", map[string]string{"Authorization": "Bearer " + oneTimeToken}, http.StatusForbidden},
+		{"admin without token", "GET", "/api/admin/projects", "", nil, http.StatusUnauthorized},
+		{"admin with wrong token", "DELETE", "/api/admin/projects/AA01", "", map[string]string{"Authorization": "Bearer wrong"}, http.StatusUnauthorized},
+		{"reload catalogue", "POST", "/api/admin/catalogue/reload", "", map[string]string{"Authorization": "Bearer " + testAdminToken}, http.StatusOK},
+		{"liveness", "GET", "/healthz", "", nil, http.StatusOK},
+		{"version", "GET", "/version", "", nil, http.StatusOK},
+		{"metrics", "GET", "/metrics", "", nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("%s %s returned %d, want %d: %s", tt.method, tt.path, recorder.Code, tt.wantStatus, recorder.Body)
+			}
+
+			route, pathParams, err := router.FindRoute(httptest.NewRequest(tt.method, tt.path, nil))
+			if err != nil {
+				t.Fatalf("FindRoute() error = %v", err)
+			}
+			input := &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: &openapi3filter.RequestValidationInput{Request: req, PathParams: pathParams, Route: route},
+				Status:                 recorder.Code,
+				Header:                 recorder.Header(),
+				Body:                   io.NopCloser(bytes.NewReader(recorder.Body.Bytes())),
+				Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+			}
+			if err := openapi3filter.ValidateResponse(t.Context(), input); err != nil {
+				t.Errorf("response does not match openapi.json: %v", err)
+			}
+		})
+	}
+}
+
+// TestModelsMatchOpenAPI checks the responses of the handlers that need MongoDB through the
+// values they encode.
+func TestModelsMatchOpenAPI(t *testing.T) {
+	doc := loadTestDocument(t)
+	criteriaStore, err := store.NewCriteriaStore(common.Config{CriteriaFilePath: "../../criteria.json"})
+	if err != nil {
+		t.Fatalf("NewCriteriaStore() error = %v", err)
+	}
+	criteria := criteriaStore.GetMandatoryCriteria(i18n.Default)
+	criteria[0].Checked = []int{1, 2}
+	criteria[0].Notes = "Analyse mit **Zielstruktur**"
+	project := models.MongoIpaProject{
+		ID: "K7QX2M3", Firstname: "Anna", Lastname: "Muster", Topic: "Webshop", Date: "2026-05-04",
+		PasswordHash: "hash", CatalogueVersion: criteriaStore.GetVersion(), Criteria: criteria,
+		StartDate: models.Date{Year: 2026, Month: 5, Day: 4}, EndDate: models.Date{Year: 2026, Month: 5, Day: 15},
+	}
+	project.Milestones = timeline.DefaultMilestones(project.StartDate, project.EndDate)
+	project.Tasks = []models.Task{{ID: "T3PL9", Title: "Planung", Start: project.StartDate, DurationDays: 2, PlannedHours: 12}}
+	project.Evidence = []models.Evidence{
+		{ID: "E7FQ2", CriterionID: criteria[0].ID, Requirement: 1, Kind: models.EvidenceFile, Title: "Projektauftrag", FileName: "auftrag.pdf",
+			ContentType: "application/pdf", Size: 48213, SHA256: strings.Repeat("ab", 32), CreatedAt: time.Date(2026, 5, 4, 17, 0, 0, 0, time.UTC)},
+		{ID: "E9LK4", CriterionID: criteria[0].ID, Requirement: 2, Kind: models.EvidenceLink, Title: "IPA-Bericht", Document: "IPA-Bericht", Page: 12,
+			URL: "https://example.org/bericht.pdf", CreatedAt: time.Date(2026, 5, 5, 9, 0, 0, 0, time.UTC)},
+	}
+	project.Journal = []models.JournalEntry{{
+		ID: "J4KD2", Date: project.StartDate, Activities: "Kick-off", Hours: 8, HelpReceived: "Fachperson", TaskID: "T3PL9",
+		CreatedAt: time.Date(2026, 5, 4, 17, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2026, 5, 4, 17, 0, 0, 0, time.UTC),
+	}}
+	expert := models.User{Role: models.RoleExpert, Name: "Eva Experte"}
+	candidate := models.User{Role: models.RoleCandidate, Name: "Anna Muster"}
+	project.Comments = []models.Comment{
+		comment.New("C2NF8", models.CommentInput{Text: "Bitte die Methode begründen."}, criteria[0].ID, expert, time.Date(2026, 5, 6, 9, 0, 0, 0, time.UTC)),
+		comment.New("C5RT3", models.CommentInput{Text: "Siehe Kapitel 3.", ThreadID: "C2NF8"}, criteria[0].ID, candidate, time.Date(2026, 5, 6, 10, 0, 0, 0, time.UTC)),
+	}
+	project.NoteRevisions = []models.NoteRevision{{ID: "R8MV2", CriterionID: criteria[0].ID, Notes: "Analyse", ReplacedAt: time.Date(2026, 5, 6, 11, 0, 0, 0, time.UTC),
+		ReplacedBy: "Anna Muster", Role: models.RoleCandidate}}
+	graded := project
+	graded.Status = models.StatusGraded
+	graded.StatusHistory = []models.StatusChange{{From: models.StatusUnderReview, To: models.StatusGraded, By: "Eva Experte", Role: models.RoleExpert,
+		At: time.Date(2026, 5, 20, 9, 0, 0, 0, time.UTC)}}
+	signer := snapshot.Signer{Key: []byte("secret")}
+	graded.GradeSnapshots = []models.GradeSnapshot{signer.New("S4HX7", project, false, expert, graded.StatusHistory[0].At)}
+	tampered := graded
+	tampered.GradeSnapshots = []models.GradeSnapshot{graded.GradeSnapshots[0]}
+	tampered.GradeSnapshots[0].Grade.Part1.Grade = 1
+	imported, err := spreadsheet.Plan(project, []spreadsheet.Entry{{Row: 2, ID: criteria[0].ID, Checked: []int{0}}})
+	if err != nil {
+		t.Fatalf("spreadsheet.Plan() error = %v", err)
+	}
+	personData := project
+	personData.Criteria = nil
+	personData.StartDate, personData.EndDate, personData.Milestones = models.Date{}, models.Date{}, nil
+
+	tests := []struct {
+		schema string
+		value  any
+	}{
+		{"IpaProject", project.Map()},
+		{"IpaProject", personData.Map()},
+		{"LoginResponse", gin.H{"project": project.Map()}},
+		{"GradeResult", grade.CalculateGrade(project.Criteria)},
+		{"GradeResult", grade.CalculateGrade(nil)},
+		{"Timeline", timeline.Build(project, models.Date{Year: 2026, Month: 5, Day: 6})},
+		{"Timeline", timeline.Build(personData, models.Date{Year: 2026, Month: 5, Day: 6})},
+		{"Journal", journal.Summarize(project, 8)},
+		{"Journal", journal.Summarize(personData, 8)},
+		{"JournalEntry", project.Journal[0]},
+		{"Plan", plan.Build(project)},
+		{"Plan", plan.Build(personData)},
+		{"Task", project.Tasks[0]},
+		{"Evidence", project.Evidence[0]},
+		{"Evidence", project.Evidence[1]},
+		{"EvidenceReport", evidence.Report(project)},
+		{"Criterion", notes.RenderCriteria(project.Criteria)[0]},
+		{"CriterionNotes", models.CriterionNotes{CriterionID: criteria[0].ID, Notes: criteria[0].Notes, NotesHTML: notes.Render(criteria[0].Notes),
+			Revisions: notes.History(project, criteria[0].ID)}},
+		{"Workflow", workflow.Build(project, candidate)},
+		{"Workflow", workflow.Build(graded, expert)},
+		{"Archive", archive.New([]models.MongoIpaProject{graded}, "2025.1", false)},
+		{"SnapshotVerification", signer.VerifyProject(graded)},
+		{"CohortStatistics", cohort.Build([]models.MongoIpaProject{project, personData}, cohort.DefaultTop)},
+		{"CohortStatistics", cohort.Build(nil, cohort.DefaultTop)},
+		{"SnapshotVerification", signer.VerifyProject(tampered)},
+		{"SnapshotVerification", signer.VerifyProject(personData)},
+		{"SpreadsheetImport", imported},
+		{"Comment", project.Comments[0]},
+		{"CommentThread", comment.Threads(project, criteria[0].ID, candidate)[0]},
+		{"CommentSummary", comment.Summary(project, expert)},
+		{"CommentSummary", comment.Summary(personData, expert)},
+		{"ExpertToken", models.ExpertToken{ProjectID: "K7QX2M3", Name: "Eva Experte", Token: "abc.def", ExpiresAt: time.Date(2026, 5, 7, 9, 0, 0, 0, time.UTC)}},
+		{"SelectionResult", selection.Validate(criteriaStore.GetSelectionRules(i18n.Default), project.Criteria)},
+		{"ProjectPage", admin.ListProjects([]models.MongoIpaProject{project}, admin.ProjectQuery{Page: 1, PageSize: 20})},
+		{"ProjectPage", admin.ListProjects(nil, admin.ProjectQuery{Page: 1, PageSize: 20})},
+		{"RosterImportResult", gin.H{"projects": []models.ProjectCredentials{{ID: "K7QX2M3", Password: "abc"}}}},
+		{"Archive", archive.New([]models.MongoIpaProject{project}, "2025.1", true)},
+		{"ImportReport", archive.ImportReport{Projects: []archive.ImportedProject{{OriginalID: "AA01", ID: "K7QX2M3", Conflict: "id already exists"}}, Warnings: []string{}}},
+		{"HealthReport", health.Report{Status: health.StatusUnavailable, Checks: map[string]health.Result{"mongodb": {Status: health.StatusUnavailable, Error: "timeout"}}}},
+		{"Problem", Problem{Type: "urn:criteria-catalogue:error:invalid_roster", Title: errorTitles[CodeInvalidRoster].In(i18n.German), Status: 400,
+			Detail: "Klassenliste enthält ungültige Zeilen", Instance: "/api/admin/projects/import", Code: CodeInvalidRoster,
+			RequestID: "abc", Rows: []admin.RowError{{Row: 2, Message: "Vorname fehlt"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			schema := doc.Components.Schemas[tt.schema]
+			if schema == nil {
+				t.Fatalf("openapi.json has no schema %s", tt.schema)
+			}
+			data, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			var decoded any
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if err := schema.Value.VisitJSON(decoded); err != nil {
+				t.Errorf("%s does not match schema %s: %v", data, tt.schema, err)
+			}
+		})
+	}
+}
+
+func TestRequestValidationMiddleware(t *testing.T) {
+	validation, err := RequestValidationMiddleware(loadTestDocument(t))
+	if err != nil {
+		t.Fatalf("RequestValidationMiddleware() error = %v", err)
+	}
+	engine := newTestRouter(t, validation)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		wantStatus  int
+		wantMessage string
+	}{
+		{"wrong type in body", "POST", "/api/ipa/login", `{"id": 5, "password": "x"}`, http.StatusBadRequest, `"detail":"id: `},
+		{"missing required field", "POST", "/api/ipa", `{"firstname": "Anna"}`, http.StatusBadRequest, `"code":"invalid_request"`},
+		{"invalid query parameter", "GET", "/api/admin/projects?pageSize=abc", "", http.StatusBadRequest, `"detail":"Parameter pageSize`},
+		{"valid request reaches handler", "POST", "/api/ipa/logout", "", http.StatusOK, ""},
+		{"authentication is left to the middleware", "GET", "/api/admin/projects?pageSize=10", "", http.StatusUnauthorized, "Autorisierung erforderlich"},
+		{"unknown route", "GET", "/unknown", "", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+			if recorder.Code != tt.wantStatus || !strings.Contains(recorder.Body.String(), tt.wantMessage) {
+				t.Errorf("%s %s = %d %s, want %d containing %q", tt.method, tt.path, recorder.Code, recorder.Body, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestErrorCodesMatchOpenAPI(t *testing.T) {
+	documented := loadTestDocument(t).Components.Schemas["Problem"].Value.Properties["code"].Value.Enum
+	if len(documented) != len(errorTitles) {
+		t.Errorf("openapi.json documents %d error codes, errorTitles has %d", len(documented), len(errorTitles))
+	}
+	for _, code := range documented {
+		if _, ok := errorTitles[ErrorCode(code.(string))]; !ok {
+			t.Errorf("error code %v has no title", code)
+		}
+	}
+}
+
+// Ensure unknown routes are reported as such by the OpenAPI router used in the middleware.
+func TestOpenAPIRouterRejectsUnknownRoutes(t *testing.T) {
+	router, err := gorillamux.NewRouter(loadTestDocument(t))
+	if err != nil {
+		t.Fatalf("gorillamux.NewRouter() error = %v", err)
+	}
+	if _, _, err := router.FindRoute(httptest.NewRequest("GET", "/api/unknown", nil)); err != routers.ErrPathNotFound {
+		t.Errorf("FindRoute() error = %v, want ErrPathNotFound", err)
+	}
+}
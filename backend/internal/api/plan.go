@@ -0,0 +1,120 @@
+package api
+
+import (
+	"crypto/rand"
+	"errors"
+	"net/http"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/plan"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPlanHandler liefert den Zeitplan mit den Abweichungen pro Aufgabe und Tag.
+func (h *Handlers) GetPlanHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	c.JSON(http.StatusOK, plan.Build(*project))
+}
+
+// CreateTaskHandler fügt dem Zeitplan eine Aufgabe hinzu.
+func (h *Handlers) CreateTaskHandler(c *gin.Context) {
+	var task models.Task
+	if err := c.ShouldBindJSON(&task); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+
+	task.ID = rand.Text()
+	if err := plan.Validate(task, *project); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidTask, planDetail(c, err))
+		return
+	}
+
+	if _, err := h.MongoStore.AddTask(c.Request.Context(), project.ID, task); err != nil {
+		respondStoreError(c, "adding task failed", err, CodeProjectNotFound)
+		return
+	}
+	c.JSON(http.StatusCreated, task)
+}
+
+// UpdateTaskHandler ersetzt eine Aufgabe im Zeitplan.
+func (h *Handlers) UpdateTaskHandler(c *gin.Context) {
+	var task models.Task
+	if err := c.ShouldBindJSON(&task); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+
+	task.ID = c.Param("taskId") // Ensure the ID cannot be changed
+	if err := plan.Validate(task, *project); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidTask, planDetail(c, err))
+		return
+	}
+
+	if _, err := h.MongoStore.UpdateTask(c.Request.Context(), project.ID, task); err != nil {
+		respondStoreError(c, "updating task failed", err, CodeTaskNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// DeleteTaskHandler löscht eine Aufgabe aus dem Zeitplan.
+func (h *Handlers) DeleteTaskHandler(c *gin.Context) {
+	_, err := h.MongoStore.DeleteTask(c.Request.Context(), c.Param("id"), c.Param("taskId"))
+	if err != nil {
+		respondStoreError(c, "deleting task failed", err, CodeTaskNotFound)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// planDetail beschreibt einen Fehler aus plan.Validate in der Sprache der Anfrage.
+func planDetail(c *gin.Context, err error) string {
+	for cause, msg := range planMessages {
+		if errors.Is(err, cause) {
+			return localize(c, msg)
+		}
+	}
+	return ""
+}
+
+// planMessages enthält die Meldungen zu den Fehlern aus plan.Validate.
+var planMessages = map[error]i18n.Text{
+	plan.ErrTitleMissing: {
+		i18n.German:  "Der Titel fehlt",
+		i18n.French:  "Le titre est manquant",
+		i18n.Italian: "Il titolo manca",
+	},
+	plan.ErrStartMissing: {
+		i18n.German:  "Der geplante Beginn fehlt",
+		i18n.French:  "Le début prévu est manquant",
+		i18n.Italian: "L'inizio previsto manca",
+	},
+	plan.ErrDuration: {
+		i18n.German:  "Die Dauer muss zwischen 1 und 120 Tagen liegen",
+		i18n.French:  "La durée doit être comprise entre 1 et 120 jours",
+		i18n.Italian: "La durata deve essere compresa tra 1 e 120 giorni",
+	},
+	plan.ErrHours: {
+		i18n.German:  "Die geplanten Stunden müssen grösser als 0 sein und in die Dauer passen",
+		i18n.French:  "Les heures prévues doivent être supérieures à 0 et tenir dans la durée",
+		i18n.Italian: "Le ore previste devono essere maggiori di 0 e rientrare nella durata",
+	},
+	plan.ErrOutsidePeriod: {
+		i18n.German:  "Die Aufgabe liegt ausserhalb des IPA-Zeitraums",
+		i18n.French:  "La tâche est en dehors de la période TPI",
+		i18n.Italian: "Il compito è al di fuori del periodo LPI",
+	},
+}
@@ -0,0 +1,385 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/admin"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ProblemContentType ist der Content-Type aller Fehlerantworten (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// ErrorCode bezeichnet eine Fehlerart. Die Codes sind Teil der API und ändern sich nicht,
+// Clients sollen auf den Code reagieren statt auf den Text.
+type ErrorCode string
+
+const (
+	CodeInvalidRequest         ErrorCode = "invalid_request"
+	CodeInvalidProjectID       ErrorCode = "invalid_project_id"
+	CodePasswordRequired       ErrorCode = "password_required"
+	CodePasswordUnchanged      ErrorCode = "password_unchanged"
+	CodeUnauthorized           ErrorCode = "unauthorized"
+	CodeInvalidToken           ErrorCode = "invalid_token"
+	CodeInvalidCredentials     ErrorCode = "invalid_credentials"
+	CodeWrongPassword          ErrorCode = "wrong_password"
+	CodeForbidden              ErrorCode = "forbidden"
+	CodePasswordChangeRequired ErrorCode = "password_change_required"
+	CodeNotCommentAuthor       ErrorCode = "not_comment_author"
+	CodeTransitionForbidden    ErrorCode = "transition_forbidden"
+	CodeAdminDisabled          ErrorCode = "admin_disabled"
+	CodeProjectNotFound        ErrorCode = "project_not_found"
+	CodeCriterionNotFound      ErrorCode = "criterion_not_found"
+	CodeCriterionExists        ErrorCode = "criterion_exists"
+	CodeJournalEntryNotFound   ErrorCode = "journal_entry_not_found"
+	CodeTaskNotFound           ErrorCode = "task_not_found"
+	CodeEvidenceNotFound       ErrorCode = "evidence_not_found"
+	CodeCommentNotFound        ErrorCode = "comment_not_found"
+	CodeRevisionNotFound       ErrorCode = "revision_not_found"
+	CodeProjectLocked          ErrorCode = "project_locked"
+	CodeInvalidTransition      ErrorCode = "invalid_transition"
+	CodeConflict               ErrorCode = "conflict"
+	CodeInvalidTimeline        ErrorCode = "invalid_timeline"
+	CodeInvalidJournalEntry    ErrorCode = "invalid_journal_entry"
+	CodeInvalidTask            ErrorCode = "invalid_task"
+	CodeInvalidEvidence        ErrorCode = "invalid_evidence"
+	CodeInvalidComment         ErrorCode = "invalid_comment"
+	CodeInvalidNotes           ErrorCode = "invalid_notes"
+	CodeInvalidRoster          ErrorCode = "invalid_roster"
+	CodeInvalidArchive         ErrorCode = "invalid_archive"
+	CodeInvalidSpreadsheet     ErrorCode = "invalid_spreadsheet"
+	CodePayloadTooLarge        ErrorCode = "payload_too_large"
+	CodeInvalidCatalogue       ErrorCode = "invalid_catalogue"
+	CodeNotImplemented         ErrorCode = "not_implemented"
+	CodeInternal               ErrorCode = "internal_error"
+)
+
+// errorTitles enthält die Meldung zu jedem Code in jeder Sprache. Der Titel ist für alle Fehler
+// eines Codes gleich, Angaben zum Einzelfall stehen in Problem.Detail.
+var errorTitles = map[ErrorCode]i18n.Text{
+	CodeInvalidRequest: {
+		i18n.German:  "Ungültige Anfrage",
+		i18n.French:  "Requête invalide",
+		i18n.Italian: "Richiesta non valida",
+	},
+	CodeInvalidProjectID: {
+		i18n.German:  "Ungültige Projekt-ID, bitte Eingabe prüfen",
+		i18n.French:  "ID de projet invalide, veuillez vérifier la saisie",
+		i18n.Italian: "ID del progetto non valido, verificare l'inserimento",
+	},
+	CodePasswordRequired: {
+		i18n.German:  "Passwort ist erforderlich",
+		i18n.French:  "Le mot de passe est obligatoire",
+		i18n.Italian: "La password è obbligatoria",
+	},
+	CodePasswordUnchanged: {
+		i18n.German:  "Das neue Passwort muss sich vom aktuellen unterscheiden",
+		i18n.French:  "Le nouveau mot de passe doit être différent de l'actuel",
+		i18n.Italian: "La nuova password deve essere diversa da quella attuale",
+	},
+	CodeUnauthorized: {
+		i18n.German:  "Autorisierung erforderlich",
+		i18n.French:  "Autorisation requise",
+		i18n.Italian: "Autorizzazione necessaria",
+	},
+	CodeInvalidToken: {
+		i18n.German:  "Ungültiger oder abgelaufener Token",
+		i18n.French:  "Jeton invalide ou expiré",
+		i18n.Italian: "Token non valido o scaduto",
+	},
+	CodeInvalidCredentials: {
+		i18n.German:  "Ungültige Anmeldedaten",
+		i18n.French:  "Identifiants invalides",
+		i18n.Italian: "Credenziali non valide",
+	},
+	CodeWrongPassword: {
+		i18n.German:  "Aktuelles Passwort ist falsch",
+		i18n.French:  "Le mot de passe actuel est incorrect",
+		i18n.Italian: "La password attuale non è corretta",
+	},
+	CodeForbidden: {
+		i18n.German:  "Kein Zugriff auf dieses Projekt",
+		i18n.French:  "Pas d'accès à ce projet",
+		i18n.Italian: "Nessun accesso a questo progetto",
+	},
+	CodePasswordChangeRequired: {
+		i18n.German:  "Bitte zuerst das Einmalpasswort ändern",
+		i18n.French:  "Veuillez d'abord changer le mot de passe à usage unique",
+		i18n.Italian: "Modificare prima la password monouso",
+	},
+	CodeNotCommentAuthor: {
+		i18n.German:  "Nur der Autor kann den Kommentar ändern",
+		i18n.French:  "Seul l'auteur peut modifier le commentaire",
+		i18n.Italian: "Solo l'autore può modificare il commento",
+	},
+	CodeTransitionForbidden: {
+		i18n.German:  "Statuswechsel für diese Rolle nicht erlaubt",
+		i18n.French:  "Changement de statut non autorisé pour ce rôle",
+		i18n.Italian: "Cambio di stato non consentito per questo ruolo",
+	},
+	CodeAdminDisabled: {
+		i18n.German:  "Administrations-API ist nicht aktiviert",
+		i18n.French:  "L'API d'administration n'est pas activée",
+		i18n.Italian: "L'API di amministrazione non è attivata",
+	},
+	CodeProjectNotFound: {
+		i18n.German:  "Kein IPA-Projekt gefunden",
+		i18n.French:  "Aucun projet TPI trouvé",
+		i18n.Italian: "Nessun progetto LPI trovato",
+	},
+	CodeCriterionNotFound: {
+		i18n.German:  "Kriterium nicht gefunden",
+		i18n.French:  "Critère introuvable",
+		i18n.Italian: "Criterio non trovato",
+	},
+	CodeCriterionExists: {
+		i18n.German:  "Ein Kriterium mit dieser ID existiert bereits",
+		i18n.French:  "Un critère avec cet ID existe déjà",
+		i18n.Italian: "Esiste già un criterio con questo ID",
+	},
+	CodeJournalEntryNotFound: {
+		i18n.German:  "Journaleintrag nicht gefunden",
+		i18n.French:  "Entrée de journal introuvable",
+		i18n.Italian: "Voce del diario non trovata",
+	},
+	CodeTaskNotFound: {
+		i18n.German:  "Aufgabe nicht gefunden",
+		i18n.French:  "Tâche introuvable",
+		i18n.Italian: "Compito non trovato",
+	},
+	CodeEvidenceNotFound: {
+		i18n.German:  "Beleg nicht gefunden",
+		i18n.French:  "Justificatif introuvable",
+		i18n.Italian: "Giustificativo non trovato",
+	},
+	CodeCommentNotFound: {
+		i18n.German:  "Kommentar nicht gefunden",
+		i18n.French:  "Commentaire introuvable",
+		i18n.Italian: "Commento non trovato",
+	},
+	CodeRevisionNotFound: {
+		i18n.German:  "Frühere Fassung der Notizen nicht gefunden",
+		i18n.French:  "Version antérieure des notes introuvable",
+		i18n.Italian: "Versione precedente delle note non trovata",
+	},
+	CodeProjectLocked: {
+		i18n.German:  "Das Projekt ist gesperrt",
+		i18n.French:  "Le projet est verrouillé",
+		i18n.Italian: "Il progetto è bloccato",
+	},
+	CodeInvalidTransition: {
+		i18n.German:  "Statuswechsel nicht möglich",
+		i18n.French:  "Changement de statut impossible",
+		i18n.Italian: "Cambio di stato non possibile",
+	},
+	CodeConflict: {
+		i18n.German:  "Die Daten widersprechen einem bestehenden Eintrag",
+		i18n.French:  "Les données sont en conflit avec une entrée existante",
+		i18n.Italian: "I dati sono in conflitto con una voce esistente",
+	},
+	CodeInvalidTimeline: {
+		i18n.German:  "Ungültiger IPA-Zeitraum oder Meilenstein",
+		i18n.French:  "Période TPI ou jalon invalide",
+		i18n.Italian: "Periodo LPI o traguardo non valido",
+	},
+	CodeInvalidJournalEntry: {
+		i18n.German:  "Ungültiger Journaleintrag",
+		i18n.French:  "Entrée de journal invalide",
+		i18n.Italian: "Voce del diario non valida",
+	},
+	CodeInvalidTask: {
+		i18n.German:  "Ungültige Aufgabe",
+		i18n.French:  "Tâche invalide",
+		i18n.Italian: "Compito non valido",
+	},
+	CodeInvalidEvidence: {
+		i18n.German:  "Ungültiger Beleg",
+		i18n.French:  "Justificatif invalide",
+		i18n.Italian: "Giustificativo non valido",
+	},
+	CodeInvalidComment: {
+		i18n.German:  "Ungültiger Kommentar",
+		i18n.French:  "Commentaire invalide",
+		i18n.Italian: "Commento non valido",
+	},
+	CodeInvalidNotes: {
+		i18n.German:  "Ungültige Notizen",
+		i18n.French:  "Notes invalides",
+		i18n.Italian: "Note non valide",
+	},
+	CodeInvalidRoster: {
+		i18n.German:  "Ungültige Klassenliste",
+		i18n.French:  "Liste de classe invalide",
+		i18n.Italian: "Elenco della classe non valido",
+	},
+	CodeInvalidArchive: {
+		i18n.German:  "Ungültiges Archiv",
+		i18n.French:  "Archive invalide",
+		i18n.Italian: "Archivio non valido",
+	},
+	CodeInvalidSpreadsheet: {
+		i18n.German:  "Ungültige Tabelle",
+		i18n.French:  "Tableau invalide",
+		i18n.Italian: "Tabella non valida",
+	},
+	CodePayloadTooLarge: {
+		i18n.German:  "Die hochgeladene Datei ist zu gross",
+		i18n.French:  "Le fichier téléversé est trop volumineux",
+		i18n.Italian: "Il file caricato è troppo grande",
+	},
+	CodeInvalidCatalogue: {
+		i18n.German:  "Kriterienkatalog konnte nicht geladen werden, der bisherige Katalog bleibt aktiv",
+		i18n.French:  "Le catalogue de critères n'a pas pu être chargé, le catalogue précédent reste actif",
+		i18n.Italian: "Impossibile caricare il catalogo dei criteri, il catalogo precedente rimane attivo",
+	},
+	CodeNotImplemented: {
+		i18n.German:  "Dieser Endpunkt ist noch nicht umgesetzt",
+		i18n.French:  "Ce point de terminaison n'est pas encore implémenté",
+		i18n.Italian: "Questo endpoint non è ancora implementato",
+	},
+	CodeInternal: {
+		i18n.German:  "Interner Fehler, bitte später erneut versuchen",
+		i18n.French:  "Erreur interne, veuillez réessayer plus tard",
+		i18n.Italian: "Errore interno, riprovare più tardi",
+	},
+}
+
+// Problem ist eine Fehlerantwort nach RFC 7807. Neben den Standardfeldern enthält sie den
+// stabilen Code und die Request-ID, mit der sich der Fehler im Log finden lässt.
+type Problem struct {
+	Type      string           `json:"type"`
+	Title     string           `json:"title"`
+	Status    int              `json:"status"`
+	Detail    string           `json:"detail,omitempty"`
+	Instance  string           `json:"instance,omitempty"`
+	Code      ErrorCode        `json:"code"`
+	RequestID string           `json:"requestId,omitempty"`
+	Rows      []admin.RowError `json:"rows,omitempty"` // Fehlerhafte Zeilen einer Klassenliste oder Tabelle
+}
+
+// newProblem erstellt das Problem zu code für die aktuelle Anfrage.
+func newProblem(c *gin.Context, status int, code ErrorCode, detail string) Problem {
+	return Problem{
+		Type:      "urn:criteria-catalogue:error:" + string(code),
+		Title:     errorTitles[code].In(requestLanguage(c)),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		RequestID: c.Writer.Header().Get(RequestIDHeader),
+	}
+}
+
+// writeProblem sendet p und bricht die Verarbeitung der Anfrage ab.
+func writeProblem(c *gin.Context, p Problem) {
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// respondProblem sendet eine Fehlerantwort. detail darf keine internen Fehlertexte enthalten.
+func respondProblem(c *gin.Context, status int, code ErrorCode, detail string) {
+	writeProblem(c, newProblem(c, status, code, detail))
+}
+
+// respondInternalError protokolliert err und sendet eine Fehlerantwort ohne Einzelheiten.
+func respondInternalError(c *gin.Context, msg string, err error) {
+	requestLogger(c).Error(msg, "error", err)
+	respondProblem(c, http.StatusInternalServerError, CodeInternal, "")
+}
+
+// respondStoreError bildet einen Fehler des Stores auf die passende Fehlerantwort ab.
+// notFound ist der Code, falls das gesuchte Objekt nicht existiert. Ist das Projekt inzwischen
+// gesperrt, wird wie bei requireEditable geantwortet.
+func respondStoreError(c *gin.Context, msg string, err error, notFound ErrorCode) {
+	var locked *store.LockedError
+	switch {
+	case errors.As(err, &locked):
+		requestLogger(c).Info(msg, "error", err)
+		respondLocked(c, locked.Status)
+	case errors.Is(err, store.ErrNotFound):
+		requestLogger(c).Info(msg, "error", err)
+		respondProblem(c, http.StatusNotFound, notFound, "")
+	case errors.Is(err, store.ErrConflict):
+		requestLogger(c).Info(msg, "error", err)
+		respondProblem(c, http.StatusConflict, CodeConflict, "")
+	case errors.Is(err, store.ErrValidation):
+		respondProblem(c, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+	default:
+		respondInternalError(c, msg, err)
+	}
+}
+
+// respondBindingError beantwortet ungültige JSON-Eingaben. Die Meldung nennt nur das Feld,
+// nicht die Go-Typen aus den Fehlern von encoding/json und validator.
+func respondBindingError(c *gin.Context, err error) {
+	respondProblem(c, http.StatusBadRequest, CodeInvalidRequest, bindingDetail(c, err))
+}
+
+func bindingDetail(c *gin.Context, err error) string {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var dateErr *models.DateError
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]string, len(validationErrs))
+		for i, fieldErr := range validationErrs {
+			fields[i] = jsonFieldName(fieldErr.Field())
+		}
+		return localize(c, msgInvalidFields, strings.Join(fields, ", "))
+	case errors.As(err, &typeErr):
+		return localize(c, msgInvalidFieldType, typeErr.Field)
+	case errors.As(err, &dateErr):
+		return localize(c, msgInvalidDate, dateErr.Value)
+	}
+	return localize(c, msgInvalidJSON) // Syntaxfehler oder leerer Inhalt
+}
+
+// contentDetail beschreibt einen Fehler im Inhalt einer hochgeladenen Datei oder einer Anfrage
+// mit der passenden Meldung aus messages. Die Args eines admin.ContentError werden eingesetzt.
+func contentDetail(c *gin.Context, err error, messages map[error]i18n.Text) string {
+	var args []any
+	var contentErr *admin.ContentError
+	if errors.As(err, &contentErr) {
+		args = contentErr.Args
+	}
+	for cause, msg := range messages {
+		if errors.Is(err, cause) {
+			return localize(c, msg, args...)
+		}
+	}
+	return ""
+}
+
+// localizedRows übersetzt die Fehler der Zeilen einer Klassenliste oder Tabelle mit messages.
+func localizedRows(c *gin.Context, rows []admin.RowError, messages map[error]i18n.Text) []admin.RowError {
+	localized := make([]admin.RowError, len(rows))
+	for i, row := range rows {
+		texts := make([]string, len(row.Problems))
+		for j, problem := range row.Problems {
+			texts[j] = contentDetail(c, problem, messages)
+		}
+		localized[i] = admin.RowError{Row: row.Row, Message: strings.Join(texts, "; ")}
+	}
+	return localized
+}
+
+// jsonFieldName leitet den JSON-Namen aus dem Go-Feldnamen ab (CurrentPassword -> currentPassword).
+func jsonFieldName(field string) string {
+	r, size := utf8.DecodeRuneInString(field)
+	return string(unicode.ToLower(r)) + field[size:]
+}
+
+// RecoverPanic beantwortet eine abgefangene Panic mit einem internen Fehler (für gin.CustomRecovery).
+func RecoverPanic(c *gin.Context, err any) {
+	requestLogger(c).Error("panic recovered", "error", err)
+	respondProblem(c, http.StatusInternalServerError, CodeInternal, "")
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/admin"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/archive"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/spreadsheet"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/store"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/timeline"
+	"github.com/gin-gonic/gin"
+)
+
+// serveProblem runs respond inside a request with request logger and returns the decoded problem.
+func serveProblem(t *testing.T, respond gin.HandlerFunc) (*httptest.ResponseRecorder, Problem) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestLoggerMiddleware())
+	r.POST("/api/ipa/:id", respond)
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest("POST", "/api/ipa/K7QX2M3", strings.NewReader(`{"id": 5}`)))
+	var problem Problem
+	if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decoding problem %q: %v", recorder.Body, err)
+	}
+	return recorder, problem
+}
+
+func TestRespondStoreError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   ErrorCode
+	}{
+		{"not found", store.ErrNotFound, http.StatusNotFound, CodeCriterionNotFound},
+		{"conflict", fmt.Errorf("%w: E11000 duplicate key error collection: criteria-catalogue.user-data", store.ErrConflict), http.StatusConflict, CodeConflict},
+		{"locked", fmt.Errorf("updating criterion: %w", &store.LockedError{Status: models.StatusSubmitted}), http.StatusConflict, CodeProjectLocked},
+		{"internal", errors.New("connection(mongo:27017) incomplete read of message header"), http.StatusInternalServerError, CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, problem := serveProblem(t, func(c *gin.Context) {
+				respondStoreError(c, "operation failed", tt.err, CodeCriterionNotFound)
+			})
+
+			if recorder.Code != tt.wantStatus || problem.Status != tt.wantStatus || problem.Code != tt.wantCode {
+				t.Errorf("got %d %+v, want %d with code %s", recorder.Code, problem, tt.wantStatus, tt.wantCode)
+			}
+			if got := recorder.Header().Get("Content-Type"); got != ProblemContentType {
+				t.Errorf("Content-Type = %q, want %q", got, ProblemContentType)
+			}
+			if problem.Title != errorTitles[tt.wantCode].In(i18n.German) || problem.Type != "urn:criteria-catalogue:error:"+string(tt.wantCode) {
+				t.Errorf("problem %+v lacks title or type of code %s", problem, tt.wantCode)
+			}
+			if problem.Instance != "/api/ipa/K7QX2M3" || problem.RequestID != recorder.Header().Get(RequestIDHeader) {
+				t.Errorf("problem %+v lacks instance or request ID", problem)
+			}
+			if strings.Contains(recorder.Body.String(), "mongo") || strings.Contains(recorder.Body.String(), "E11000") {
+				t.Errorf("internal error text exposed: %s", recorder.Body)
+			}
+		})
+	}
+}
+
+func TestRespondBindingError(t *testing.T) {
+	_, problem := serveProblem(t, func(c *gin.Context) {
+		var login struct {
+			ID string `json:"id"`
+		}
+		respondBindingError(c, c.ShouldBindJSON(&login))
+	})
+	if problem.Code != CodeInvalidRequest || problem.Detail != "Feld id hat einen ungültigen Typ" {
+		t.Errorf("got %+v, want invalid_request for field id", problem)
+	}
+}
+
+func TestRespondBindingErrorNamesInvalidDate(t *testing.T) {
+	_, problem := serveProblem(t, func(c *gin.Context) {
+		var input models.TimelineInput
+		c.Request.Body = io.NopCloser(strings.NewReader(`{"startDate": "2026-02-30"}`))
+		respondBindingError(c, c.ShouldBindJSON(&input))
+	})
+	if problem.Code != CodeInvalidRequest || problem.Detail != "Ungültiges Datum: 2026-02-30" {
+		t.Errorf("got %+v, want invalid_request for date 2026-02-30", problem)
+	}
+}
+
+func TestTimelineDetail(t *testing.T) {
+	_, problem := serveProblem(t, func(c *gin.Context) {
+		c.Request.Header.Set("Accept-Language", "fr")
+		err := &timeline.MilestoneError{Index: 1, Err: timeline.ErrMilestoneDate}
+		respondProblem(c, http.StatusBadRequest, CodeInvalidTimeline, timelineDetail(c, err))
+	})
+	if problem.Detail != "Jalon 2 : La date est manquante ou en dehors de la période TPI" {
+		t.Errorf("detail = %q", problem.Detail)
+	}
+}
+
+func TestArchiveDetail(t *testing.T) {
+	_, problem := serveProblem(t, func(c *gin.Context) {
+		c.Request.Header.Set("Accept-Language", "it")
+		_, err := archive.Read([]byte(`{"formatVersion": 99}`))
+		respondProblem(c, http.StatusBadRequest, CodeInvalidArchive, archiveDetail(c, err))
+	})
+	if problem.Detail != "La versione del formato dell'archivio non è supportata" {
+		t.Errorf("detail = %q", problem.Detail)
+	}
+}
+
+func TestRosterRowsAreLocalized(t *testing.T) {
+	_, err := admin.ParseRoster(strings.NewReader("firstname,lastname,topic,date\n,Muster,Webshop,2025-05-12\nBeat,Meier,API,morgen\nbeat,meier,API,2025-05-12\n"))
+	var rosterErr *admin.RosterError
+	if !errors.As(err, &rosterErr) {
+		t.Fatalf("ParseRoster() = %v, want *admin.RosterError", err)
+	}
+	_, problem := serveProblem(t, func(c *gin.Context) {
+		c.Request.Header.Set("Accept-Language", "fr")
+		problem := newProblem(c, http.StatusBadRequest, CodeInvalidRoster, "")
+		problem.Rows = localizedRows(c, rosterErr.Rows, rosterMessages)
+		writeProblem(c, problem)
+	})
+	want := []admin.RowError{
+		{Row: 2, Message: "Le prénom est manquant"},
+		{Row: 3, Message: `Date invalide "morgen"`},
+		{Row: 4, Message: "Entrée en double, voir ligne 3"},
+	}
+	if !reflect.DeepEqual(problem.Rows, want) {
+		t.Errorf("rows = %+v, want %+v", problem.Rows, want)
+	}
+}
+
+func TestSpreadsheetDetail(t *testing.T) {
+	_, problem := serveProblem(t, func(c *gin.Context) {
+		c.Request.Header.Set("Accept-Language", "it")
+		_, err := spreadsheet.Read([]byte("id;notes\nA01;ok\n"))
+		respondSpreadsheetError(c, err)
+	})
+	if problem.Code != CodeInvalidSpreadsheet || problem.Detail != `Manca la colonna "checked"` {
+		t.Errorf("got %+v, want the missing column checked", problem)
+	}
+}
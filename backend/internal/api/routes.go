@@ -1,6 +1,9 @@
 package api
 
 import (
+	"net/http"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/metrics"
 	"github.com/Liuuner/criteria-catalogue/backend/internal/store"
 	"github.com/gin-gonic/gin"
 )
@@ -10,23 +13,85 @@ func SetupRouter(r *gin.Engine, h *Handlers, mongoStore *store.MongoStore) {
 	api := r.Group("/api")
 	{
 		// Public routes (no authentication required)
-		api.POST("/ipa", h.CreateIpaProjectHandler)          // Erstellt neues IPA-Projekt (Personendaten + Basiskriterien) von Personendaten
-		api.POST("/ipa/login", h.LoginHandler)               // Login to an existing IPA project
-		api.POST("/ipa/logout", h.LogoutHandler)             // Logout (clears auth cookie)
-		api.GET("/criteria", h.GetPredefinedCriteriaHandler) // Holt alle verfügbaren Kriterien aus der JSON-Datei
+		api.POST("/ipa", h.CreateIpaProjectHandler)            // Erstellt neues IPA-Projekt (Personendaten + Basiskriterien) von Personendaten
+		api.POST("/ipa/login", h.LoginHandler)                 // Login to an existing IPA project
+		api.POST("/ipa/logout", h.LogoutHandler)               // Logout (clears auth cookie)
+		api.GET("/criteria", h.GetPredefinedCriteriaHandler)   // Holt alle verfügbaren Kriterien aus der JSON-Datei
+		api.GET("/criteria/rules", h.GetSelectionRulesHandler) // Holt die Auswahlregeln für optionale Kriterien
+		api.GET("/criteria/search", h.SearchCriteriaHandler)   // Durchsucht den Kriterienkatalog
+		api.GET("/openapi.json", h.OpenAPIHandler)             // Liefert die OpenAPI-Beschreibung dieser API
 
 		// Protected routes (authentication required)
 		protected := api.Group("/ipa/:id")
 		protected.Use(AuthMiddleware(mongoStore))
 		{
-			protected.GET("", h.GetIpaProjectHandler)                             // Holt gesamtes IPA-Projekt (Personendaten + Kriterien)
-			protected.GET("/criteria", h.GetIpaCriteriaHandler)                   // Holt Kriterien einer bestimmten IPA
-			protected.POST("/criteria", h.CreateIpaCriteriaHandler)               // Fügt ein neues Kriterium zu einer bestimmten IPA hinzu
-			protected.PUT("/criteria/:criteriaId", h.UpdateIpaCriteriaHandler)    // Aktualisiert ein Kriterium einer bestimmten IPA
-			protected.DELETE("/criteria/:criteriaId", h.DeleteIpaCriteriaHandler) // Löscht ein Kriterium aus einer bestimmten IPA
-			protected.GET("/person-data", h.GetPersonDataHandler)                 // Holt die Personendaten für die IPA mit der angegebenen ID
-			protected.PUT("/person-data", h.UpdatePersonDataHandler)              // Aktualisiert die Personendaten für die IPA mit der angegebenen ID
-			protected.GET("/grade", h.GetGradeHandler)                            // Calculates and returns the grade for the IPA project with the given ID
+			protected.GET("", h.GetIpaProjectHandler)                                                                 // Holt gesamtes IPA-Projekt (Personendaten + Kriterien)
+			protected.GET("/criteria", h.GetIpaCriteriaHandler)                                                       // Holt Kriterien einer bestimmten IPA
+			protected.POST("/criteria", h.CreateIpaCriteriaHandler)                                                   // Fügt ein neues Kriterium zu einer bestimmten IPA hinzu
+			protected.PUT("/criteria/:criteriaId", h.UpdateIpaCriteriaHandler)                                        // Aktualisiert ein Kriterium einer bestimmten IPA
+			protected.DELETE("/criteria/:criteriaId", h.DeleteIpaCriteriaHandler)                                     // Löscht ein Kriterium aus einer bestimmten IPA
+			protected.GET("/criteria/:criteriaId/notes", h.GetNotesHandler)                                           // Notes as Markdown and sanitized HTML with earlier versions
+			protected.POST("/criteria/:criteriaId/notes/revisions/:revisionId/restore", h.RestoreNoteRevisionHandler) // Restores an earlier version of the notes
+			protected.GET("/criteria/:criteriaId/comments", h.ListCommentsHandler)                                    // Comment threads on a criterion
+			protected.POST("/criteria/:criteriaId/comments", h.CreateCommentHandler)                                  // Starts a thread or replies in a thread
+			protected.POST("/criteria/:criteriaId/comments/read", h.MarkCommentsReadHandler)                          // Marks all comments on a criterion as read
+			protected.PUT("/criteria/:criteriaId/comments/:commentId", h.UpdateCommentHandler)                        // Edits an own comment
+			protected.DELETE("/criteria/:criteriaId/comments/:commentId", h.DeleteCommentHandler)                     // Deletes the text of an own comment
+			protected.PUT("/criteria/:criteriaId/comments/:commentId/resolved", h.UpdateThreadStatusHandler)          // Marks a thread as resolved or unresolved
+			protected.GET("/comments", h.GetCommentSummaryHandler)                                                    // Unresolved threads and unread comments per criterion
+			protected.GET("/person-data", h.GetPersonDataHandler)                                                     // Holt die Personendaten für die IPA mit der angegebenen ID
+			protected.PUT("/person-data", h.UpdatePersonDataHandler)                                                  // Aktualisiert die Personendaten für die IPA mit der angegebenen ID
+			protected.GET("/status", h.GetWorkflowHandler)                                                            // Status, allowed transitions and signed grades
+			protected.POST("/status", h.ChangeStatusHandler)                                                          // Moves the project to another status, locks the criteria after submission
+			protected.GET("/grade", h.GetGradeHandler)                                                                // Grade of the latest snapshot once graded, otherwise calculated from the criteria
+			protected.GET("/grade/snapshots", h.ListSnapshotsHandler)                                                 // Hash-chained, signed grade snapshots
+			protected.GET("/grade/snapshots/verify", h.VerifySnapshotsHandler)                                        // Detects later modifications of the grade snapshots
+			protected.GET("/export", h.ExportSpreadsheetHandler)                                                      // Criteria and part grades as CSV or Excel file
+			protected.POST("/import", h.ImportSpreadsheetHandler)                                                     // Checkmarks and notes from an edited export, ?dryRun=true only shows the changes
+			protected.GET("/selection", h.GetSelectionHandler)                                                        // Validates the selection of optional criteria against the catalogue rules
+			protected.PUT("/password", h.ChangePasswordHandler)                                                       // Replaces the (one-time) password of the IPA project
+			protected.GET("/timeline", h.GetTimelineHandler)                                                          // Days remaining and milestone status in the IPA period
+			protected.PUT("/timeline", h.UpdateTimelineHandler)                                                       // Replaces the IPA period and the milestones
+			protected.GET("/journal", h.GetJournalHandler)                                                            // Work journal with hours per day compared with the plan
+			protected.POST("/journal", h.CreateJournalEntryHandler)                                                   // Adds an entry to the work journal
+			protected.PUT("/journal/:entryId", h.UpdateJournalEntryHandler)                                           // Replaces an entry of the work journal
+			protected.DELETE("/journal/:entryId", h.DeleteJournalEntryHandler)                                        // Deletes an entry of the work journal
+			protected.GET("/plan", h.GetPlanHandler)                                                                  // Time plan compared with the journal, ready for a Gantt chart
+			protected.POST("/plan/tasks", h.CreateTaskHandler)                                                        // Adds a task to the time plan
+			protected.PUT("/plan/tasks/:taskId", h.UpdateTaskHandler)                                                 // Replaces a task of the time plan
+			protected.DELETE("/plan/tasks/:taskId", h.DeleteTaskHandler)                                              // Deletes a task, its journal entries become unassigned
+			protected.GET("/evidence", h.ListEvidenceHandler)                                                         // Lists the evidence for the requirements of the criteria
+			protected.GET("/evidence/report", h.GetEvidenceReportHandler)                                             // Evidence per criterion and requirement
+			protected.POST("/evidence/files", h.CreateEvidenceFileHandler)                                            // Uploads a file as evidence for a requirement
+			protected.POST("/evidence/links", h.CreateEvidenceLinkHandler)                                            // Adds a reference to a document page as evidence
+			protected.GET("/evidence/:evidenceId/file", h.DownloadEvidenceHandler)                                    // Downloads the file of an evidence
+			protected.DELETE("/evidence/:evidenceId", h.DeleteEvidenceHandler)                                        // Deletes an evidence and its file
+		}
+
+		// Admin routes (admin token required)
+		admin := api.Group("/admin")
+		admin.Use(AdminMiddleware(h.AdminToken))
+		{
+			admin.GET("/projects", h.ListIpaProjectsHandler)                    // Lists all IPA projects with filtering, sorting and pagination
+			admin.POST("/projects/import", h.ImportRosterHandler)               // Creates IPA projects with one-time passwords from a class roster CSV
+			admin.GET("/statistics", h.GetStatisticsHandler)                    // Grade distributions and criteria statistics over a set of projects as JSON or CSV
+			admin.POST("/projects/:id/archive", h.ArchiveIpaProjectHandler)     // Archives an IPA project
+			admin.POST("/projects/:id/unarchive", h.UnarchiveIpaProjectHandler) // Restores an archived IPA project
+			admin.DELETE("/projects/:id", h.DeleteIpaProjectHandler)            // Deletes an IPA project permanently
+			admin.POST("/projects/:id/expert-token", h.IssueExpertTokenHandler) // Issues a token for an expert of an IPA project
+			admin.GET("/export", h.ExportProjectsHandler)                       // Exports IPA projects as a JSON or ZIP archive
+			admin.POST("/import", h.ImportProjectsHandler)                      // Imports IPA projects from a JSON or ZIP archive
+			admin.POST("/catalogue/reload", h.ReloadCatalogueHandler)           // Reloads the criteria catalogue from the criteria file
 		}
 	}
 }
+
+// SetupSystemRoutes registriert die Routen für Betrieb und Überwachung.
+func SetupSystemRoutes(r *gin.Engine, h *Handlers, version string) {
+	r.GET("/version", func(c *gin.Context) {
+		c.String(http.StatusOK, version)
+	})
+	r.GET("/metrics", gin.WrapH(metrics.Handler())) // Prometheus-Metriken
+	r.GET("/healthz", h.HealthzHandler)             // Liveness: Prozess läuft
+	r.GET("/readyz", h.ReadyzHandler)               // Readiness: MongoDB und Kriterienkatalog verfügbar
+}
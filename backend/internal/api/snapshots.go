@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ListSnapshotsHandler liefert die Momentaufnahmen der Bewertung, die älteste zuerst.
+func (h *Handlers) ListSnapshotsHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	snapshots := project.GradeSnapshots
+	if snapshots == nil {
+		snapshots = make([]models.GradeSnapshot, 0)
+	}
+	c.JSON(http.StatusOK, snapshots)
+}
+
+// VerifySnapshotsHandler prüft Hashes, Signaturen und Verkettung der Momentaufnahmen, zum Umfang
+// siehe snapshot.Signer.VerifyProject. Eine verletzte Kette ist ein Befund und kein Fehler der
+// Anfrage, die Antwort ist deshalb immer 200.
+func (h *Handlers) VerifySnapshotsHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	c.JSON(http.StatusOK, h.Snapshots.VerifyProject(*project))
+}
+
+// gradedSnapshot liefert die Momentaufnahme, die für ein bewertetes oder abgeschlossenes
+// Projekt gilt. Während der Bearbeitung und Bewertung wird die Note laufend berechnet.
+func gradedSnapshot(project models.MongoIpaProject) (models.GradeSnapshot, bool) {
+	status := project.CurrentStatus()
+	if (status != models.StatusGraded && status != models.StatusClosed) || len(project.GradeSnapshots) == 0 {
+		return models.GradeSnapshot{}, false
+	}
+	return project.GradeSnapshots[len(project.GradeSnapshots)-1], true
+}
+
+// localizeGrade übersetzt die Titel der Kriterien in einer festgehaltenen Note, ohne die
+// Momentaufnahme zu verändern.
+func (h *Handlers) localizeGrade(result models.GradeResult, criteria []models.Criterion, lang i18n.Lang) models.GradeResult {
+	titles := make(map[string]string, len(criteria))
+	for _, criterion := range h.JsonStore.LocalizeCriteria(criteria, lang) {
+		titles[criterion.ID] = criterion.Title
+	}
+	for _, part := range []*models.GradeDetails{&result.Part1, &result.Part2} {
+		part.CriterionGrades = slices.Clone(part.CriterionGrades)
+		for i, criterionGrade := range part.CriterionGrades {
+			if title, ok := titles[criterionGrade.CriterionID]; ok {
+				part.CriterionGrades[i].CriterionTitle = title
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/notes"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/spreadsheet"
+	"github.com/gin-gonic/gin"
+)
+
+// maxSpreadsheetSize begrenzt die Grösse einer hochgeladenen Tabelle.
+const maxSpreadsheetSize = 4 << 20
+
+// ExportSpreadsheetHandler liefert die Kriterien mit erfüllten Anforderungen, Gütestufen und
+// Notizen sowie die Noten beider Teile als CSV oder mit ?format=xlsx als Excel-Datei.
+func (h *Handlers) ExportSpreadsheetHandler(c *gin.Context) {
+	format := c.DefaultQuery("format", spreadsheet.FormatCSV)
+	contentType, ok := spreadsheet.ContentTypes[format]
+	if !ok {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidRequest, localize(c, msgInvalidFormat, format))
+		return
+	}
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+
+	lang := requestLanguage(c)
+	result := h.projectGrade(*project, lang)
+	project.Criteria = h.JsonStore.LocalizeCriteria(project.Criteria, lang)
+	now := time.Now().UTC()
+	export := spreadsheet.New(*project, result, now)
+
+	filename := fmt.Sprintf("ipa-%s-%s.%s", project.ID, now.Format("20060102-150405"), format)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	if err := export.Write(c.Writer, format); err != nil {
+		requestLogger(c).Error("writing spreadsheet failed", "error", err)
+	}
+}
+
+// ImportSpreadsheetHandler übernimmt erfüllte Anforderungen und Notizen aus einer Tabelle im
+// Format von ExportSpreadsheetHandler. Mit ?dryRun=true werden die Änderungen nur angezeigt,
+// sonst werden alle gemeinsam gespeichert oder keine.
+func (h *Handlers) ImportSpreadsheetHandler(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSpreadsheetSize+maxFormOverhead)
+	var input io.Reader = c.Request.Body
+	file, err := c.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		respondProblem(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "")
+		return
+	}
+	if err == nil {
+		if file.Size > maxSpreadsheetSize {
+			respondProblem(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "")
+			return
+		}
+		opened, err := file.Open()
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, CodeInvalidSpreadsheet, localize(c, msgUnreadableFile))
+			return
+		}
+		defer opened.Close()
+		input = opened
+	}
+	data, err := io.ReadAll(input)
+	if errors.As(err, &maxBytesErr) || len(data) > maxSpreadsheetSize {
+		respondProblem(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "")
+		return
+	}
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidSpreadsheet, localize(c, msgUnreadableFile))
+		return
+	}
+	entries, err := spreadsheet.Read(data)
+	if err != nil {
+		respondSpreadsheetError(c, err)
+		return
+	}
+
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	dryRun := c.Query("dryRun") == "true"
+	if !dryRun && !requireEditable(c, *project) {
+		return
+	}
+	result, err := spreadsheet.Plan(*project, entries)
+	if err != nil {
+		respondSpreadsheetError(c, err)
+		return
+	}
+	result.DryRun = dryRun
+
+	titles := make(map[string]string, len(project.Criteria))
+	for _, criterion := range h.JsonStore.LocalizeCriteria(project.Criteria, requestLanguage(c)) {
+		titles[criterion.ID] = criterion.Title
+	}
+	for i := range result.Changes {
+		result.Changes[i].Title = titles[result.Changes[i].CriterionID]
+	}
+	if dryRun || len(result.Changes) == 0 {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	user := currentUser(c, *project)
+	now := time.Now().UTC().Truncate(time.Millisecond) // Genauigkeit von MongoDB
+	revisions := make([]models.NoteRevision, 0)
+	for _, change := range result.Changes {
+		if revision, ok := notes.Revision(rand.Text(), *project, change.CriterionID, change.NotesAfter, user, now); ok {
+			revisions = append(revisions, revision)
+		}
+	}
+	if _, err := h.MongoStore.ApplyCriterionChanges(c.Request.Context(), project.ID, result.Changes, revisions, editableStatuses(c, *project)); err != nil {
+		respondStoreError(c, "importing spreadsheet failed", err, CodeProjectNotFound)
+		return
+	}
+	requestLogger(c).Info("spreadsheet imported", "criteria", len(result.Changes))
+	c.JSON(http.StatusOK, result)
+}
+
+// respondSpreadsheetError antwortet auf einen Fehler aus spreadsheet.Read oder spreadsheet.Plan.
+// Die Fehler beschreiben den Inhalt der Tabelle.
+func respondSpreadsheetError(c *gin.Context, err error) {
+	var sheetErr *spreadsheet.SheetError
+	if errors.As(err, &sheetErr) {
+		problem := newProblem(c, http.StatusBadRequest, CodeInvalidSpreadsheet, localize(c, msgInvalidSpreadsheetRows))
+		problem.Rows = localizedRows(c, sheetErr.Rows, spreadsheetMessages)
+		writeProblem(c, problem)
+		return
+	}
+	respondProblem(c, http.StatusBadRequest, CodeInvalidSpreadsheet, contentDetail(c, err, spreadsheetMessages))
+}
+
+// spreadsheetMessages enthält die Meldungen zu den Fehlern aus spreadsheet.Read und
+// spreadsheet.Plan.
+var spreadsheetMessages = map[error]i18n.Text{
+	spreadsheet.ErrEmpty: {
+		i18n.German:  "Die Tabelle ist leer",
+		i18n.French:  "Le tableau est vide",
+		i18n.Italian: "La tabella è vuota",
+	},
+	spreadsheet.ErrNoCriteria: {
+		i18n.German:  "Die Tabelle enthält keine Kriterien",
+		i18n.French:  "Le tableau ne contient aucun critère",
+		i18n.Italian: "La tabella non contiene criteri",
+	},
+	spreadsheet.ErrInvalidCSV: {
+		i18n.German:  "Die Datei ist keine gültige CSV-Datei",
+		i18n.French:  "Le fichier n'est pas un fichier CSV valide",
+		i18n.Italian: "Il file non è un file CSV valido",
+	},
+	spreadsheet.ErrInvalidExcel: {
+		i18n.German:  "Die Datei ist keine gültige Excel-Datei",
+		i18n.French:  "Le fichier n'est pas un fichier Excel valide",
+		i18n.Italian: "Il file non è un file Excel valido",
+	},
+	spreadsheet.ErrMissingColumn: {
+		i18n.German:  "Die Spalte %q fehlt",
+		i18n.French:  "La colonne %q est manquante",
+		i18n.Italian: "Manca la colonna %q",
+	},
+	spreadsheet.ErrIDMissing: {
+		i18n.German:  "Die ID fehlt",
+		i18n.French:  "L'ID est manquant",
+		i18n.Italian: "Manca l'ID",
+	},
+	spreadsheet.ErrDuplicateRow: {
+		i18n.German:  "Doppelter Eintrag, siehe Zeile %d",
+		i18n.French:  "Entrée en double, voir ligne %d",
+		i18n.Italian: "Voce duplicata, vedi riga %d",
+	},
+	spreadsheet.ErrInvalidIndex: {
+		i18n.German:  "Ungültiger Index einer Anforderung %q",
+		i18n.French:  "Index d'exigence invalide %q",
+		i18n.Italian: "Indice di requisito non valido %q",
+	},
+	spreadsheet.ErrCriterionUnknown: {
+		i18n.German:  "Das Kriterium %s gehört nicht zum Projekt",
+		i18n.French:  "Le critère %s n'appartient pas au projet",
+		i18n.Italian: "Il criterio %s non appartiene al progetto",
+	},
+	spreadsheet.ErrRequirementUnknown: {
+		i18n.German:  "Die Anforderung %d gibt es nicht, das Kriterium hat %d Anforderungen",
+		i18n.French:  "L'exigence %d n'existe pas, le critère a %d exigences",
+		i18n.Italian: "Il requisito %d non esiste, il criterio ha %d requisiti",
+	},
+	notes.ErrTooLong: notesMessages[notes.ErrTooLong],
+}
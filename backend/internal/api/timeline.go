@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/timeline"
+	"github.com/gin-gonic/gin"
+)
+
+// today liefert den heutigen Tag in der konfigurierten Zeitzone.
+func (h *Handlers) today() models.Date {
+	loc := h.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return models.DateOf(time.Now().In(loc))
+}
+
+// GetTimelineHandler liefert den IPA-Zeitraum mit verbleibenden Tagen und dem Stand der Meilensteine.
+func (h *Handlers) GetTimelineHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	c.JSON(http.StatusOK, timeline.Build(*project, h.today()))
+}
+
+// UpdateTimelineHandler ersetzt den IPA-Zeitraum und die Meilensteine des Projekts.
+func (h *Handlers) UpdateTimelineHandler(c *gin.Context) {
+	personId := c.Param("id")
+	var input models.TimelineInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+	if input.Milestones == nil {
+		input.Milestones = make([]models.Milestone, 0)
+	}
+	if err := timeline.Validate(input.StartDate, input.EndDate, input.Milestones); err != nil {
+		respondProblem(c, http.StatusBadRequest, CodeInvalidTimeline, timelineDetail(c, err))
+		return
+	}
+
+	_, err := h.MongoStore.UpdateTimeline(c.Request.Context(), personId, input.StartDate, input.EndDate, input.Milestones)
+	if err != nil {
+		respondStoreError(c, "updating timeline failed", err, CodeProjectNotFound)
+		return
+	}
+	project := models.MongoIpaProject{StartDate: input.StartDate, EndDate: input.EndDate, Milestones: input.Milestones}
+	c.JSON(http.StatusOK, timeline.Build(project, h.today()))
+}
+
+// timelineDetail beschreibt einen Fehler aus timeline.Validate in der Sprache der Anfrage.
+func timelineDetail(c *gin.Context, err error) string {
+	var detail string
+	for cause, msg := range timelineMessages {
+		if errors.Is(err, cause) {
+			detail = localize(c, msg)
+			break
+		}
+	}
+	var milestoneErr *timeline.MilestoneError
+	if errors.As(err, &milestoneErr) {
+		return localize(c, msgMilestone, milestoneErr.Index+1) + detail
+	}
+	return detail
+}
+
+var msgMilestone = i18n.Text{
+	i18n.German:  "Meilenstein %d: ",
+	i18n.French:  "Jalon %d : ",
+	i18n.Italian: "Traguardo %d: ",
+}
+
+// timelineMessages enthält die Meldungen zu den Fehlern aus timeline.Validate.
+var timelineMessages = map[error]i18n.Text{
+	timeline.ErrIncompletePeriod: {
+		i18n.German:  "Start- und Enddatum müssen beide angegeben werden",
+		i18n.French:  "Les dates de début et de fin doivent être indiquées toutes les deux",
+		i18n.Italian: "Le date di inizio e di fine devono essere indicate entrambe",
+	},
+	timeline.ErrEndBeforeStart: {
+		i18n.German:  "Das Enddatum liegt vor dem Startdatum",
+		i18n.French:  "La date de fin précède la date de début",
+		i18n.Italian: "La data di fine precede la data di inizio",
+	},
+	timeline.ErrPeriodTooLong: {
+		i18n.German:  "Der IPA-Zeitraum ist zu lang",
+		i18n.French:  "La période TPI est trop longue",
+		i18n.Italian: "Il periodo LPI è troppo lungo",
+	},
+	timeline.ErrMilestonesWithoutPeriod: {
+		i18n.German:  "Meilensteine erfordern ein Start- und Enddatum",
+		i18n.French:  "Les jalons nécessitent une date de début et de fin",
+		i18n.Italian: "I traguardi richiedono una data di inizio e di fine",
+	},
+	timeline.ErrMilestoneKind: {
+		i18n.German:  "Unbekannte Art",
+		i18n.French:  "Type inconnu",
+		i18n.Italian: "Tipo sconosciuto",
+	},
+	timeline.ErrMilestoneTitle: {
+		i18n.German:  "Eigene Meilensteine benötigen einen Titel",
+		i18n.French:  "Les jalons personnalisés nécessitent un titre",
+		i18n.Italian: "I traguardi personalizzati richiedono un titolo",
+	},
+	timeline.ErrMilestoneDate: {
+		i18n.German:  "Das Datum fehlt oder liegt ausserhalb des IPA-Zeitraums",
+		i18n.French:  "La date est manquante ou en dehors de la période TPI",
+		i18n.Italian: "La data manca o è al di fuori del periodo LPI",
+	},
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/selection"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/workflow"
+	"github.com/gin-gonic/gin"
+)
+
+// GetWorkflowHandler liefert den Status des Projekts, die möglichen Übergänge und die
+// signierten Bewertungen.
+func (h *Handlers) GetWorkflowHandler(c *gin.Context) {
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+	c.JSON(http.StatusOK, workflow.Build(*project, currentUser(c, *project)))
+}
+
+// ChangeStatusHandler versetzt das Projekt in einen neuen Status. Beim Übergang zu "graded"
+// wird die Bewertung signiert festgehalten.
+func (h *Handlers) ChangeStatusHandler(c *gin.Context) {
+	var input models.StatusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+	project, err := h.getIpaProjectFromRequest(c)
+	if err != nil {
+		return // Error is already handled by helper
+	}
+
+	user := currentUser(c, *project)
+	if err := workflow.Check(*project, input.Status, user); errors.Is(err, workflow.ErrRole) {
+		respondProblem(c, http.StatusForbidden, CodeTransitionForbidden, workflowDetail(c, err))
+		return
+	} else if err != nil {
+		respondProblem(c, http.StatusConflict, CodeInvalidTransition, workflowDetail(c, err))
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond) // Genauigkeit von MongoDB
+	change := models.StatusChange{From: project.CurrentStatus(), To: input.Status, By: user.Name, Role: user.Role, At: now}
+	var snapshot *models.GradeSnapshot
+	if input.Status == models.StatusGraded {
+		provisional := !selection.Validate(h.JsonStore.GetSelectionRules(i18n.Default), project.Criteria).Valid
+		graded := h.Snapshots.New(rand.Text(), *project, provisional, user, now)
+		snapshot = &graded
+	}
+	if _, err := h.MongoStore.ChangeStatus(c.Request.Context(), project.ID, change, snapshot, project.CriteriaRevision); err != nil {
+		respondStoreError(c, "changing status failed", err, CodeProjectNotFound)
+		return
+	}
+
+	project.Status = input.Status
+	project.StatusHistory = append(project.StatusHistory, change)
+	if snapshot != nil {
+		project.GradeSnapshots = append(project.GradeSnapshots, *snapshot)
+	}
+	c.JSON(http.StatusOK, workflow.Build(*project, user))
+}
+
+// requireEditable lehnt die Anfrage ab, wenn der Benutzer die Kriterien des Projekts im aktuellen
+// Status nicht ändern darf.
+func requireEditable(c *gin.Context, project models.MongoIpaProject) bool {
+	if workflow.Editable(project, currentUser(c, project)) {
+		return true
+	}
+	respondLocked(c, project.CurrentStatus())
+	return false
+}
+
+// editableStatuses liefert die Status, in denen der Store die Kriterien für den Benutzer schreibt.
+// Ändert sich der Status nach requireEditable, schlägt das Schreiben mit store.ErrLocked fehl.
+func editableStatuses(c *gin.Context, project models.MongoIpaProject) []models.ProjectStatus {
+	return workflow.EditableStatuses(currentUser(c, project))
+}
+
+func respondLocked(c *gin.Context, status models.ProjectStatus) {
+	respondProblem(c, http.StatusConflict, CodeProjectLocked, localize(c, msgProjectLocked, status))
+}
+
+// workflowDetail beschreibt einen Fehler aus workflow.Check in der Sprache der Anfrage.
+func workflowDetail(c *gin.Context, err error) string {
+	for cause, msg := range workflowMessages {
+		if errors.Is(err, cause) {
+			return localize(c, msg)
+		}
+	}
+	return ""
+}
+
+// workflowMessages enthält die Meldungen zu den Fehlern aus workflow.Check.
+var workflowMessages = map[error]i18n.Text{
+	workflow.ErrTransition: {
+		i18n.German:  "Aus dem aktuellen Status ist dieser Wechsel nicht vorgesehen",
+		i18n.French:  "Ce changement n'est pas prévu depuis le statut actuel",
+		i18n.Italian: "Questo cambio non è previsto dallo stato attuale",
+	},
+	workflow.ErrRole: {
+		i18n.German:  "Diesen Wechsel darf nur die andere Rolle vornehmen",
+		i18n.French:  "Seul l'autre rôle peut effectuer ce changement",
+		i18n.Italian: "Solo l'altro ruolo può effettuare questo cambio",
+	},
+}
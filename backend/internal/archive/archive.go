@@ -0,0 +1,198 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+// FormatVersion ist die Version des Archivformats. Sie wird erhöht, wenn sich
+// das Format inkompatibel ändert.
+const FormatVersion = 1
+
+// maxArchiveEntrySize begrenzt die Grösse einer einzelnen Datei in einem ZIP-Archiv.
+const maxArchiveEntrySize = 32 << 20
+
+var (
+	ErrInvalid            = errors.New("invalid archive")
+	ErrUnsupportedVersion = errors.New("unsupported archive format version")
+	ErrManifestMissing    = errors.New("manifest.json is missing")
+	ErrManifestMismatch   = errors.New("manifest does not match the archive")
+	ErrEntryTooLarge      = errors.New("archive entry is too large")
+)
+
+// Archive ist ein portables Abbild einer Menge von IPA-Projekten.
+type Archive struct {
+	FormatVersion     int       `json:"formatVersion"`
+	ExportedAt        time.Time `json:"exportedAt"`
+	CatalogueVersion  string    `json:"catalogueVersion"`
+	IncludesPasswords bool      `json:"includesPasswords"`
+	Projects          []Project `json:"projects"`
+}
+
+// Project ist ein exportiertes IPA-Projekt. Der Passwort-Hash wird nur auf
+// ausdrücklichen Wunsch exportiert.
+type Project struct {
+	PasswordHash string    `json:"passwordHash,omitempty"`
+	Comments     []Comment `json:"comments"`
+	models.MongoIpaProject
+}
+
+// Comment ist ein exportierter Kommentar. Anders als in der API bleiben Autor und
+// Lesestatus erhalten, damit die Diskussionen nach dem Import unverändert sind.
+type Comment struct {
+	AuthorKey string   `json:"authorKey"`
+	ReadBy    []string `json:"readBy"`
+	models.Comment
+}
+
+// manifest ist der Inhalt von manifest.json in einem ZIP-Archiv.
+type manifest struct {
+	FormatVersion     int       `json:"formatVersion"`
+	ExportedAt        time.Time `json:"exportedAt"`
+	CatalogueVersion  string    `json:"catalogueVersion"`
+	IncludesPasswords bool      `json:"includesPasswords"`
+	ProjectCount      int       `json:"projectCount"`
+}
+
+// New erstellt ein Archiv aus den angegebenen Projekten.
+func New(projects []models.MongoIpaProject, catalogueVersion string, includePasswords bool) *Archive {
+	a := &Archive{
+		FormatVersion:     FormatVersion,
+		ExportedAt:        time.Now().UTC(),
+		CatalogueVersion:  catalogueVersion,
+		IncludesPasswords: includePasswords,
+		Projects:          make([]Project, len(projects)),
+	}
+	for i, project := range projects {
+		exported := Project{MongoIpaProject: project}
+		for _, comment := range project.Comments {
+			exported.Comments = append(exported.Comments, Comment{AuthorKey: comment.AuthorKey, ReadBy: comment.ReadBy, Comment: comment})
+		}
+		if includePasswords {
+			exported.PasswordHash = project.PasswordHash
+		}
+		a.Projects[i] = exported
+	}
+	return a
+}
+
+// WriteJSON schreibt das Archiv als einzelne JSON-Datei.
+func (a *Archive) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(a)
+}
+
+// WriteZIP schreibt das Archiv als ZIP-Datei mit einer manifest.json und einer
+// Datei pro Projekt unter projects/.
+func (a *Archive) WriteZIP(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	writeEntry := func(name string, value any) error {
+		entry, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: a.ExportedAt})
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	err := writeEntry("manifest.json", manifest{
+		FormatVersion:     a.FormatVersion,
+		ExportedAt:        a.ExportedAt,
+		CatalogueVersion:  a.CatalogueVersion,
+		IncludesPasswords: a.IncludesPasswords,
+		ProjectCount:      len(a.Projects),
+	})
+	if err != nil {
+		return err
+	}
+	for _, project := range a.Projects {
+		if err := writeEntry(path.Join("projects", project.ID+".json"), project); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// Read liest ein Archiv im JSON- oder ZIP-Format. Jeder Fehler enthält genau einen der
+// Fehler ErrInvalid, ErrUnsupportedVersion, ErrManifestMissing, ErrManifestMismatch oder
+// ErrEntryTooLarge.
+func Read(data []byte) (*Archive, error) {
+	var a *Archive
+	if bytes.HasPrefix(data, []byte("PK")) {
+		var err error
+		if a, err = readZIP(data); err != nil {
+			return nil, err
+		}
+	} else {
+		a = &Archive{}
+		if err := json.Unmarshal(data, a); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+		}
+	}
+	if a.FormatVersion < 1 || a.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("%w %d", ErrUnsupportedVersion, a.FormatVersion)
+	}
+	return a, nil
+}
+
+func readZIP(data []byte) (*Archive, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	a := &Archive{Projects: make([]Project, 0)}
+	var m *manifest
+	for _, file := range zr.File {
+		switch {
+		case file.Name == "manifest.json":
+			m = &manifest{}
+			err = readZIPEntry(file, m)
+		case strings.HasPrefix(file.Name, "projects/") && strings.HasSuffix(file.Name, ".json"):
+			var project Project
+			err = readZIPEntry(file, &project)
+			a.Projects = append(a.Projects, project)
+		}
+		if errors.Is(err, ErrEntryTooLarge) {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalid, file.Name, err)
+		}
+	}
+	if m == nil {
+		return nil, ErrManifestMissing
+	}
+	if m.ProjectCount != len(a.Projects) {
+		return nil, fmt.Errorf("%w: manifest lists %d projects, archive contains %d", ErrManifestMismatch, m.ProjectCount, len(a.Projects))
+	}
+
+	a.FormatVersion = m.FormatVersion
+	a.ExportedAt = m.ExportedAt
+	a.CatalogueVersion = m.CatalogueVersion
+	a.IncludesPasswords = m.IncludesPasswords
+	return a, nil
+}
+
+func readZIPEntry(file *zip.File, value any) error {
+	if file.UncompressedSize64 > maxArchiveEntrySize {
+		return ErrEntryTooLarge
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return json.NewDecoder(io.LimitReader(rc, maxArchiveEntrySize)).Decode(value)
+}
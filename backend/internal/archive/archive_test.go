@@ -0,0 +1,189 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+func testProjects() []models.MongoIpaProject {
+	return []models.MongoIpaProject{
+		{
+			ID:               "AA01",
+			Firstname:        "Anna",
+			Lastname:         "Muster",
+			PasswordHash:     "hash-1",
+			CatalogueVersion: "2025.1",
+			Criteria:         []models.Criterion{{ID: "A01", Checked: []int{1, 2}, Notes: "Notiz"}},
+			Comments: []models.Comment{{
+				ID: "c1", CriterionID: "A01", ThreadID: "c1", Author: "Eva Experte", AuthorKey: "expert:Eva Experte",
+				Role: models.RoleExpert, Text: "Bitte ergänzen", ReadBy: []string{"expert:Eva Experte", "candidate"},
+			}},
+		},
+		{ID: "AB02", Firstname: "Beat", Lastname: "Meier", PasswordHash: "hash-2", CatalogueVersion: "2025.1"},
+	}
+}
+
+func TestNewExcludesPasswordsByDefault(t *testing.T) {
+	a := New(testProjects(), "2025.1", false)
+	for _, project := range a.Projects {
+		if project.PasswordHash != "" {
+			t.Errorf("project %s contains password hash", project.ID)
+		}
+	}
+	if a.Projects[0].ID != "AA01" || a.Projects[1].ID != "AB02" {
+		t.Errorf("New() ids = %s, %s", a.Projects[0].ID, a.Projects[1].ID)
+	}
+}
+
+func TestRoundtrip(t *testing.T) {
+	writers := map[string]func(*Archive, *bytes.Buffer) error{
+		"json": func(a *Archive, b *bytes.Buffer) error { return a.WriteJSON(b) },
+		"zip":  func(a *Archive, b *bytes.Buffer) error { return a.WriteZIP(b) },
+	}
+	for name, write := range writers {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := write(New(testProjects(), "2025.1", true), &buf); err != nil {
+				t.Fatalf("write error = %v", err)
+			}
+			got, err := Read(buf.Bytes())
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if got.FormatVersion != FormatVersion || got.CatalogueVersion != "2025.1" || !got.IncludesPasswords {
+				t.Errorf("Read() header = %+v", got)
+			}
+			if len(got.Projects) != 2 {
+				t.Fatalf("Read() returned %d projects, want 2", len(got.Projects))
+			}
+			first := got.Projects[0]
+			if first.ID != "AA01" || first.PasswordHash != "hash-1" || first.Firstname != "Anna" ||
+				len(first.Criteria) != 1 || first.Criteria[0].Notes != "Notiz" || len(first.Criteria[0].Checked) != 2 {
+				t.Errorf("Read() first project = %+v", first)
+			}
+			if len(first.Comments) != 1 || first.Comments[0].AuthorKey != "expert:Eva Experte" || len(first.Comments[0].ReadBy) != 2 {
+				t.Errorf("Read() comments = %+v", first.Comments)
+			}
+		})
+	}
+}
+
+func TestReadRejectsUnknownFormatVersion(t *testing.T) {
+	if _, err := Read([]byte(`{"formatVersion": 99, "projects": []}`)); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Read() with unknown format version = %v, want ErrUnsupportedVersion", err)
+	}
+	if _, err := Read([]byte(`{"projects": []}`)); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Read() with missing format version = %v, want ErrUnsupportedVersion", err)
+	}
+}
+
+func TestReadRejectsMalformedArchives(t *testing.T) {
+	if _, err := Read([]byte(`{"formatVersion": 1, "projects": [`)); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Read() with truncated JSON = %v, want ErrInvalid", err)
+	}
+	if _, err := Read([]byte("PK not a zip")); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Read() with broken ZIP = %v, want ErrInvalid", err)
+	}
+}
+
+type fakeStore struct {
+	existing map[string]bool
+	newIDs   []string
+	saved    []models.MongoIpaProject
+}
+
+func (f *fakeStore) ExistingProjectIDs(_ context.Context, ids []string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	for _, id := range ids {
+		if f.existing[id] {
+			result[id] = true
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeStore) GetNewID(context.Context) (string, error) {
+	id := f.newIDs[0]
+	f.newIDs = f.newIDs[1:]
+	return id, nil
+}
+
+func (f *fakeStore) SaveIpaProjects(_ context.Context, projects []models.MongoIpaProject) error {
+	f.saved = projects
+	return nil
+}
+
+func exportedProject(id string) Project {
+	return Project{MongoIpaProject: models.MongoIpaProject{ID: id}}
+}
+
+func TestImport(t *testing.T) {
+	scheme := common.ProjectIDScheme{Alphabet: "ABCDEFGHJKLMNPQRSTUVWXYZ23456789", Length: 6}
+	valid := make([]string, 6)
+	for i := range valid {
+		id, err := scheme.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		valid[i] = id
+	}
+
+	a := New(testProjects(), "2024.1", false)
+	a.Projects[0].ID = valid[0]
+	a.Projects[1].ID = valid[1]
+	a.Projects[1].PasswordHash = "hash-2"
+	a.Projects[1].GradeSnapshots = []models.GradeSnapshot{{ID: "S1", ProjectID: valid[1], Sequence: 1}}
+	a.Projects = append(a.Projects, exportedProject(valid[0]), exportedProject("invalid"), exportedProject(valid[2]))
+
+	// Die erste neue ID kollidiert mit einer ID aus dem Archiv und wird verworfen
+	store := &fakeStore{existing: map[string]bool{valid[1]: true}, newIDs: []string{valid[2], valid[3], valid[4], valid[5]}}
+	importer := Importer{
+		Store:            store,
+		IDScheme:         scheme,
+		CatalogueVersion: "2025.1",
+		HashPassword:     func(password string) (string, error) { return "hashed:" + password, nil },
+	}
+	report, err := importer.Import(context.Background(), a)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	wantIDs := []string{valid[0], valid[3], valid[4], valid[5], valid[2]}
+	wantConflict := []bool{false, true, true, true, false}
+	for i, project := range report.Projects {
+		if project.ID != wantIDs[i] || (project.Conflict != "") != wantConflict[i] {
+			t.Errorf("Import() project %d = %+v, want id %s conflict %v", i, project, wantIDs[i], wantConflict[i])
+		}
+	}
+	if report.Conflicts != 3 {
+		t.Errorf("Import() conflicts = %d, want 3", report.Conflicts)
+	}
+	if len(report.Warnings) != 4 || !strings.Contains(report.Warnings[0], "project_mismatch") {
+		t.Errorf("Import() warnings = %v, want one per project without catalogue version and one for the reassigned snapshots", report.Warnings)
+	}
+
+	if len(store.saved) != 5 {
+		t.Fatalf("Import() saved %d projects, want 5", len(store.saved))
+	}
+	for i, project := range store.saved {
+		if project.ID != wantIDs[i] {
+			t.Errorf("Import() saved project %d with id %s, want %s", i, project.ID, wantIDs[i])
+		}
+	}
+	if store.saved[1].PasswordHash != "hash-2" || store.saved[1].PasswordChangeRequired || report.Projects[1].Password != "" {
+		t.Errorf("Import() did not keep exported password hash: %+v", store.saved[1])
+	}
+	if report.Projects[0].Password == "" || store.saved[0].PasswordHash != "hashed:"+report.Projects[0].Password ||
+		!store.saved[0].PasswordChangeRequired {
+		t.Errorf("Import() did not generate a one-time password: %+v", store.saved[0])
+	}
+	if comments := store.saved[0].Comments; len(comments) != 1 || comments[0].AuthorKey != "expert:Eva Experte" || len(comments[0].ReadBy) != 2 {
+		t.Errorf("Import() comments = %+v", comments)
+	}
+}
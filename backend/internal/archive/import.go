@@ -0,0 +1,141 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/admin"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+// ProjectStore ist der Teil des MongoStores, den der Import benötigt.
+type ProjectStore interface {
+	ExistingProjectIDs(ctx context.Context, ids []string) (map[string]bool, error)
+	GetNewID(ctx context.Context) (string, error)
+	SaveIpaProjects(ctx context.Context, projects []models.MongoIpaProject) error
+}
+
+// ImportedProject beschreibt das Ergebnis des Imports eines Projekts.
+type ImportedProject struct {
+	OriginalID string `json:"originalId"`
+	ID         string `json:"id"`
+	Conflict   string `json:"conflict,omitempty"` // Grund für die Neuvergabe der ID
+	Password   string `json:"password,omitempty"` // Einmal-Passwort, falls das Archiv keinen Passwort-Hash enthielt
+}
+
+// ImportReport fasst einen Import zusammen.
+type ImportReport struct {
+	Projects  []ImportedProject `json:"projects"`
+	Conflicts int               `json:"conflicts"`
+	Warnings  []string          `json:"warnings"`
+}
+
+// Importer importiert Archive in den Store.
+type Importer struct {
+	Store            ProjectStore
+	IDScheme         common.ProjectIDScheme
+	CatalogueVersion string
+	// HashPassword erzeugt den Hash für generierte Einmal-Passwörter.
+	HashPassword func(password string) (string, error)
+}
+
+// Import speichert alle Projekte des Archivs. Kollidiert eine ID mit einem
+// bestehenden Projekt oder einem anderen Projekt im Archiv oder ist sie ungültig,
+// erhält das Projekt eine neue zufällige ID. Projekte ohne Passwort-Hash erhalten ein
+// Einmal-Passwort. Es werden entweder alle oder keine Projekte gespeichert. Die Momentaufnahmen
+// der Bewertung sind an die ID gebunden: Nach einer Neuvergabe meldet die Prüfung sie als
+// project_mismatch, der Bericht weist mit einer Warnung darauf hin.
+func (im Importer) Import(ctx context.Context, a *Archive) (ImportReport, error) {
+	report := ImportReport{
+		Projects: make([]ImportedProject, len(a.Projects)),
+		Warnings: make([]string, 0),
+	}
+
+	ids := make([]string, len(a.Projects))
+	for i, project := range a.Projects {
+		ids[i] = common.NormalizeProjectID(project.ID)
+	}
+	existing, err := im.Store.ExistingProjectIDs(ctx, ids)
+	if err != nil {
+		return report, err
+	}
+
+	projects := make([]models.MongoIpaProject, len(a.Projects))
+	taken := make(map[string]bool)
+	for i, exported := range a.Projects {
+		project := exported.MongoIpaProject
+		project.Comments = nil
+		for _, comment := range exported.Comments {
+			comment.Comment.AuthorKey, comment.Comment.ReadBy = comment.AuthorKey, comment.ReadBy
+			project.Comments = append(project.Comments, comment.Comment)
+		}
+		imported := &report.Projects[i]
+		imported.OriginalID = exported.ID
+
+		id := ids[i]
+		switch {
+		case im.IDScheme.Validate(id) != nil:
+			imported.Conflict = "invalid id"
+		case existing[id]:
+			imported.Conflict = "id already exists"
+		case taken[id]:
+			imported.Conflict = "duplicate id in archive"
+		}
+		if imported.Conflict != "" {
+			// Auch neue IDs dürfen nicht mit IDs aus dem Archiv kollidieren
+			for existing[id] || taken[id] || slices.Contains(ids, id) {
+				if id, err = im.Store.GetNewID(ctx); err != nil {
+					return report, err
+				}
+			}
+			report.Conflicts++
+		}
+		taken[id] = true
+		if bound := boundSnapshots(project.GradeSnapshots); imported.Conflict != "" && bound > 0 {
+			// Die Momentaufnahmen bleiben unverändert, eine neue Signatur würde Änderungen im Archiv verdecken
+			report.Warnings = append(report.Warnings, fmt.Sprintf(
+				"project %s was imported as %s, its %d grade snapshots name the original id and fail verification with project_mismatch",
+				exported.ID, id, bound))
+		}
+		project.ID = id
+		imported.ID = id
+
+		project.PasswordHash = exported.PasswordHash
+		if project.PasswordHash == "" {
+			password, err := admin.GeneratePassword()
+			if err != nil {
+				return report, err
+			}
+			if project.PasswordHash, err = im.HashPassword(password); err != nil {
+				return report, err
+			}
+			project.PasswordChangeRequired = true
+			imported.Password = password
+		}
+
+		if project.CatalogueVersion != im.CatalogueVersion {
+			report.Warnings = append(report.Warnings, fmt.Sprintf(
+				"project %s uses catalogue version %q, current version is %q", exported.ID, project.CatalogueVersion, im.CatalogueVersion))
+		}
+
+		projects[i] = project
+	}
+
+	if err := im.Store.SaveIpaProjects(ctx, projects); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// boundSnapshots zählt die Momentaufnahmen, die eine Projekt-ID enthalten.
+func boundSnapshots(snapshots []models.GradeSnapshot) int {
+	count := 0
+	for _, snapshot := range snapshots {
+		if snapshot.ProjectID != "" {
+			count++
+		}
+	}
+	return count
+}
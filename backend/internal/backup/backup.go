@@ -0,0 +1,228 @@
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// FormatVersion ist die Version des Snapshot-Formats.
+const FormatVersion = 1
+
+const (
+	filePrefix    = "snapshot-"
+	fileSuffix    = ".json.gz"
+	fileTimestamp = "20060102T150405Z"
+)
+
+// Collections sind die Collections, die gesichert werden.
+var Collections = []string{store.ProjectsCollection}
+
+// Source liefert die Dokumente der zu sichernden Collections.
+type Source interface {
+	ExportCollection(ctx context.Context, name string) ([]bson.Raw, error)
+}
+
+// Snapshot ist der Inhalt einer Sicherung. Die Dokumente werden als kanonisches
+// Extended JSON gespeichert, damit beim Wiederherstellen alle BSON-Typen erhalten bleiben.
+type Snapshot struct {
+	FormatVersion int                          `json:"formatVersion"`
+	CreatedAt     time.Time                    `json:"createdAt"`
+	Collections   map[string][]json.RawMessage `json:"collections"`
+}
+
+// Create sichert die Collections als komprimierten Snapshot im Verzeichnis dir
+// und gibt den Pfad der erstellten Datei zurück.
+func Create(ctx context.Context, source Source, dir string, now time.Time) (string, error) {
+	snapshot := Snapshot{
+		FormatVersion: FormatVersion,
+		CreatedAt:     now.UTC(),
+		Collections:   make(map[string][]json.RawMessage, len(Collections)),
+	}
+	for _, name := range Collections {
+		documents, err := source.ExportCollection(ctx, name)
+		if err != nil {
+			return "", fmt.Errorf("exporting %s: %w", name, err)
+		}
+		encoded := make([]json.RawMessage, len(documents))
+		for i, document := range documents {
+			if encoded[i], err = bson.MarshalExtJSON(document, true, false); err != nil {
+				return "", fmt.Errorf("encoding %s: %w", name, err)
+			}
+		}
+		snapshot.Collections[name] = encoded
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, filePrefix+snapshot.CreatedAt.Format(fileTimestamp)+fileSuffix)
+
+	// Erst in eine temporäre Datei schreiben, damit nie ein halber Snapshot liegen bleibt
+	tmp, err := os.CreateTemp(dir, ".snapshot-*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := gzip.NewWriter(tmp)
+	if err := json.NewEncoder(zw).Encode(snapshot); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Load liest einen Snapshot.
+func Load(path string) (*Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+	defer zr.Close()
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(zr).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+	if snapshot.FormatVersion < 1 || snapshot.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("unsupported snapshot format version %d", snapshot.FormatVersion)
+	}
+	return &snapshot, nil
+}
+
+// Documents liefert die Dokumente einer Collection aus dem Snapshot.
+func (s *Snapshot) Documents(collection string) ([]bson.Raw, error) {
+	encoded, ok := s.Collections[collection]
+	if !ok {
+		return nil, fmt.Errorf("snapshot does not contain collection %s", collection)
+	}
+	documents := make([]bson.Raw, len(encoded))
+	for i, document := range encoded {
+		if err := bson.UnmarshalExtJSON(document, true, &documents[i]); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", collection, err)
+		}
+	}
+	return documents, nil
+}
+
+// FindProject liefert das Dokument des Projekts mit der angegebenen ID. Projekte aus
+// Snapshots vor der Einführung der zufälligen IDs werden über ihre fortlaufende Nummer gefunden.
+func (s *Snapshot) FindProject(id string) (bson.Raw, error) {
+	documents, err := s.Documents(store.ProjectsCollection)
+	if err != nil {
+		return nil, err
+	}
+	legacyID, legacyErr := common.ParseProjectID(id)
+	for _, document := range documents {
+		if publicID, ok := document.Lookup("publicId").StringValueOK(); ok {
+			if publicID == id {
+				return document, nil
+			}
+			continue
+		}
+		if n, ok := document.Lookup("id").AsInt64OK(); ok && legacyErr == nil && n == int64(legacyID) {
+			return document, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// ErrNotFound wird zurückgegeben, wenn ein Dokument oder Snapshot nicht gefunden wurde.
+var ErrNotFound = errors.New("not found")
+
+// Entry ist ein Snapshot im Sicherungsverzeichnis.
+type Entry struct {
+	Path      string
+	CreatedAt time.Time
+}
+
+// List liefert alle Snapshots im Verzeichnis, die ältesten zuerst.
+func List(dir string) ([]Entry, error) {
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		timestamp := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix)
+		createdAt, err := time.Parse(fileTimestamp, timestamp)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, Entry{Path: filepath.Join(dir, name), CreatedAt: createdAt})
+	}
+	slices.SortFunc(entries, func(a, b Entry) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return entries, nil
+}
+
+// FindAt liefert den neuesten Snapshot, der zum Zeitpunkt at bereits existierte.
+func FindAt(dir string, at time.Time) (Entry, error) {
+	entries, err := List(dir)
+	if err != nil {
+		return Entry{}, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !entries[i].CreatedAt.After(at) {
+			return entries[i], nil
+		}
+	}
+	return Entry{}, ErrNotFound
+}
+
+// Prune löscht die ältesten Snapshots, sodass höchstens retention Snapshots übrig bleiben.
+// Eine Retention von 0 oder weniger behält alle Snapshots.
+func Prune(dir string, retention int) ([]string, error) {
+	if retention <= 0 {
+		return nil, nil
+	}
+	entries, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for len(entries) > retention {
+		if err := os.Remove(entries[0].Path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, entries[0].Path)
+		entries = entries[1:]
+	}
+	return removed, nil
+}
@@ -0,0 +1,157 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/store"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type fakeDatabase struct {
+	collections map[string][]bson.Raw
+	replaced    []bson.Raw
+	migrated    bool
+}
+
+func mustMarshal(t *testing.T, value any) bson.Raw {
+	t.Helper()
+	data, err := bson.Marshal(value)
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+	return data
+}
+
+func newFakeDatabase(t *testing.T) *fakeDatabase {
+	return &fakeDatabase{collections: map[string][]bson.Raw{
+		store.ProjectsCollection: {
+			mustMarshal(t, bson.D{{Key: "_id", Value: bson.NewObjectID()}, {Key: "publicId", Value: "K7QX2M"}, {Key: "firstname", Value: "Anna"}}),
+			mustMarshal(t, bson.D{{Key: "id", Value: int64(7)}, {Key: "firstname", Value: "Beat"}, {Key: "date", Value: time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC)}}),
+		},
+	}}
+}
+
+func (f *fakeDatabase) ExportCollection(_ context.Context, name string) ([]bson.Raw, error) {
+	return f.collections[name], nil
+}
+
+func (f *fakeDatabase) ReplaceCollection(_ context.Context, name string, documents []bson.Raw) error {
+	f.collections[name] = documents
+	return nil
+}
+
+func (f *fakeDatabase) ReplaceIpaProjectDocument(_ context.Context, document bson.Raw) error {
+	f.replaced = append(f.replaced, document)
+	return nil
+}
+
+func (f *fakeDatabase) MigrateLegacyProjectIDs(context.Context) error {
+	f.migrated = true
+	return nil
+}
+
+func TestCreateAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	db := newFakeDatabase(t)
+	now := time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC)
+
+	path, err := Create(context.Background(), db, dir, now)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	snapshot, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !snapshot.CreatedAt.Equal(now) {
+		t.Errorf("CreatedAt = %v, want %v", snapshot.CreatedAt, now)
+	}
+
+	for _, name := range Collections {
+		documents, err := snapshot.Documents(name)
+		if err != nil {
+			t.Fatalf("Documents(%s) error = %v", name, err)
+		}
+		original := db.collections[name]
+		if len(documents) != len(original) {
+			t.Fatalf("Documents(%s) returned %d documents, want %d", name, len(documents), len(original))
+		}
+		for i := range documents {
+			if !bytes.Equal(documents[i], original[i]) {
+				t.Errorf("Documents(%s)[%d] = %s, want %s", name, i, documents[i], original[i])
+			}
+		}
+	}
+}
+
+func TestListPruneAndFindAt(t *testing.T) {
+	dir := t.TempDir()
+	db := newFakeDatabase(t)
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for day := range 5 {
+		if _, err := Create(context.Background(), db, dir, start.AddDate(0, 0, day)); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	entry, err := FindAt(dir, start.AddDate(0, 0, 2).Add(time.Hour))
+	if err != nil || !entry.CreatedAt.Equal(start.AddDate(0, 0, 2)) {
+		t.Errorf("FindAt() = %v, %v, want snapshot of day 2", entry, err)
+	}
+	if _, err := FindAt(dir, start.Add(-time.Hour)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindAt() before first snapshot error = %v, want ErrNotFound", err)
+	}
+
+	removed, err := Prune(dir, 3)
+	if err != nil || len(removed) != 2 {
+		t.Fatalf("Prune() = %v, %v, want two removed snapshots", removed, err)
+	}
+	entries, err := List(dir)
+	if err != nil || len(entries) != 3 || !entries[0].CreatedAt.Equal(start.AddDate(0, 0, 2)) {
+		t.Errorf("List() after Prune() = %v, %v", entries, err)
+	}
+
+	if removed, _ := Prune(dir, 0); len(removed) != 0 {
+		t.Errorf("Prune() with retention 0 removed %v", removed)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	dir := t.TempDir()
+	db := newFakeDatabase(t)
+	path, err := Create(context.Background(), db, dir, time.Now())
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	snapshot, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if err := RestoreProject(context.Background(), db, snapshot, "K7QX2M"); err != nil {
+		t.Fatalf("RestoreProject() error = %v", err)
+	}
+	// Projekte aus älteren Snapshots werden über die abgeleitete Legacy-ID gefunden
+	if err := RestoreProject(context.Background(), db, snapshot, "AA07"); err != nil {
+		t.Fatalf("RestoreProject() for legacy project error = %v", err)
+	}
+	if len(db.replaced) != 2 || db.replaced[0].Lookup("firstname").StringValue() != "Anna" ||
+		db.replaced[1].Lookup("firstname").StringValue() != "Beat" {
+		t.Errorf("RestoreProject() replaced %v", db.replaced)
+	}
+	if err := RestoreProject(context.Background(), db, snapshot, "ZZZZZZ"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RestoreProject() for unknown project error = %v, want ErrNotFound", err)
+	}
+
+	db.collections[store.ProjectsCollection] = nil
+	if err := RestoreAll(context.Background(), db, snapshot); err != nil {
+		t.Fatalf("RestoreAll() error = %v", err)
+	}
+	if len(db.collections[store.ProjectsCollection]) != 2 || !db.migrated {
+		t.Errorf("RestoreAll() restored %d projects, migrated %v", len(db.collections[store.ProjectsCollection]), db.migrated)
+	}
+}
@@ -0,0 +1,45 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Target nimmt die wiederhergestellten Dokumente auf.
+type Target interface {
+	ReplaceCollection(ctx context.Context, name string, documents []bson.Raw) error
+	ReplaceIpaProjectDocument(ctx context.Context, document bson.Raw) error
+	MigrateLegacyProjectIDs(ctx context.Context) error
+}
+
+// RestoreAll ersetzt alle gesicherten Collections durch den Stand des Snapshots.
+func RestoreAll(ctx context.Context, target Target, snapshot *Snapshot) error {
+	// Alle Dokumente zuerst dekodieren, damit ein defekter Snapshot nichts überschreibt
+	documents := make(map[string][]bson.Raw, len(Collections))
+	for _, name := range Collections {
+		decoded, err := snapshot.Documents(name)
+		if err != nil {
+			return err
+		}
+		documents[name] = decoded
+	}
+	for _, name := range Collections {
+		if err := target.ReplaceCollection(ctx, name, documents[name]); err != nil {
+			return err
+		}
+	}
+	// Ältere Snapshots enthalten Projekte ohne lesbare ID
+	return target.MigrateLegacyProjectIDs(ctx)
+}
+
+// RestoreProject stellt ein einzelnes Projekt aus dem Snapshot wieder her. Alle anderen
+// Projekte bleiben unverändert.
+func RestoreProject(ctx context.Context, target Target, snapshot *Snapshot, id string) error {
+	document, err := snapshot.FindProject(id)
+	if err != nil {
+		return fmt.Errorf("project %s: %w", id, err)
+	}
+	return target.ReplaceIpaProjectDocument(ctx, document)
+}
@@ -0,0 +1,50 @@
+package backup
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Scheduler erstellt in regelmässigen Abständen Snapshots und löscht alte Snapshots.
+type Scheduler struct {
+	Source    Source
+	Dir       string
+	Interval  time.Duration
+	Retention int
+}
+
+// Run erstellt Snapshots, bis ctx beendet wird.
+func (s Scheduler) Run(ctx context.Context) {
+	slog.Info("backup scheduler started", "interval", s.Interval, "dir", s.Dir, "retention", s.Retention)
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// Ein begonnener Snapshot wird auch beim Herunterfahren fertig geschrieben
+			s.RunOnce(context.WithoutCancel(ctx), now)
+		}
+	}
+}
+
+// RunOnce erstellt einen Snapshot und wendet die Retention an. Fehler werden protokolliert.
+func (s Scheduler) RunOnce(ctx context.Context, now time.Time) {
+	path, err := Create(ctx, s.Source, s.Dir, now)
+	if err != nil {
+		slog.Error("backup failed", "error", err)
+		return
+	}
+	slog.Info("backup written", "path", path)
+
+	removed, err := Prune(s.Dir, s.Retention)
+	if err != nil {
+		slog.Error("removing old backups failed", "error", err)
+	}
+	for _, path := range removed {
+		slog.Info("old backup removed", "path", path)
+	}
+}
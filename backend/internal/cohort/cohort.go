@@ -0,0 +1,195 @@
+// Package cohort wertet die Bewertung einer Gruppe von Projekten aus, z.B. einer Klasse, und
+// schreibt die Auswertung als CSV für Tabellenkalkulationen.
+package cohort
+
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/grade"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+// DefaultTop ist die Anzahl der am häufigsten nicht erfüllten Anforderungen ohne eigene Angabe.
+const DefaultTop = 20
+
+// Tabellen der CSV-Ausgabe.
+const (
+	TableGrades       = "grades"
+	TableCriteria     = "criteria"
+	TableRequirements = "requirements"
+)
+
+var ErrUnknownTable = errors.New("unknown table")
+
+// criterionTotals sammelt die Werte eines Kriteriums über alle Projekte.
+type criterionTotals struct {
+	stats        models.CriterionStatistics
+	qualityLevel int
+}
+
+// requirementKey identifiziert eine Anforderung über alle Projekte.
+type requirementKey struct {
+	criterionID string
+	index       int
+}
+
+// Build wertet die Projekte aus. Die Texte stammen aus dem ersten Projekt mit dem Kriterium,
+// projects sollten deshalb bereits in die gewünschte Sprache übersetzt sein. top begrenzt die
+// Anzahl der am häufigsten nicht erfüllten Anforderungen.
+func Build(projects []models.MongoIpaProject, top int) models.CohortStatistics {
+	var part1, part2 []float64
+	criteria := make(map[string]*criterionTotals)
+	requirements := make(map[requirementKey]*models.RequirementStatistics)
+
+	for _, project := range projects {
+		result := grade.CalculateGrade(project.Criteria)
+		if len(result.Part1.CriterionGrades) > 0 {
+			part1 = append(part1, result.Part1.Grade)
+		}
+		if len(result.Part2.CriterionGrades) > 0 {
+			part2 = append(part2, result.Part2.Grade)
+		}
+		levels := make(map[string]int)
+		for _, criterionGrade := range slices.Concat(result.Part1.CriterionGrades, result.Part2.CriterionGrades) {
+			levels[criterionGrade.CriterionID] = criterionGrade.QualityLevel
+		}
+
+		for _, criterion := range project.Criteria {
+			totals, ok := criteria[criterion.ID]
+			if !ok {
+				totals = &criterionTotals{stats: models.CriterionStatistics{ID: criterion.ID, Title: criterion.Title, Part: part(criterion.ID)}}
+				criteria[criterion.ID] = totals
+			}
+			level := levels[criterion.ID]
+			totals.stats.Selected++
+			totals.stats.QualityLevels[level]++
+			totals.qualityLevel += level
+
+			for index, text := range criterion.Requirements {
+				if slices.Contains(criterion.Checked, index) {
+					continue
+				}
+				key := requirementKey{criterion.ID, index}
+				requirement, ok := requirements[key]
+				if !ok {
+					requirement = &models.RequirementStatistics{CriterionID: criterion.ID, Index: index, Text: text}
+					requirements[key] = requirement
+				}
+				requirement.Unchecked++
+			}
+		}
+	}
+
+	stats := models.CohortStatistics{
+		Projects:     len(projects),
+		Part1:        distribution(part1),
+		Part2:        distribution(part2),
+		Criteria:     make([]models.CriterionStatistics, 0, len(criteria)),
+		Requirements: make([]models.RequirementStatistics, 0, len(requirements)),
+	}
+	for _, totals := range criteria {
+		totals.stats.SelectionRate = common.Round(float64(totals.stats.Selected) / float64(len(projects)))
+		totals.stats.AverageQualityLevel = common.Round(float64(totals.qualityLevel) / float64(totals.stats.Selected))
+		stats.Criteria = append(stats.Criteria, totals.stats)
+	}
+	slices.SortFunc(stats.Criteria, func(a, b models.CriterionStatistics) int { return cmp.Compare(a.ID, b.ID) })
+
+	for _, requirement := range requirements {
+		requirement.UncheckedRate = common.Round(float64(requirement.Unchecked) / float64(criteria[requirement.CriterionID].stats.Selected))
+		stats.Requirements = append(stats.Requirements, *requirement)
+	}
+	slices.SortFunc(stats.Requirements, func(a, b models.RequirementStatistics) int {
+		return cmp.Or(
+			cmp.Compare(b.Unchecked, a.Unchecked),
+			cmp.Compare(b.UncheckedRate, a.UncheckedRate),
+			cmp.Compare(a.CriterionID, b.CriterionID),
+			cmp.Compare(a.Index, b.Index),
+		)
+	})
+	if top > 0 && len(stats.Requirements) > top {
+		stats.Requirements = stats.Requirements[:top]
+	}
+	return stats
+}
+
+// distribution berechnet Kennzahlen und Häufigkeiten der Noten.
+func distribution(grades []float64) models.GradeDistribution {
+	dist := models.GradeDistribution{Projects: len(grades), Counts: make([]models.GradeCount, 0, 11)}
+	for g := 1.0; g <= 6; g += 0.5 {
+		dist.Counts = append(dist.Counts, models.GradeCount{Grade: g})
+	}
+	if len(grades) == 0 {
+		return dist
+	}
+
+	sorted := slices.Sorted(slices.Values(grades))
+	sum := 0.0
+	for _, g := range sorted {
+		sum += g
+		dist.Counts[int(math.Round(g*2))-2].Count++ // 1.0 ist der erste Eintrag
+	}
+	dist.Average = common.Round(sum / float64(len(sorted)))
+	dist.Min = sorted[0]
+	dist.Max = sorted[len(sorted)-1]
+	if mid := len(sorted) / 2; len(sorted)%2 == 1 {
+		dist.Median = sorted[mid]
+	} else {
+		dist.Median = common.Round((sorted[mid-1] + sorted[mid]) / 2)
+	}
+	return dist
+}
+
+// WriteCSV schreibt eine Tabelle der Auswertung als CSV. Semikolon als Trennzeichen und die
+// Byte Order Mark sorgen dafür, dass Excel die Datei mit Umlauten direkt öffnet.
+func WriteCSV(w io.Writer, stats models.CohortStatistics, table string) error {
+	var records [][]string
+	switch table {
+	case TableGrades:
+		records = append(records, []string{"part", "grade", "count"})
+		for i, dist := range []models.GradeDistribution{stats.Part1, stats.Part2} {
+			for _, count := range dist.Counts {
+				records = append(records, []string{strconv.Itoa(i + 1), formatFloat(count.Grade), strconv.Itoa(count.Count)})
+			}
+		}
+	case TableCriteria:
+		records = append(records, []string{"id", "title", "part", "selected", "selectionRate", "averageQualityLevel",
+			"qualityLevel0", "qualityLevel1", "qualityLevel2", "qualityLevel3"})
+		for _, c := range stats.Criteria {
+			records = append(records, []string{c.ID, c.Title, strconv.Itoa(c.Part), strconv.Itoa(c.Selected),
+				formatFloat(c.SelectionRate), formatFloat(c.AverageQualityLevel),
+				strconv.Itoa(c.QualityLevels[0]), strconv.Itoa(c.QualityLevels[1]), strconv.Itoa(c.QualityLevels[2]), strconv.Itoa(c.QualityLevels[3])})
+		}
+	case TableRequirements:
+		records = append(records, []string{"criterionId", "index", "text", "unchecked", "uncheckedRate"})
+		for _, r := range stats.Requirements {
+			records = append(records, []string{r.CriterionID, strconv.Itoa(r.Index), r.Text, strconv.Itoa(r.Unchecked), formatFloat(r.UncheckedRate)})
+		}
+	default:
+		return ErrUnknownTable
+	}
+
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+	return writer.WriteAll(records)
+}
+
+func part(criterionID string) int {
+	if common.IsCriterionPart1(criterionID) {
+		return 1
+	}
+	return 2
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,85 @@
+package cohort
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+func testProjects() []models.MongoIpaProject {
+	criterion := func(id string, checked ...int) models.Criterion {
+		return models.Criterion{ID: id, Title: "Titel " + id, Requirements: []string{"a", "b", "c"}, Checked: checked,
+			QualityLevels: map[string]models.QualityLevel{"2": {MinRequirements: 2}, "1": {MinRequirements: 1}}}
+	}
+	return []models.MongoIpaProject{
+		{ID: "AA01", Criteria: []models.Criterion{criterion("A01", 0, 1, 2), criterion("Doc01", 0)}},
+		{ID: "AA02", Criteria: []models.Criterion{criterion("A01", 0), criterion("B01")}},
+		{ID: "AA03"},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	stats := Build(testProjects(), DefaultTop)
+
+	if stats.Projects != 3 || stats.Part1.Projects != 2 || stats.Part2.Projects != 1 {
+		t.Fatalf("Build() = %+v", stats)
+	}
+	// AA01: A01 Stufe 3 → 6; AA02: A01 Stufe 1, B01 Stufe 0 → 1.83
+	if stats.Part1.Min != 1.83 || stats.Part1.Max != 6 || stats.Part1.Median != 3.92 || stats.Part1.Average != 3.92 {
+		t.Errorf("Part1 = %+v", stats.Part1)
+	}
+	if len(stats.Part1.Counts) != 11 || stats.Part1.Counts[0].Grade != 1 || stats.Part1.Counts[2].Count != 1 || stats.Part1.Counts[10].Count != 1 {
+		t.Errorf("Part1.Counts = %+v", stats.Part1.Counts)
+	}
+
+	if len(stats.Criteria) != 3 || stats.Criteria[0].ID != "A01" || stats.Criteria[2].ID != "Doc01" {
+		t.Fatalf("Criteria = %+v", stats.Criteria)
+	}
+	a01 := stats.Criteria[0]
+	if a01.Selected != 2 || a01.SelectionRate != 0.67 || a01.AverageQualityLevel != 2 || a01.QualityLevels != [4]int{0, 1, 0, 1} || a01.Part != 1 {
+		t.Errorf("A01 = %+v", a01)
+	}
+	if doc := stats.Criteria[2]; doc.Part != 2 || doc.Title != "Titel Doc01" {
+		t.Errorf("Doc01 = %+v", doc)
+	}
+
+	// Jede offene Anforderung fehlt in einem Projekt, A01 wurde aber in zwei Projekten gewählt
+	first, last := stats.Requirements[0], stats.Requirements[len(stats.Requirements)-1]
+	if len(stats.Requirements) != 7 || first.CriterionID != "B01" || first.Index != 0 || first.Text != "a" || first.UncheckedRate != 1 ||
+		last.CriterionID != "A01" || last.Index != 2 || last.UncheckedRate != 0.5 {
+		t.Errorf("Requirements = %+v", stats.Requirements)
+	}
+	if top := Build(testProjects(), 2); len(top.Requirements) != 2 {
+		t.Errorf("Build(top 2) requirements = %d", len(top.Requirements))
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	stats := Build(nil, DefaultTop)
+	if stats.Projects != 0 || stats.Criteria == nil || stats.Requirements == nil || len(stats.Part1.Counts) != 11 {
+		t.Errorf("Build(nil) = %+v", stats)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	stats := Build(testProjects(), DefaultTop)
+	for table, want := range map[string]int{TableGrades: 23, TableCriteria: 4, TableRequirements: 8} {
+		var buf bytes.Buffer
+		if err := WriteCSV(&buf, stats, table); err != nil {
+			t.Fatalf("WriteCSV(%s) error = %v", table, err)
+		}
+		reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\ufeff")))
+		reader.Comma = ';'
+		records, err := reader.ReadAll()
+		if err != nil || len(records) != want {
+			t.Errorf("WriteCSV(%s) = %d records, error %v, want %d", table, len(records), err, want)
+		}
+	}
+	if err := WriteCSV(&bytes.Buffer{}, stats, "projects"); !errors.Is(err, ErrUnknownTable) {
+		t.Errorf("WriteCSV(projects) error = %v, want %v", err, ErrUnknownTable)
+	}
+}
@@ -0,0 +1,157 @@
+// Package comment prüft die Kommentare in den Diskussionen zu den Kriterien eines Projekts
+// und bereitet sie für den anfragenden Benutzer auf.
+package comment
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+// MaxTextLength begrenzt die Länge eines Kommentars in Zeichen.
+const MaxTextLength = 10000
+
+var (
+	ErrTextMissing      = errors.New("text is missing")
+	ErrTextTooLong      = errors.New("text is too long")
+	ErrCriterionUnknown = errors.New("criterion does not exist in the project")
+	ErrThreadUnknown    = errors.New("thread does not exist for the criterion")
+	ErrNotThread        = errors.New("comment does not start a thread")
+)
+
+// Validate prüft einen neuen Kommentar zum Kriterium criterionID. Eine Antwort muss zu einer
+// Diskussion desselben Kriteriums gehören.
+func Validate(input models.CommentInput, criterionID string, project models.MongoIpaProject) error {
+	if err := ValidateText(input.Text); err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(project.Criteria, func(criterion models.Criterion) bool { return criterion.ID == criterionID }) {
+		return ErrCriterionUnknown
+	}
+	if input.ThreadID == "" {
+		return nil
+	}
+	if first, ok := Find(project, input.ThreadID); !ok || first.ID != first.ThreadID || first.CriterionID != criterionID {
+		return ErrThreadUnknown
+	}
+	return nil
+}
+
+// ValidateText prüft den Text eines neuen oder geänderten Kommentars.
+func ValidateText(text string) error {
+	switch {
+	case strings.TrimSpace(text) == "":
+		return ErrTextMissing
+	case utf8.RuneCountInString(text) > MaxTextLength:
+		return ErrTextTooLong
+	}
+	return nil
+}
+
+// New erstellt einen Kommentar von user. Ohne ThreadID eröffnet er eine neue Diskussion.
+// Der Autor hat den Kommentar gelesen.
+func New(id string, input models.CommentInput, criterionID string, user models.User, now time.Time) models.Comment {
+	threadID := input.ThreadID
+	if threadID == "" {
+		threadID = id
+	}
+	return models.Comment{
+		ID:          id,
+		CriterionID: criterionID,
+		ThreadID:    threadID,
+		Author:      user.Name,
+		AuthorKey:   user.Key(),
+		Role:        user.Role,
+		Text:        input.Text,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		ReadBy:      []string{user.Key()},
+	}
+}
+
+// Find liefert den Kommentar mit der ID id.
+func Find(project models.MongoIpaProject, id string) (models.Comment, bool) {
+	index := slices.IndexFunc(project.Comments, func(comment models.Comment) bool {
+		return comment.ID == id
+	})
+	if index < 0 {
+		return models.Comment{}, false
+	}
+	return project.Comments[index], true
+}
+
+// Threads liefert die Diskussionen zum Kriterium criterionID aus Sicht von user, die älteste zuerst.
+func Threads(project models.MongoIpaProject, criterionID string, user models.User) []models.CommentThread {
+	comments := slices.Clone(project.Comments)
+	slices.SortStableFunc(comments, func(a, b models.Comment) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	threads := make([]models.CommentThread, 0)
+	index := make(map[string]int)
+	for _, comment := range comments {
+		if comment.CriterionID != criterionID {
+			continue
+		}
+		i, ok := index[comment.ThreadID]
+		if !ok {
+			i = len(threads)
+			index[comment.ThreadID] = i
+			threads = append(threads, models.CommentThread{ID: comment.ThreadID, CriterionID: criterionID})
+		}
+		thread := &threads[i]
+		if comment.ID == comment.ThreadID {
+			thread.Resolved = comment.Resolved
+		}
+		view := models.CommentView{
+			Comment: comment,
+			Own:     comment.AuthorKey == user.Key(),
+			Read:    !unread(comment, user),
+		}
+		if !view.Read {
+			thread.Unread++
+		}
+		thread.Comments = append(thread.Comments, view)
+	}
+	return threads
+}
+
+// Summary zählt die Diskussionen und ungelesenen Kommentare pro Kriterium aus Sicht von user,
+// in der Reihenfolge der Kriterien des Projekts.
+func Summary(project models.MongoIpaProject, user models.User) models.CommentSummary {
+	summary := models.CommentSummary{Criteria: make([]models.CriterionComments, 0)}
+	for _, criterion := range project.Criteria {
+		counts := models.CriterionComments{CriterionID: criterion.ID}
+		for _, thread := range Threads(project, criterion.ID, user) {
+			counts.Threads++
+			counts.Unread += thread.Unread
+			if !thread.Resolved {
+				counts.Unresolved++
+			}
+		}
+		if counts.Threads == 0 {
+			continue
+		}
+		summary.Unread += counts.Unread
+		summary.Unresolved += counts.Unresolved
+		summary.Criteria = append(summary.Criteria, counts)
+	}
+	return summary
+}
+
+// HasComments meldet, ob es zum Kriterium criterionID Kommentare gibt.
+func HasComments(project models.MongoIpaProject, criterionID string) bool {
+	return slices.ContainsFunc(project.Comments, func(comment models.Comment) bool {
+		return comment.CriterionID == criterionID
+	})
+}
+
+// unread meldet, ob user einen Kommentar eines anderen Benutzers noch nicht gelesen hat.
+// Gelöschte Kommentare zählen nicht.
+func unread(comment models.Comment, user models.User) bool {
+	return !comment.Deleted && comment.AuthorKey != user.Key() && !slices.Contains(comment.ReadBy, user.Key())
+}
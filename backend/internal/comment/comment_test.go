@@ -0,0 +1,103 @@
+package comment
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+var (
+	candidate = models.User{Role: models.RoleCandidate, Name: "Anna Muster"}
+	expert    = models.User{Role: models.RoleExpert, Name: "Eva Experte"}
+)
+
+func testProject() models.MongoIpaProject {
+	start := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+	first := New("c1", models.CommentInput{Text: "Bitte ergänzen"}, "A01", expert, start)
+	reply := New("c2", models.CommentInput{Text: "Erledigt", ThreadID: "c1"}, "A01", candidate, start.Add(time.Hour))
+	other := New("c3", models.CommentInput{Text: "Quelle fehlt"}, "A02", expert, start.Add(2*time.Hour))
+	deleted := New("c4", models.CommentInput{Text: "Doppelt", ThreadID: "c3"}, "A02", expert, start.Add(3*time.Hour))
+	deleted.Deleted, deleted.Text = true, ""
+	older := New("c0", models.CommentInput{Text: "Zu Beginn"}, "A01", candidate, start.Add(-time.Hour))
+	older.Resolved = true
+	return models.MongoIpaProject{
+		Criteria: []models.Criterion{{ID: "A01"}, {ID: "A02"}, {ID: "A03"}},
+		Comments: []models.Comment{first, reply, other, deleted, older},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       models.CommentInput
+		criterionID string
+		want        error
+	}{
+		{"thread", models.CommentInput{Text: "Frage"}, "A03", nil},
+		{"reply", models.CommentInput{Text: "Antwort", ThreadID: "c1"}, "A01", nil},
+		{"text missing", models.CommentInput{Text: "  "}, "A01", ErrTextMissing},
+		{"text too long", models.CommentInput{Text: strings.Repeat("a", MaxTextLength+1)}, "A01", ErrTextTooLong},
+		{"unknown criterion", models.CommentInput{Text: "Frage"}, "X99", ErrCriterionUnknown},
+		{"unknown thread", models.CommentInput{Text: "Antwort", ThreadID: "c9"}, "A01", ErrThreadUnknown},
+		{"reply as thread", models.CommentInput{Text: "Antwort", ThreadID: "c2"}, "A01", ErrThreadUnknown},
+		{"thread of other criterion", models.CommentInput{Text: "Antwort", ThreadID: "c3"}, "A01", ErrThreadUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.input, tt.criterionID, testProject()); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	comment := New("c5", models.CommentInput{Text: "Frage"}, "A01", expert, time.Now())
+	if comment.ThreadID != "c5" || comment.AuthorKey != "expert:Eva Experte" || comment.Role != models.RoleExpert ||
+		len(comment.ReadBy) != 1 || comment.ReadBy[0] != comment.AuthorKey {
+		t.Errorf("New() = %+v", comment)
+	}
+}
+
+func TestThreads(t *testing.T) {
+	threads := Threads(testProject(), "A01", candidate)
+	if len(threads) != 2 || threads[0].ID != "c0" || threads[1].ID != "c1" {
+		t.Fatalf("Threads() = %+v, want threads c0, c1", threads)
+	}
+	if !threads[0].Resolved || threads[1].Resolved {
+		t.Errorf("Threads() resolved = %v, %v", threads[0].Resolved, threads[1].Resolved)
+	}
+	comments := threads[1].Comments
+	if len(comments) != 2 || comments[0].ID != "c1" || comments[1].ID != "c2" {
+		t.Fatalf("Threads() comments = %+v", comments)
+	}
+	if comments[0].Own || comments[0].Read || !comments[1].Own || !comments[1].Read || threads[1].Unread != 1 {
+		t.Errorf("Threads() flags = %+v, unread %d", comments, threads[1].Unread)
+	}
+
+	// Gelöschte Kommentare zählen nicht als ungelesen
+	if threads := Threads(testProject(), "A02", candidate); len(threads) != 1 || threads[0].Unread != 1 || len(threads[0].Comments) != 2 {
+		t.Errorf("Threads(A02) = %+v", threads)
+	}
+	if threads := Threads(testProject(), "A03", candidate); threads == nil || len(threads) != 0 {
+		t.Errorf("Threads(A03) = %#v, want empty slice", threads)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	summary := Summary(testProject(), candidate)
+	if summary.Unread != 2 || summary.Unresolved != 2 || len(summary.Criteria) != 2 {
+		t.Fatalf("Summary() = %+v", summary)
+	}
+	if first := summary.Criteria[0]; first.CriterionID != "A01" || first.Threads != 2 || first.Unresolved != 1 || first.Unread != 1 {
+		t.Errorf("Summary() A01 = %+v", first)
+	}
+
+	// Eigene Kommentare sind nie ungelesen
+	if summary := Summary(testProject(), expert); summary.Unread != 2 {
+		t.Errorf("Summary(expert) unread = %d, want 2", summary.Unread)
+	}
+}
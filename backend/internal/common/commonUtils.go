@@ -1,7 +1,15 @@
 package common
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 func FormatServerAddress(port int) string {
 	return ":" + fmt.Sprint(port)
 }
+
+// Round rundet auf zwei Nachkommastellen, damit Summen wie 0.1 + 0.2 als 0.3 erscheinen.
+func Round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -22,3 +22,12 @@ func TestFormatServerAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestRound(t *testing.T) {
+	if got := Round(0.1 + 0.2); got != 0.3 {
+		t.Errorf("Round(0.1 + 0.2) = %v, want 0.3", got)
+	}
+	if got := Round(2.675000001); got != 2.68 {
+		t.Errorf("Round(2.675000001) = %v, want 2.68", got)
+	}
+}
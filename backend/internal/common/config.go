@@ -1,17 +1,108 @@
 package common
 
-import "github.com/caarlos0/env/v11"
+import (
+	"fmt"
+	"time"
+
+	"github.com/caarlos0/env/v11"
+)
 
 type Config struct {
 	ServerPort       int    `env:"SERVER_PORT" envDefault:"8080"`
 	CriteriaFilePath string `env:"CRITERIA_FILE_PATH" envDefault:"./criteria.json"`
 	MongoURI         string `env:"MONGO_URI" envDefault:"mongodb://localhost:27017"`
 	TokenSecret      string `env:"TOKEN_SECRET" envDefault:"change-this-secret-in-production"`
+	SnapshotSecret   string `env:"SNAPSHOT_SECRET"`                                   // Key for signing grade snapshots, TOKEN_SECRET when empty
 	SecureCookie     bool   `env:"SECURE_COOKIE" envDefault:"false"`                  // Set to true in production with HTTPS
 	AllowedOrigin    string `env:"ALLOWED_ORIGIN" envDefault:"http://localhost:5173"` // Frontend origin for CORS
+	AdminToken       string `env:"ADMIN_TOKEN"`                                       // Bearer token for /api/admin, admin API is disabled when empty
+
+	OpenAPIValidation bool `env:"OPENAPI_VALIDATION" envDefault:"false"` // Reject requests that do not match the OpenAPI document
+
+	MongoTimeout     time.Duration `env:"MONGO_TIMEOUT" envDefault:"5s"`       // Timeout of database operations on a single project
+	MongoBulkTimeout time.Duration `env:"MONGO_BULK_TIMEOUT" envDefault:"60s"` // Timeout of database operations on many projects, e.g. export, import and backup
+
+	HTTPReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" envDefault:"10s"`
+	HTTPReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT" envDefault:"2m"` // Includes uploads of archives and class rosters
+	HTTPWriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT" envDefault:"2m"`
+	HTTPIdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT" envDefault:"2m"`
+	ShutdownTimeout       time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"15s"` // Time to drain in-flight requests on SIGTERM
+	ReadinessTimeout      time.Duration `env:"READINESS_TIMEOUT" envDefault:"2s"` // Timeout per dependency check of /readyz
+
+	LogFormat string `env:"LOG_FORMAT" envDefault:"text"` // text or json
+	LogLevel  string `env:"LOG_LEVEL" envDefault:"info"`  // debug, info, warn or error
+
+	ProjectIDAlphabet string `env:"PROJECT_ID_ALPHABET" envDefault:"ABCDEFGHJKLMNPQRSTUVWXYZ23456789"` // Characters of new project IDs, even count
+	ProjectIDLength   int    `env:"PROJECT_ID_LENGTH" envDefault:"6"`                                  // Random characters per project ID, plus one check character
+
+	BackupInterval  time.Duration `env:"BACKUP_INTERVAL" envDefault:"0s"`   // Interval between automatic backups, 0 disables them
+	BackupDir       string        `env:"BACKUP_DIR" envDefault:"./backups"` // Directory for backup snapshots
+	BackupRetention int           `env:"BACKUP_RETENTION" envDefault:"14"`  // Number of snapshots to keep, 0 keeps all
+
+	TimeZone           string  `env:"TIME_ZONE" envDefault:"Europe/Zurich"` // Determines "today" for the timeline of IPA projects
+	PlannedHoursPerDay float64 `env:"PLANNED_HOURS_PER_DAY" envDefault:"8"` // Planned hours per workday of the IPA period, compared with the work journal
+
+	EvidenceDir         string   `env:"EVIDENCE_DIR" envDefault:"./evidence"`                                        // Directory for uploaded evidence files
+	EvidenceMaxSize     int64    `env:"EVIDENCE_MAX_SIZE" envDefault:"10485760"`                                     // Maximum size of an evidence file in bytes
+	EvidenceTypes       []string `env:"EVIDENCE_TYPES" envDefault:"application/pdf,image/png,image/jpeg,text/plain"` // Allowed media types, detected from the content
+	EvidenceScanCommand string   `env:"EVIDENCE_SCAN_COMMAND"`                                                       // Virus scanner reading the file from stdin, e.g. "clamdscan --no-summary -"; no scan when empty
 }
 
 func LoadConfig() (cfg Config, err error) {
-	err = env.Parse(&cfg)
-	return cfg, err
+	if err = env.Parse(&cfg); err != nil {
+		return cfg, err
+	}
+	if err = cfg.checkTimeouts(); err != nil {
+		return cfg, err
+	}
+	if _, err = cfg.Location(); err != nil {
+		return cfg, err
+	}
+	if cfg.PlannedHoursPerDay <= 0 || cfg.PlannedHoursPerDay > 24 {
+		return cfg, fmt.Errorf("PLANNED_HOURS_PER_DAY must be between 0 and 24, got %v", cfg.PlannedHoursPerDay)
+	}
+	if cfg.EvidenceMaxSize <= 0 {
+		return cfg, fmt.Errorf("EVIDENCE_MAX_SIZE must be positive, got %d", cfg.EvidenceMaxSize)
+	}
+	return cfg, cfg.ProjectIDScheme().Check()
+}
+
+// checkTimeouts stellt sicher, dass keine Operation sofort abgebrochen wird.
+func (cfg Config) checkTimeouts() error {
+	timeouts := map[string]time.Duration{
+		"MONGO_TIMEOUT":            cfg.MongoTimeout,
+		"MONGO_BULK_TIMEOUT":       cfg.MongoBulkTimeout,
+		"HTTP_READ_HEADER_TIMEOUT": cfg.HTTPReadHeaderTimeout,
+		"SHUTDOWN_TIMEOUT":         cfg.ShutdownTimeout,
+		"READINESS_TIMEOUT":        cfg.ReadinessTimeout,
+	}
+	for name, timeout := range timeouts {
+		if timeout <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", name, timeout)
+		}
+	}
+	return nil
+}
+
+// ProjectIDScheme liefert das konfigurierte Schema für neue Projekt-IDs.
+func (cfg Config) ProjectIDScheme() ProjectIDScheme {
+	return ProjectIDScheme{Alphabet: cfg.ProjectIDAlphabet, Length: cfg.ProjectIDLength}
+}
+
+// SnapshotKey liefert den Schlüssel für die Signatur der Bewertungen. Ein eigener Schlüssel
+// erlaubt es, TOKEN_SECRET zu wechseln, ohne dass bestehende Signaturen ungültig werden.
+func (cfg Config) SnapshotKey() []byte {
+	if cfg.SnapshotSecret != "" {
+		return []byte(cfg.SnapshotSecret)
+	}
+	return []byte(cfg.TokenSecret)
+}
+
+// Location liefert die konfigurierte Zeitzone.
+func (cfg Config) Location() (*time.Location, error) {
+	loc, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("TIME_ZONE: %w", err)
+	}
+	return loc, nil
 }
@@ -0,0 +1,64 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadConfigDefaults(t *testing.T) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.MongoTimeout != 5*time.Second || cfg.MongoBulkTimeout != time.Minute || cfg.ShutdownTimeout != 15*time.Second {
+		t.Errorf("LoadConfig() timeouts = %s, %s, %s", cfg.MongoTimeout, cfg.MongoBulkTimeout, cfg.ShutdownTimeout)
+	}
+	if cfg.EvidenceMaxSize != 10<<20 || len(cfg.EvidenceTypes) != 4 || cfg.EvidenceTypes[0] != "application/pdf" {
+		t.Errorf("LoadConfig() evidence limits = %d, %v", cfg.EvidenceMaxSize, cfg.EvidenceTypes)
+	}
+}
+
+func TestLoadConfigRejectsNonPositiveTimeouts(t *testing.T) {
+	for _, name := range []string{"MONGO_TIMEOUT", "MONGO_BULK_TIMEOUT", "SHUTDOWN_TIMEOUT", "READINESS_TIMEOUT"} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, "0s")
+			if _, err := LoadConfig(); err == nil {
+				t.Errorf("LoadConfig() accepted %s=0s", name)
+			}
+		})
+	}
+}
+
+func TestLoadConfigRejectsUnknownTimeZone(t *testing.T) {
+	t.Setenv("TIME_ZONE", "Europe/Atlantis")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("LoadConfig() accepted an unknown time zone")
+	}
+}
+
+func TestLoadConfigRejectsInvalidPlannedHours(t *testing.T) {
+	for _, hours := range []string{"0", "-8", "25"} {
+		t.Setenv("PLANNED_HOURS_PER_DAY", hours)
+		if _, err := LoadConfig(); err == nil {
+			t.Errorf("LoadConfig() accepted PLANNED_HOURS_PER_DAY=%s", hours)
+		}
+	}
+}
+
+func TestLoadConfigRejectsInvalidEvidenceSize(t *testing.T) {
+	t.Setenv("EVIDENCE_MAX_SIZE", "0")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("LoadConfig() accepted EVIDENCE_MAX_SIZE=0")
+	}
+}
+
+func TestSnapshotKeyFallsBackToTokenSecret(t *testing.T) {
+	cfg := Config{TokenSecret: "token"}
+	if key := string(cfg.SnapshotKey()); key != "token" {
+		t.Errorf("SnapshotKey() = %q, want token secret", key)
+	}
+	cfg.SnapshotSecret = "snapshot"
+	if key := string(cfg.SnapshotKey()); key != "snapshot" {
+		t.Errorf("SnapshotKey() = %q, want snapshot secret", key)
+	}
+}
@@ -33,3 +33,15 @@ func IsCriterionPart2(criterionID string) bool {
 func IsCriterionPart1(criterionID string) bool {
 	return !IsCriterionPart2(criterionID)
 }
+
+// CriterionCategory returns the category of a criterion, i.e. the leading
+// letters of its ID in upper case ("B01s" -> "B", "Doc03" -> "DOC").
+func CriterionCategory(criterionID string) string {
+	end := strings.IndexFunc(criterionID, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z')
+	})
+	if end < 0 {
+		end = len(criterionID)
+	}
+	return strings.ToUpper(criterionID[:end])
+}
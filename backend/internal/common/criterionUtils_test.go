@@ -90,3 +90,26 @@ func TestIsCriterionPart2(t *testing.T) {
 		})
 	}
 }
+
+func TestCriterionCategory(t *testing.T) {
+	tests := []struct {
+		name        string
+		criterionID string
+		want        string
+	}{
+		{"single letter", "B02", "B"},
+		{"suffix after number", "B01s", "B"},
+		{"lowercase", "g11", "G"},
+		{"documentation", "Doc03", "DOC"},
+		{"letters only", "H", "H"},
+		{"empty string", "", ""},
+		{"starts with digit", "01A", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CriterionCategory(tt.criterionID); got != tt.want {
+				t.Errorf("CriterionCategory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// sensitiveKeys sind Attribute, deren Wert nie im Log erscheinen darf.
+var sensitiveKeys = map[string]bool{
+	"password":        true,
+	"currentpassword": true,
+	"newpassword":     true,
+	"passwordhash":    true,
+	"token":           true,
+	"authorization":   true,
+	"cookie":          true,
+	"secret":          true,
+}
+
+// redacted ersetzt den Wert sensibler Attribute.
+const redacted = "[REDACTED]"
+
+// NewLogger erstellt den Logger gemäss LOG_FORMAT und LOG_LEVEL.
+func NewLogger(cfg Config, w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		return nil, fmt.Errorf("invalid LOG_LEVEL %q: %w", cfg.LogLevel, err)
+	}
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+
+	switch strings.ToLower(cfg.LogFormat) {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	default:
+		return nil, fmt.Errorf("invalid LOG_FORMAT %q, expected text or json", cfg.LogFormat)
+	}
+}
+
+// redactAttr verhindert, dass Passwörter oder Tokens versehentlich geloggt werden.
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, redacted)
+	}
+	return attr
+}
+
+type loggerKey struct{}
+
+// WithLogger legt den Logger im Kontext ab, z.B. mit der Request-ID einer Anfrage.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// Logger liefert den Logger aus dem Kontext oder den Standard-Logger.
+func Logger(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
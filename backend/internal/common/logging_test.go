@@ -0,0 +1,63 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"text", Config{LogFormat: "text", LogLevel: "info"}, false},
+		{"json upper case", Config{LogFormat: "JSON", LogLevel: "DEBUG"}, false},
+		{"unknown format", Config{LogFormat: "xml", LogLevel: "info"}, true},
+		{"unknown level", Config{LogFormat: "text", LogLevel: "verbose"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewLogger(tt.cfg, &bytes.Buffer{}); (err != nil) != tt.wantErr {
+				t.Errorf("NewLogger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoggerRedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(Config{LogFormat: "json", LogLevel: "info"}, &buf)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	logger.Info("login", "project_id", "K7QX2M3", "password", "geheim", "Token", "abc.def")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid JSON log line %q: %v", buf.String(), err)
+	}
+	if entry["project_id"] != "K7QX2M3" || entry["password"] != redacted || entry["Token"] != redacted {
+		t.Errorf("log entry = %v", entry)
+	}
+	if strings.Contains(buf.String(), "geheim") || strings.Contains(buf.String(), "abc.def") {
+		t.Errorf("log contains secret: %s", buf.String())
+	}
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger, _ := NewLogger(Config{LogFormat: "text", LogLevel: "info"}, &buf)
+	ctx := WithLogger(context.Background(), logger.With("request_id", "r-1"))
+
+	Logger(ctx).Info("hello")
+	if !strings.Contains(buf.String(), "request_id=r-1") {
+		t.Errorf("log line %q lacks request id", buf.String())
+	}
+	if Logger(context.Background()) == nil {
+		t.Error("Logger() without logger in context returned nil")
+	}
+}
@@ -1,10 +1,16 @@
 package common
 
 import (
+	"crypto/rand"
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
+// FormatProjectID und ParseProjectID beschreiben das alte, fortlaufende
+// ID-Schema ("AA01"). Neue Projekte erhalten IDs aus einem ProjectIDScheme.
+
 func FormatProjectID(n int) string {
 	const maxPerPrefix = 100 // 00–99
 
@@ -39,3 +45,91 @@ func ParseProjectID(id string) (int, error) {
 
 	return prefixIndex*100 + num, nil
 }
+
+// ProjectIDScheme beschreibt das Schema für zufällige, nicht erratbare Projekt-IDs.
+// Eine ID besteht aus Length zufälligen Zeichen aus Alphabet und einem
+// Prüfzeichen (Luhn mod N), das Tippfehler beim Login erkennt.
+type ProjectIDScheme struct {
+	Alphabet string
+	Length   int
+}
+
+// Check prüft, ob das Schema verwendbar ist.
+func (s ProjectIDScheme) Check() error {
+	// Luhn mod N erkennt nur bei gerader Zeichenanzahl alle Einzelfehler
+	if len(s.Alphabet) < 2 || len(s.Alphabet)%2 != 0 {
+		return fmt.Errorf("project id alphabet needs an even number of characters, got %d", len(s.Alphabet))
+	}
+	seen := make(map[rune]bool)
+	for _, r := range s.Alphabet {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return fmt.Errorf("project id alphabet may only contain A-Z and 0-9, got %q", r)
+		}
+		if seen[r] {
+			return fmt.Errorf("project id alphabet contains %q twice", r)
+		}
+		seen[r] = true
+	}
+	if s.Length < 4 {
+		return fmt.Errorf("project id length must be at least 4")
+	}
+	return nil
+}
+
+// Generate erzeugt eine zufällige Projekt-ID inklusive Prüfzeichen.
+func (s ProjectIDScheme) Generate() (string, error) {
+	id := make([]byte, s.Length, s.Length+1)
+	limit := big.NewInt(int64(len(s.Alphabet)))
+	for i := range id {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		id[i] = s.Alphabet[n.Int64()]
+	}
+	return string(append(id, s.checkCharacter(string(id)))), nil
+}
+
+// Validate prüft eine (normalisierte) Projekt-ID. IDs im alten Format ("AA01")
+// haben kein Prüfzeichen und werden weiterhin akzeptiert.
+func (s ProjectIDScheme) Validate(id string) error {
+	if _, err := ParseProjectID(id); err == nil {
+		return nil
+	}
+	if len(id) != s.Length+1 {
+		return fmt.Errorf("invalid id length")
+	}
+	for i := 0; i < len(id); i++ {
+		if strings.IndexByte(s.Alphabet, id[i]) < 0 {
+			return fmt.Errorf("invalid character %q", id[i])
+		}
+	}
+	if s.checkCharacter(id[:s.Length]) != id[s.Length] {
+		return fmt.Errorf("invalid check character")
+	}
+	return nil
+}
+
+// checkCharacter berechnet das Prüfzeichen nach dem Luhn-mod-N-Verfahren.
+func (s ProjectIDScheme) checkCharacter(body string) byte {
+	n := len(s.Alphabet)
+	factor := 2
+	sum := 0
+	for i := len(body) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(s.Alphabet, body[i])
+		factor = 3 - factor
+		sum += addend/n + addend%n
+	}
+	return s.Alphabet[(n-sum%n)%n]
+}
+
+// NormalizeProjectID bereinigt eine eingegebene Projekt-ID: Grossschreibung,
+// Leerzeichen und Bindestriche werden entfernt.
+func NormalizeProjectID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '\t' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(id)))
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -77,3 +78,122 @@ func TestProjectIDRoundtrip(t *testing.T) {
 		})
 	}
 }
+
+var testScheme = ProjectIDScheme{Alphabet: "ABCDEFGHJKLMNPQRSTUVWXYZ23456789", Length: 6}
+
+func TestProjectIDSchemeCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		scheme  ProjectIDScheme
+		wantErr bool
+	}{
+		{"default scheme", testScheme, false},
+		{"alphabet too short", ProjectIDScheme{Alphabet: "A", Length: 6}, true},
+		{"odd alphabet length", ProjectIDScheme{Alphabet: "ABC", Length: 6}, true},
+		{"lowercase alphabet", ProjectIDScheme{Alphabet: "abcd", Length: 6}, true},
+		{"duplicate characters", ProjectIDScheme{Alphabet: "ABCA", Length: 6}, true},
+		{"too short", ProjectIDScheme{Alphabet: "ABCD", Length: 3}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.scheme.Check(); (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectIDSchemeGenerate(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 1000 {
+		id, err := testScheme.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if len(id) != testScheme.Length+1 {
+			t.Fatalf("Generate() = %q, want length %d", id, testScheme.Length+1)
+		}
+		if err := testScheme.Validate(id); err != nil {
+			t.Fatalf("Validate(%q) error = %v", id, err)
+		}
+		if seen[id] {
+			t.Errorf("Generate() returned %q twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestProjectIDSchemeValidate(t *testing.T) {
+	id, err := testScheme.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{"generated id", id, false},
+		{"legacy id", "AB12", false},
+		{"too short", id[:len(id)-1], true},
+		{"invalid character", "I" + id[1:], true},
+		{"lowercase", strings.ToLower(id), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := testScheme.Validate(tt.id); (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%q) error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectIDSchemeDetectsTypos(t *testing.T) {
+	id, err := testScheme.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// Jede Änderung eines einzelnen Zeichens wird erkannt
+	for i := 0; i < len(id); i++ {
+		for j := 0; j < len(testScheme.Alphabet); j++ {
+			if testScheme.Alphabet[j] == id[i] {
+				continue
+			}
+			typo := id[:i] + string(testScheme.Alphabet[j]) + id[i+1:]
+			if testScheme.Validate(typo) == nil {
+				t.Errorf("Validate(%q) accepted single character typo of %q", typo, id)
+			}
+		}
+	}
+
+	// Vertauschungen benachbarter, unterschiedlicher Zeichen werden erkannt
+	for i := 0; i < len(id)-1; i++ {
+		if id[i] == id[i+1] {
+			continue
+		}
+		swapped := id[:i] + string(id[i+1]) + string(id[i]) + id[i+2:]
+		if testScheme.Validate(swapped) == nil {
+			t.Errorf("Validate(%q) accepted transposition of %q", swapped, id)
+		}
+	}
+}
+
+func TestNormalizeProjectID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"AA01", "AA01"},
+		{" aa01 ", "AA01"},
+		{"abc-def-g", "ABCDEFG"},
+		{"ABC DEF G", "ABCDEFG"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := NormalizeProjectID(tt.id); got != tt.want {
+				t.Errorf("NormalizeProjectID(%q) = %q, want %q", tt.id, got, tt.want)
+			}
+		})
+	}
+}
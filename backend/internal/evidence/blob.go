@@ -0,0 +1,86 @@
+package evidence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrBlobNotFound meldet eine Datei, die im BlobStore fehlt.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore speichert die hochgeladenen Dateien. Der Schlüssel ist die ID des Belegs.
+// Neben DiskStore lassen sich so z.B. Objektspeicher anbinden.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// DiskStore legt die Dateien in einem Verzeichnis auf der lokalen Festplatte ab.
+type DiskStore struct {
+	Dir string
+}
+
+func (s DiskStore) path(key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.Dir, key), nil
+}
+
+// Put speichert den Inhalt von r unter key. Eine bestehende Datei wird ersetzt.
+func (s DiskStore) Put(_ context.Context, key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o750); err != nil {
+		return err
+	}
+
+	// Erst in eine temporäre Datei schreiben, damit nie eine halbe Datei ausgeliefert wird
+	tmp, err := os.CreateTemp(s.Dir, ".upload-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Open öffnet die Datei unter key.
+func (s DiskStore) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return file, err
+}
+
+// Delete löscht die Datei unter key. Eine fehlende Datei ist kein Fehler.
+func (s DiskStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Package evidence prüft Belege zu den Anforderungen der Kriterien, speichert hochgeladene
+// Dateien und stellt die Belege eines Projekts in einem Bericht zusammen.
+package evidence
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/notes"
+)
+
+// SniffLength ist die Anzahl Bytes, aus denen der Dateityp erkannt wird.
+const SniffLength = 512
+
+var (
+	ErrCriterionUnknown = errors.New("criterion does not exist in the project")
+	ErrRequirement      = errors.New("requirement does not exist in the criterion")
+	ErrDocumentMissing  = errors.New("document is missing")
+	ErrPage             = errors.New("page must not be negative")
+	ErrURL              = errors.New("url must be an absolute http or https url")
+	ErrTooLarge         = errors.New("file is too large")
+	ErrType             = errors.New("file type is not allowed")
+)
+
+// Limits begrenzt Grösse und Typ hochgeladener Dateien.
+type Limits struct {
+	MaxSize int64    // Bytes
+	Types   []string // Erlaubte Medientypen, z.B. application/pdf
+}
+
+// Check prüft die Grösse einer Datei und erkennt ihren Typ aus den ersten SniffLength Bytes.
+// Die Angabe des Browsers wird nicht übernommen, damit sich z.B. HTML nicht als PDF ausgibt.
+func (l Limits) Check(size int64, head []byte) (string, error) {
+	if size > l.MaxSize {
+		return "", ErrTooLarge
+	}
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil || !slices.Contains(l.Types, contentType) {
+		return "", fmt.Errorf("%w: %s", ErrType, contentType)
+	}
+	return contentType, nil
+}
+
+// Validate prüft, ob der Beleg zu einer Anforderung eines Kriteriums des Projekts gehört,
+// und bei Verweisen die Angaben zum Dokument.
+func Validate(evidence models.Evidence, project models.MongoIpaProject) error {
+	index := slices.IndexFunc(project.Criteria, func(criterion models.Criterion) bool {
+		return criterion.ID == evidence.CriterionID
+	})
+	if index < 0 {
+		return ErrCriterionUnknown
+	}
+	if evidence.Requirement < 0 || evidence.Requirement >= len(project.Criteria[index].Requirements) {
+		return ErrRequirement
+	}
+	if evidence.Kind != models.EvidenceLink {
+		return nil
+	}
+
+	switch {
+	case evidence.Document == "":
+		return ErrDocumentMissing
+	case evidence.Page < 0:
+		return ErrPage
+	case evidence.URL != "" && !validURL(evidence.URL):
+		return ErrURL
+	}
+	return nil
+}
+
+func validURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Find liefert den Beleg mit der ID id.
+func Find(project models.MongoIpaProject, id string) (models.Evidence, bool) {
+	index := slices.IndexFunc(project.Evidence, func(evidence models.Evidence) bool {
+		return evidence.ID == id
+	})
+	if index < 0 {
+		return models.Evidence{}, false
+	}
+	return project.Evidence[index], true
+}
+
+// Report stellt die Belege pro Kriterium und Anforderung zusammen, in der Reihenfolge der
+// Kriterien des Projekts. Belege zu gelöschten Kriterien oder Anforderungen erscheinen nicht.
+func Report(project models.MongoIpaProject) models.EvidenceReport {
+	report := models.EvidenceReport{Criteria: make([]models.CriterionEvidence, len(project.Criteria))}
+	for i, criterion := range project.Criteria {
+		requirements := make([]models.RequirementEvidence, len(criterion.Requirements))
+		for index, text := range criterion.Requirements {
+			requirements[index] = models.RequirementEvidence{
+				Index:    index,
+				Text:     text,
+				Checked:  slices.Contains(criterion.Checked, index),
+				Evidence: make([]models.Evidence, 0),
+			}
+		}
+		for _, evidence := range project.Evidence {
+			if evidence.CriterionID == criterion.ID && evidence.Requirement >= 0 && evidence.Requirement < len(requirements) {
+				requirement := &requirements[evidence.Requirement]
+				requirement.Evidence = append(requirement.Evidence, evidence)
+			}
+		}
+		for _, requirement := range requirements {
+			if requirement.Checked && len(requirement.Evidence) == 0 {
+				report.CheckedWithoutEvidence++
+			}
+		}
+		report.Criteria[i] = models.CriterionEvidence{
+			CriterionID:  criterion.ID,
+			Title:        criterion.Title,
+			NotesHTML:    notes.Render(criterion.Notes),
+			Requirements: requirements,
+		}
+	}
+	return report
+}
@@ -0,0 +1,166 @@
+package evidence
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+func testProject() models.MongoIpaProject {
+	return models.MongoIpaProject{
+		Criteria: []models.Criterion{
+			{ID: "A01", Title: "Auftragsanalyse", Requirements: []string{"Analyse", "Grundlage", "Methode"}, Checked: []int{0, 2}, Notes: "**Scrum**"},
+			{ID: "A02", Title: "Zeitplan", Requirements: []string{"Plan"}, Checked: []int{}},
+		},
+		Evidence: []models.Evidence{
+			{ID: "e1", CriterionID: "A01", Requirement: 0, Kind: models.EvidenceFile, Title: "Analyse.pdf"},
+			{ID: "e2", CriterionID: "A01", Requirement: 0, Kind: models.EvidenceLink, Title: "Bericht", Document: "Bericht", Page: 4},
+			{ID: "e3", CriterionID: "A01", Requirement: 1, Kind: models.EvidenceLink, Title: "Bericht", Document: "Bericht"},
+			{ID: "e4", CriterionID: "X99", Requirement: 0, Kind: models.EvidenceLink, Title: "Gelöschtes Kriterium", Document: "Bericht"},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	link := func(document string, page int, url string) models.Evidence {
+		return models.Evidence{CriterionID: "A01", Requirement: 2, Kind: models.EvidenceLink, Document: document, Page: page, URL: url}
+	}
+	tests := []struct {
+		name     string
+		evidence models.Evidence
+		want     error
+	}{
+		{"file", models.Evidence{CriterionID: "A01", Requirement: 2, Kind: models.EvidenceFile}, nil},
+		{"link", link("IPA-Bericht", 12, "https://example.org/bericht.pdf"), nil},
+		{"link without page", link("IPA-Bericht", 0, ""), nil},
+		{"unknown criterion", models.Evidence{CriterionID: "X99", Kind: models.EvidenceFile}, ErrCriterionUnknown},
+		{"requirement out of range", models.Evidence{CriterionID: "A01", Requirement: 3, Kind: models.EvidenceFile}, ErrRequirement},
+		{"negative requirement", models.Evidence{CriterionID: "A01", Requirement: -1, Kind: models.EvidenceFile}, ErrRequirement},
+		{"document missing", link("", 12, ""), ErrDocumentMissing},
+		{"negative page", link("IPA-Bericht", -1, ""), ErrPage},
+		{"script url", link("IPA-Bericht", 1, "javascript:alert(1)"), ErrURL},
+		{"relative url", link("IPA-Bericht", 1, "/bericht.pdf"), ErrURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.evidence, testProject())
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitsCheck(t *testing.T) {
+	limits := Limits{MaxSize: 1024, Types: []string{"application/pdf", "text/plain"}}
+	tests := []struct {
+		name     string
+		size     int64
+		head     string
+		wantType string
+		want     error
+	}{
+		{"pdf", 900, "%PDF-1.7\n", "application/pdf", nil},
+		{"text with charset", 12, "Hallo Welt\n", "text/plain", nil},
+		{"too large", 1025, "%PDF-1.7\n", "", ErrTooLarge},
+		{"html disguised as pdf", 40, "<html><script>alert(1)</script>", "", ErrType},
+		{"png not allowed", 40, "\x89PNG\r\n\x1a\n", "", ErrType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, err := limits.Check(tt.size, []byte(tt.head))
+			if contentType != tt.wantType || !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("Check() = %q, %v; want %q, %v", contentType, err, tt.wantType, tt.want)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	report := Report(testProject())
+	if len(report.Criteria) != 2 || report.Criteria[0].CriterionID != "A01" {
+		t.Fatalf("Report() criteria = %+v", report.Criteria)
+	}
+	if report.Criteria[0].NotesHTML != "<p><strong>Scrum</strong></p>\n" || report.Criteria[1].NotesHTML != "" {
+		t.Errorf("Report() notes = %q, %q", report.Criteria[0].NotesHTML, report.Criteria[1].NotesHTML)
+	}
+	requirements := report.Criteria[0].Requirements
+	got := []int{len(requirements[0].Evidence), len(requirements[1].Evidence), len(requirements[2].Evidence)}
+	if got[0] != 2 || got[1] != 1 || got[2] != 0 {
+		t.Errorf("evidence per requirement = %v, want [2 1 0]", got)
+	}
+	if !requirements[0].Checked || requirements[1].Checked || requirements[1].Text != "Grundlage" {
+		t.Errorf("requirements = %+v", requirements)
+	}
+	// Anforderung 3 von A01 ist erfüllt, aber ohne Beleg
+	if report.CheckedWithoutEvidence != 1 {
+		t.Errorf("CheckedWithoutEvidence = %d, want 1", report.CheckedWithoutEvidence)
+	}
+	if evidence := report.Criteria[1].Requirements[0].Evidence; evidence == nil || len(evidence) != 0 {
+		t.Errorf("evidence of A02 = %v, want empty list", evidence)
+	}
+}
+
+func TestFind(t *testing.T) {
+	if evidence, ok := Find(testProject(), "e3"); !ok || evidence.Requirement != 1 {
+		t.Errorf("Find(e3) = %+v, %t", evidence, ok)
+	}
+	if _, ok := Find(testProject(), "e9"); ok {
+		t.Error("Find(e9) found an evidence")
+	}
+}
+
+func TestDiskStore(t *testing.T) {
+	ctx := context.Background()
+	blobs := DiskStore{Dir: t.TempDir() + "/evidence"}
+	if err := blobs.Put(ctx, "E7FQ2", strings.NewReader("%PDF-1.7")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	file, err := blobs.Open(ctx, "E7FQ2")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	content, _ := io.ReadAll(file)
+	file.Close()
+	if string(content) != "%PDF-1.7" {
+		t.Errorf("Open() content = %q", content)
+	}
+
+	if err := blobs.Delete(ctx, "E7FQ2"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if _, err := blobs.Open(ctx, "E7FQ2"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Open() after Delete() error = %v, want %v", err, ErrBlobNotFound)
+	}
+	if err := blobs.Delete(ctx, "E7FQ2"); err != nil {
+		t.Errorf("Delete() of missing blob error = %v", err)
+	}
+	for _, key := range []string{"", "..", "../secret", `a\b`} {
+		if err := blobs.Put(ctx, key, strings.NewReader("x")); err == nil {
+			t.Errorf("Put(%q) accepted an invalid key", key)
+		}
+	}
+}
+
+func TestCommandScanner(t *testing.T) {
+	ctx := context.Background()
+	clean := CommandScanner{Command: []string{"sh", "-c", "cat >/dev/null"}}
+	if err := clean.Scan(ctx, strings.NewReader("%PDF-1.7")); err != nil {
+		t.Errorf("Scan() of clean file error = %v", err)
+	}
+	infected := CommandScanner{Command: []string{"sh", "-c", "cat >/dev/null; echo 'stream: Eicar-Signature FOUND'; exit 1"}}
+	if err := infected.Scan(ctx, strings.NewReader("X5O!P%@AP")); !errors.Is(err, ErrInfected) || !strings.Contains(err.Error(), "Eicar") {
+		t.Errorf("Scan() of infected file error = %v, want %v", err, ErrInfected)
+	}
+	broken := CommandScanner{Command: []string{"sh", "-c", "exit 2"}}
+	if err := broken.Scan(ctx, strings.NewReader("")); err == nil || errors.Is(err, ErrInfected) {
+		t.Errorf("Scan() with failing scanner error = %v", err)
+	}
+	if NewCommandScanner("  ") != nil {
+		t.Error("NewCommandScanner() without command != nil")
+	}
+}
@@ -0,0 +1,54 @@
+package evidence
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// ErrInfected meldet eine Datei, in der der Virenscanner Schadsoftware gefunden hat.
+var ErrInfected = errors.New("file is infected")
+
+// Scanner prüft eine hochgeladene Datei, bevor sie gespeichert wird.
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) error
+}
+
+// CommandScanner übergibt die Datei einem externen Virenscanner auf der Standardeingabe,
+// z.B. "clamdscan --no-summary -". Wie bei ClamAV bedeutet der Exit-Code 1 einen Fund,
+// jeder andere Exit-Code ausser 0 einen Fehler des Scanners.
+type CommandScanner struct {
+	Command []string
+}
+
+// NewCommandScanner liefert einen Scanner für die Befehlszeile command. Ist sie leer,
+// wird nil zurückgegeben und Dateien werden nicht geprüft.
+func NewCommandScanner(command string) Scanner {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil
+	}
+	return CommandScanner{Command: fields}
+}
+
+func (s CommandScanner) Scan(ctx context.Context, r io.Reader) error {
+	cmd := exec.CommandContext(ctx, s.Command[0], s.Command[1:]...)
+	cmd.Stdin = r
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return fmt.Errorf("%w: %s", ErrInfected, strings.TrimSpace(output.String()))
+	}
+	if err != nil {
+		return fmt.Errorf("running virus scanner: %w: %s", err, strings.TrimSpace(output.String()))
+	}
+	return nil
+}
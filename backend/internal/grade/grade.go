@@ -7,6 +7,10 @@ import (
 	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
 )
 
+// Scheme bezeichnet das Berechnungsverfahren von CalculateGrade in den Momentaufnahmen der
+// Bewertung. Es muss geändert werden, wenn sich die Berechnung ändert.
+const Scheme = "quality-levels-v1"
+
 // CalculateGrade berechnet die Note für das IPA-Projekt.
 func CalculateGrade(criteria []models.Criterion) models.GradeResult {
 
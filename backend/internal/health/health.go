@@ -0,0 +1,94 @@
+// Package health prüft die Abhängigkeiten des Servers für Readiness-Probes.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Der Bericht ist öffentlich und nennt nur die Art des Fehlers, die Ursache enthält Result.Cause.
+const (
+	ErrorTimeout = "timeout"
+	ErrorFailed  = "failed"
+)
+
+// Check prüft eine einzelne Abhängigkeit.
+type Check struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// Result ist das Ergebnis einer einzelnen Prüfung.
+type Result struct {
+	Status    string  `json:"status"`
+	Error     string  `json:"error,omitempty"` // ErrorTimeout oder ErrorFailed
+	LatencyMs float64 `json:"latencyMs"`
+	Cause     error   `json:"-"` // Ursache für das Log, etwa mit Adresse des Servers
+}
+
+// Report fasst alle Prüfungen zusammen. Status ist nur "ok", wenn alle Prüfungen erfolgreich waren.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// OK gibt an, ob alle Prüfungen erfolgreich waren.
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Run führt alle Prüfungen parallel aus. Jede Prüfung hat höchstens timeout Zeit.
+func Run(ctx context.Context, timeout time.Duration, checks ...Check) Report {
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Go(func() {
+			result := run(ctx, timeout, check)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusUnavailable
+			}
+		})
+	}
+	wg.Wait()
+	return report
+}
+
+func run(ctx context.Context, timeout time.Duration, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- check.Check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// Prüfungen, die den Kontext ignorieren, dürfen die Probe nicht blockieren
+		err = ctx.Err()
+	}
+
+	result := Result{Status: StatusOK, LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		result.Status = StatusUnavailable
+		result.Error = ErrorFailed
+		if errors.Is(err, context.DeadlineExceeded) {
+			result.Error = ErrorTimeout
+		}
+		result.Cause = err
+	}
+	return result
+}
@@ -0,0 +1,50 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	ok := Check{Name: "catalogue", Check: func(context.Context) error { return nil }}
+	failing := Check{Name: "mongodb", Check: func(context.Context) error { return errors.New("connection refused") }}
+	hanging := Check{Name: "slow", Check: func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}}
+
+	tests := []struct {
+		name       string
+		checks     []Check
+		wantStatus string
+		wantErrors map[string]string
+	}{
+		{"all ok", []Check{ok}, StatusOK, map[string]string{"catalogue": ""}},
+		{"one failing", []Check{ok, failing}, StatusUnavailable, map[string]string{"catalogue": "", "mongodb": ErrorFailed}},
+		{"timeout", []Check{ok, hanging}, StatusUnavailable, map[string]string{"catalogue": "", "slow": ErrorTimeout}},
+		{"no checks", nil, StatusOK, map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			report := Run(context.Background(), 50*time.Millisecond, tt.checks...)
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("Run() took %s, want the timeout to apply", elapsed)
+			}
+			if report.Status != tt.wantStatus || report.OK() != (tt.wantStatus == StatusOK) {
+				t.Errorf("Run() status = %s, want %s", report.Status, tt.wantStatus)
+			}
+			if len(report.Checks) != len(tt.wantErrors) {
+				t.Fatalf("Run() checks = %v", report.Checks)
+			}
+			for name, wantErr := range tt.wantErrors {
+				result := report.Checks[name]
+				if result.Error != wantErr || (result.Status == StatusOK) != (wantErr == "") || (result.Cause == nil) != (wantErr == "") {
+					t.Errorf("Run() check %s = %+v, want error %q", name, result, wantErr)
+				}
+			}
+		})
+	}
+}
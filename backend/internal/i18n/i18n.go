@@ -0,0 +1,110 @@
+// Package i18n wählt die Sprache einer Anfrage und enthält mehrsprachige Texte.
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang ist ein Sprachcode nach ISO 639-1.
+type Lang string
+
+const (
+	German  Lang = "de"
+	French  Lang = "fr"
+	Italian Lang = "it"
+
+	// Default ist die Sprache, in der Kataloge und Projekte gespeichert werden. Fehlt eine
+	// Übersetzung, wird der Text in dieser Sprache verwendet.
+	Default = German
+)
+
+// Supported enthält alle unterstützten Sprachen, die Standardsprache zuerst.
+var Supported = []Lang{German, French, Italian}
+
+// IsSupported meldet, ob lang unterstützt wird.
+func IsSupported(lang Lang) bool {
+	return slices.Contains(Supported, lang)
+}
+
+// Negotiate wählt anhand eines Accept-Language-Headers (RFC 9110) die passendste unterstützte
+// Sprache. Regionen werden ignoriert (fr-CH entspricht fr). Ohne passende Sprache wird
+// Default verwendet.
+func Negotiate(acceptLanguage string) Lang {
+	type candidate struct {
+		lang    Lang
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if lang := Lang(primary); quality > 0 && IsSupported(lang) {
+			candidates = append(candidates, candidate{lang, quality})
+		}
+	}
+	if len(candidates) == 0 {
+		return Default
+	}
+	// Stabil sortieren, bei gleicher Gewichtung gilt die Reihenfolge im Header
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	return candidates[0].lang
+}
+
+// Text ist ein Text in mehreren Sprachen. In JSON ist er entweder ein Objekt mit einem Eintrag
+// pro Sprache ({"de": "…", "fr": "…"}) oder, wie in älteren Katalogen, ein einfacher String
+// in der Standardsprache.
+type Text map[Lang]string
+
+// In liefert den Text in lang oder, falls die Übersetzung fehlt, in der Standardsprache.
+func (t Text) In(lang Lang) string {
+	if text, ok := t[lang]; ok && text != "" {
+		return text
+	}
+	return t[Default]
+}
+
+// Check prüft, ob nur unterstützte Sprachen vorkommen. Ein leerer Text ist gültig.
+func (t Text) Check() error {
+	for lang := range t {
+		if !IsSupported(lang) {
+			return fmt.Errorf("unsupported language %q", lang)
+		}
+	}
+	if len(t) > 0 && t[Default] == "" {
+		return fmt.Errorf("text in default language %q is missing", Default)
+	}
+	return nil
+}
+
+func (t *Text) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*t = nil
+		return nil
+	}
+	var plain string
+	if err := json.Unmarshal(data, &plain); err == nil {
+		*t = Text{Default: plain}
+		return nil
+	}
+	var translations map[Lang]string
+	if err := json.Unmarshal(data, &translations); err != nil {
+		return errors.New("text must be a string or an object with one entry per language")
+	}
+	*t = translations
+	return nil
+}
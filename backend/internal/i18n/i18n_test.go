@@ -0,0 +1,73 @@
+package i18n
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Lang
+	}{
+		{"", German},
+		{"fr", French},
+		{"fr-CH, fr;q=0.9, en;q=0.8", French},
+		{"it-CH", Italian},
+		{"en-US, en;q=0.9", German},
+		{"en, it;q=0.5, fr;q=0.7", French},
+		{"de;q=0.5, it", Italian},
+		{"fr;q=0, it;q=0.1", Italian},
+		{"FR-ch", French},
+		{"*", German},
+		{"fr;q=abc", German},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestTextIn(t *testing.T) {
+	text := Text{German: "Titel", French: "Titre", Italian: ""}
+	for lang, want := range map[Lang]string{German: "Titel", French: "Titre", Italian: "Titel"} {
+		if got := text.In(lang); got != want {
+			t.Errorf("In(%q) = %q, want %q", lang, got, want)
+		}
+	}
+}
+
+func TestTextUnmarshalJSON(t *testing.T) {
+	var value struct {
+		Plain     Text `json:"plain"`
+		Localized Text `json:"localized"`
+	}
+	data := `{"plain": "Titel", "localized": {"de": "Titel", "fr": "Titre"}}`
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if value.Plain.In(French) != "Titel" || value.Localized.In(French) != "Titre" {
+		t.Errorf("json.Unmarshal() = %+v", value)
+	}
+	if err := json.Unmarshal([]byte(`{"plain": 5}`), &value); err == nil {
+		t.Error("json.Unmarshal() accepted a number")
+	}
+}
+
+func TestTextCheck(t *testing.T) {
+	tests := []struct {
+		text    Text
+		wantErr bool
+	}{
+		{nil, false},
+		{Text{German: "Titel", Italian: "Titolo"}, false},
+		{Text{French: "Titre"}, true},
+		{Text{German: "Titel", "en": "Title"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.text.Check(); (err != nil) != tt.wantErr {
+			t.Errorf("Check(%v) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Package journal prüft Einträge im Arbeitsjournal und vergleicht die Stunden mit der Planung.
+package journal
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+// MaxHoursPerDay begrenzt die Summe der Stunden aller Einträge eines Tages.
+const MaxHoursPerDay = 24
+
+var (
+	ErrDateMissing       = errors.New("date is missing")
+	ErrOutsidePeriod     = errors.New("date is outside the IPA period")
+	ErrActivitiesMissing = errors.New("activities are missing")
+	ErrHours             = errors.New("hours must be greater than 0 and at most 24")
+	ErrDayTooLong        = errors.New("entries of the day exceed 24 hours")
+	ErrUnknownTask       = errors.New("task does not exist")
+)
+
+// Validate prüft einen neuen oder geänderten Eintrag des Projekts. Ein bisheriger Eintrag mit
+// derselben ID wird ersetzt und zählt nicht zu den Stunden des Tages. Die zugeordnete Aufgabe
+// muss im Zeitplan des Projekts stehen.
+func Validate(entry models.JournalEntry, project models.MongoIpaProject) error {
+	switch {
+	case entry.Date.IsZero():
+		return ErrDateMissing
+	case !project.StartDate.IsZero() && (entry.Date.Before(project.StartDate) || entry.Date.After(project.EndDate)):
+		return ErrOutsidePeriod
+	case entry.Activities == "":
+		return ErrActivitiesMissing
+	case entry.Hours <= 0 || entry.Hours > MaxHoursPerDay:
+		return ErrHours
+	case entry.TaskID != "" && !slices.ContainsFunc(project.Tasks, func(task models.Task) bool { return task.ID == entry.TaskID }):
+		return ErrUnknownTask
+	}
+	hours := entry.Hours
+	for _, other := range project.Journal {
+		if other.ID != entry.ID && other.Date == entry.Date {
+			hours += other.Hours
+		}
+	}
+	if hours > MaxHoursPerDay {
+		return ErrDayTooLong
+	}
+	return nil
+}
+
+// Sort sortiert die Einträge nach Datum und Erfassungszeit.
+func Sort(entries []models.JournalEntry) {
+	slices.SortStableFunc(entries, func(a, b models.JournalEntry) int {
+		if order := a.Date.Compare(b.Date); order != 0 {
+			return order
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+}
+
+// Summarize liefert das Journal eines Projekts mit den Stunden pro Tag. Geplant sind plannedPerDay
+// Stunden an jedem Arbeitstag im IPA-Zeitraum; Tage mit Einträgen ausserhalb davon erscheinen
+// ohne geplante Stunden.
+func Summarize(project models.MongoIpaProject, plannedPerDay float64) models.Journal {
+	entries := slices.Clone(project.Journal)
+	if entries == nil {
+		entries = make([]models.JournalEntry, 0)
+	}
+	Sort(entries)
+
+	days := make(map[models.Date]*models.JournalDay)
+	day := func(date models.Date) *models.JournalDay {
+		if days[date] == nil {
+			days[date] = &models.JournalDay{Date: date}
+		}
+		return days[date]
+	}
+	if !project.StartDate.IsZero() {
+		for date := project.StartDate; !date.After(project.EndDate); date = date.AddDays(1) {
+			if date.IsWorkday() {
+				day(date).PlannedHours = plannedPerDay
+			}
+		}
+	}
+	for _, entry := range entries {
+		day(entry.Date).Hours += entry.Hours
+	}
+
+	journal := models.Journal{Entries: entries, Days: make([]models.JournalDay, 0, len(days))}
+	for _, d := range days {
+		d.Hours = common.Round(d.Hours)
+		d.Difference = common.Round(d.Hours - d.PlannedHours)
+		journal.TotalHours += d.Hours
+		journal.PlannedHours += d.PlannedHours
+		journal.Days = append(journal.Days, *d)
+	}
+	slices.SortFunc(journal.Days, func(a, b models.JournalDay) int {
+		return a.Date.Compare(b.Date)
+	})
+	journal.TotalHours = common.Round(journal.TotalHours)
+	journal.PlannedHours = common.Round(journal.PlannedHours)
+	return journal
+}
@@ -0,0 +1,109 @@
+package journal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+func date(t *testing.T, value string) models.Date {
+	t.Helper()
+	d, err := models.ParseDate(value)
+	if err != nil {
+		t.Fatalf("ParseDate(%q) error = %v", value, err)
+	}
+	return d
+}
+
+func TestValidate(t *testing.T) {
+	project := models.MongoIpaProject{
+		StartDate: date(t, "2026-03-02"),
+		EndDate:   date(t, "2026-03-13"),
+		Journal:   []models.JournalEntry{{ID: "a", Date: date(t, "2026-03-02"), Activities: "Kick-off", Hours: 20}},
+		Tasks:     []models.Task{{ID: "t1", Title: "Planung"}},
+	}
+	entry := func(id, day string, hours float64) models.JournalEntry {
+		return models.JournalEntry{ID: id, Date: date(t, day), Activities: "Umsetzung", Hours: hours}
+	}
+	tests := []struct {
+		name  string
+		entry models.JournalEntry
+		want  error
+	}{
+		{"valid", entry("b", "2026-03-03", 8), nil},
+		{"date missing", models.JournalEntry{Activities: "Umsetzung", Hours: 8}, ErrDateMissing},
+		{"before period", entry("b", "2026-03-01", 8), ErrOutsidePeriod},
+		{"after period", entry("b", "2026-03-14", 8), ErrOutsidePeriod},
+		{"activities missing", models.JournalEntry{Date: date(t, "2026-03-03"), Hours: 8}, ErrActivitiesMissing},
+		{"no hours", entry("b", "2026-03-03", 0), ErrHours},
+		{"more than a day", entry("b", "2026-03-03", 25), ErrHours},
+		{"day too long", entry("b", "2026-03-02", 4.5), ErrDayTooLong},
+		{"day filled up", entry("b", "2026-03-02", 4), nil},
+		{"replaces same entry", entry("a", "2026-03-02", 24), nil},
+		{"known task", models.JournalEntry{Date: date(t, "2026-03-03"), Activities: "Planung", Hours: 2, TaskID: "t1"}, nil},
+		{"unknown task", models.JournalEntry{Date: date(t, "2026-03-03"), Activities: "Planung", Hours: 2, TaskID: "t2"}, ErrUnknownTask},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.entry, project)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if err := Validate(entry("b", "2025-01-01", 8), models.MongoIpaProject{}); err != nil {
+		t.Errorf("Validate() without period error = %v, want nil", err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	created := time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC)
+	project := models.MongoIpaProject{
+		StartDate: date(t, "2026-03-06"), // Freitag
+		EndDate:   date(t, "2026-03-09"), // Montag
+		Journal: []models.JournalEntry{
+			{ID: "c", Date: date(t, "2026-03-09"), Hours: 0.2, CreatedAt: created.Add(time.Hour)},
+			{ID: "b", Date: date(t, "2026-03-09"), Hours: 0.1, CreatedAt: created},
+			{ID: "a", Date: date(t, "2026-03-06"), Hours: 9},
+			{ID: "d", Date: date(t, "2026-03-07"), Hours: 2}, // Samstag
+			{ID: "e", Date: date(t, "2026-03-20"), Hours: 1}, // Nach dem Zeitraum
+		},
+	}
+	got := Summarize(project, 8)
+
+	var ids string
+	for _, entry := range got.Entries {
+		ids += entry.ID
+	}
+	if ids != "adbce" {
+		t.Errorf("entries in order %q, want %q", ids, "adbce")
+	}
+	want := []models.JournalDay{
+		{Date: date(t, "2026-03-06"), Hours: 9, PlannedHours: 8, Difference: 1},
+		{Date: date(t, "2026-03-07"), Hours: 2, PlannedHours: 0, Difference: 2},
+		{Date: date(t, "2026-03-09"), Hours: 0.3, PlannedHours: 8, Difference: -7.7},
+		{Date: date(t, "2026-03-20"), Hours: 1, PlannedHours: 0, Difference: 1},
+	}
+	if len(got.Days) != len(want) {
+		t.Fatalf("Summarize() days = %+v, want %+v", got.Days, want)
+	}
+	for i := range want {
+		if got.Days[i] != want[i] {
+			t.Errorf("day %d = %+v, want %+v", i, got.Days[i], want[i])
+		}
+	}
+	if got.TotalHours != 12.3 || got.PlannedHours != 16 {
+		t.Errorf("Summarize() total = %v, planned = %v; want 12.3, 16", got.TotalHours, got.PlannedHours)
+	}
+	if project.Journal[0].ID != "c" {
+		t.Error("Summarize() sorted the entries of the project")
+	}
+
+	empty := Summarize(models.MongoIpaProject{}, 8)
+	if empty.Entries == nil || len(empty.Days) != 0 || empty.TotalHours != 0 {
+		t.Errorf("Summarize() of empty project = %+v", empty)
+	}
+}
@@ -0,0 +1,142 @@
+// Package metrics stellt die Prometheus-Metriken des Servers bereit.
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "criteria_catalogue"
+
+// projectCountTimeout begrenzt die Zählung der Projekte pro Scrape.
+const projectCountTimeout = 5 * time.Second
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by method and route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by result.",
+	}, []string{"result"})
+
+	mongoDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mongo_operation_duration_seconds",
+		Help:      "Latency of MongoStore operations.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"operation"})
+
+	mongoErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "mongo_operation_errors_total",
+		Help:      "Failed MongoStore operations.",
+	}, []string{"operation"})
+
+	catalogueReloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "catalogue_reloads_total",
+		Help:      "Reloads of the criteria catalogue by result.",
+	}, []string{"result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration, logins, mongoDuration, mongoErrors, catalogueReloads,
+	)
+}
+
+// Handler liefert alle Metriken im Prometheus-Textformat. Ist die Datenbank nicht
+// erreichbar, fehlt nur die Anzahl Projekte, alle anderen Metriken werden geliefert.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+}
+
+// ObserveHTTPRequest zählt eine HTTP-Anfrage. route ist das Routen-Template, z.B. /api/ipa/:id.
+func ObserveHTTPRequest(method, route string, status int, latency time.Duration) {
+	if route == "" {
+		route = "unmatched" // Begrenzt die Anzahl Label-Werte bei unbekannten Pfaden
+	}
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(latency.Seconds())
+}
+
+// ObserveLogin zählt einen Anmeldeversuch.
+func ObserveLogin(success bool) {
+	logins.WithLabelValues(result(success)).Inc()
+}
+
+// ObserveMongoOperation erfasst Dauer und Erfolg einer Datenbankoperation.
+func ObserveMongoOperation(operation string, latency time.Duration, failed bool) {
+	mongoDuration.WithLabelValues(operation).Observe(latency.Seconds())
+	if failed {
+		mongoErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+// ObserveCatalogueReload zählt ein Neuladen des Kriterienkatalogs.
+func ObserveCatalogueReload(success bool) {
+	catalogueReloads.WithLabelValues(result(success)).Inc()
+}
+
+func result(success bool) string {
+	if success {
+		return "success"
+	}
+	return "failure"
+}
+
+// ProjectCounter zählt die aktiven und archivierten Projekte.
+type ProjectCounter func(ctx context.Context) (active, archived int64, err error)
+
+// RegisterProjectCount meldet die Anzahl Projekte bei jedem Scrape über count.
+func RegisterProjectCount(count ProjectCounter) error {
+	return registry.Register(projectCollector{count: count})
+}
+
+var projectsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "projects"),
+	"Number of IPA projects by archive status.", []string{"archived"}, nil)
+
+type projectCollector struct {
+	count ProjectCounter
+}
+
+func (p projectCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- projectsDesc
+}
+
+func (p projectCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), projectCountTimeout)
+	defer cancel()
+
+	active, archived, err := p.count(ctx)
+	if err != nil {
+		slog.Warn("counting projects for metrics failed", "error", err)
+		ch <- prometheus.NewInvalidMetric(projectsDesc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(projectsDesc, prometheus.GaugeValue, float64(active), "false")
+	ch <- prometheus.MustNewConstMetric(projectsDesc, prometheus.GaugeValue, float64(archived), "true")
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(recorder.Result().Body)
+	if err != nil {
+		t.Fatalf("reading metrics: %v", err)
+	}
+	return string(body)
+}
+
+func TestHandlerExposesObservations(t *testing.T) {
+	ObserveHTTPRequest("GET", "/api/ipa/:id", 200, 12*time.Millisecond)
+	ObserveHTTPRequest("GET", "", 404, time.Millisecond)
+	ObserveLogin(true)
+	ObserveLogin(false)
+	ObserveMongoOperation("GetIpaProject", 3*time.Millisecond, false)
+	ObserveMongoOperation("UpdatePassword", 4*time.Millisecond, true)
+	ObserveCatalogueReload(true)
+
+	body := scrape(t)
+	for _, want := range []string{
+		`criteria_catalogue_http_requests_total{method="GET",route="/api/ipa/:id",status="200"} 1`,
+		`criteria_catalogue_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`criteria_catalogue_http_request_duration_seconds_count{method="GET",route="/api/ipa/:id"} 1`,
+		`criteria_catalogue_logins_total{result="success"} 1`,
+		`criteria_catalogue_logins_total{result="failure"} 1`,
+		`criteria_catalogue_mongo_operation_duration_seconds_count{operation="GetIpaProject"} 1`,
+		`criteria_catalogue_mongo_operation_errors_total{operation="UpdatePassword"} 1`,
+		`criteria_catalogue_catalogue_reloads_total{result="success"} 1`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics lack %q", want)
+		}
+	}
+	if strings.Contains(body, `mongo_operation_errors_total{operation="GetIpaProject"}`) {
+		t.Error("successful operation counted as error")
+	}
+}
+
+func TestProjectCollector(t *testing.T) {
+	var fail bool
+	err := RegisterProjectCount(func(context.Context) (int64, int64, error) {
+		if fail {
+			return 0, 0, errors.New("database down")
+		}
+		return 12, 3, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterProjectCount() error = %v", err)
+	}
+
+	body := scrape(t)
+	if !strings.Contains(body, `criteria_catalogue_projects{archived="false"} 12`) ||
+		!strings.Contains(body, `criteria_catalogue_projects{archived="true"} 3`) {
+		t.Errorf("metrics lack project counts:\n%s", body)
+	}
+
+	fail = true
+	body = scrape(t)
+	if strings.Contains(body, "criteria_catalogue_projects{") || !strings.Contains(body, "go_goroutines") {
+		t.Errorf("scrape with failing project count returned:\n%s", body)
+	}
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Date ist ein Kalendertag ohne Uhrzeit und Zeitzone. In JSON und BSON wird er als
+// "YYYY-MM-DD" dargestellt, damit gespeicherte Tage als Text sortiert und verglichen
+// werden können. Der Nullwert steht für einen fehlenden Tag.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateError meldet einen Text, der kein gültiger Tag ist, z.B. "2025-02-30".
+type DateError struct {
+	Value string
+}
+
+func (e *DateError) Error() string {
+	return fmt.Sprintf("invalid date %q, expected YYYY-MM-DD", e.Value)
+}
+
+// ParseDate liest einen Tag im Format YYYY-MM-DD. Ein leerer Text ergibt den Nullwert.
+func ParseDate(value string) (Date, error) {
+	if value == "" {
+		return Date{}, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return Date{}, &DateError{Value: value}
+	}
+	return DateOf(t), nil
+}
+
+// DateOf liefert den Tag von t in der Zeitzone von t.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{year, month, day}
+}
+
+// IsZero meldet, ob der Tag fehlt.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// time liefert den Beginn des Tages in UTC, damit Differenzen nicht von Sommerzeit abhängen.
+func (d Date) time() time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC)
+}
+
+// Before meldet, ob d vor other liegt.
+func (d Date) Before(other Date) bool {
+	return d.time().Before(other.time())
+}
+
+// After meldet, ob d nach other liegt.
+func (d Date) After(other Date) bool {
+	return d.time().After(other.time())
+}
+
+// Compare liefert -1, 0 oder +1, je nachdem ob d vor, gleich oder nach other liegt.
+func (d Date) Compare(other Date) int {
+	return d.time().Compare(other.time())
+}
+
+// AddDays liefert den Tag days Tage nach d.
+func (d Date) AddDays(days int) Date {
+	return DateOf(d.time().AddDate(0, 0, days))
+}
+
+// DaysUntil liefert die Anzahl Tage von d bis other, negativ wenn other vor d liegt.
+func (d Date) DaysUntil(other Date) int {
+	return int(other.time().Sub(d.time()).Hours() / 24)
+}
+
+// Weekday liefert den Wochentag.
+func (d Date) Weekday() time.Weekday {
+	return d.time().Weekday()
+}
+
+// IsWorkday meldet, ob d ein Tag von Montag bis Freitag ist. Feiertage sind kantonal
+// verschieden und werden nicht berücksichtigt.
+func (d Date) IsWorkday() bool {
+	weekday := d.Weekday()
+	return weekday != time.Saturday && weekday != time.Sunday
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return &DateError{Value: string(data)}
+	}
+	if value == nil {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(*value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Date) MarshalBSONValue() (byte, []byte, error) {
+	typ, data, err := bson.MarshalValue(d.String())
+	return byte(typ), data, err
+}
+
+func (d *Date) UnmarshalBSONValue(typ byte, data []byte) error {
+	if bson.Type(typ) == bson.TypeNull {
+		*d = Date{}
+		return nil
+	}
+	var value string
+	if err := bson.UnmarshalValue(bson.Type(typ), data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
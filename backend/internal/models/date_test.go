@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestParseDate(t *testing.T) {
+	for _, value := range []string{"2026-02-30", "2026-13-01", "02.03.2026", "2026-3-2"} {
+		var dateErr *DateError
+		if _, err := ParseDate(value); !errors.As(err, &dateErr) || dateErr.Value != value {
+			t.Errorf("ParseDate(%q) error = %v, want DateError", value, err)
+		}
+	}
+	if d, err := ParseDate("2028-02-29"); err != nil || d != (Date{2028, 2, 29}) {
+		t.Errorf("ParseDate(leap day) = %v, %v", d, err)
+	}
+	if d, err := ParseDate(""); err != nil || !d.IsZero() {
+		t.Errorf("ParseDate(\"\") = %v, %v", d, err)
+	}
+}
+
+func TestDateArithmetic(t *testing.T) {
+	d := Date{2026, 3, 27}
+	if got := d.AddDays(3); got != (Date{2026, 3, 30}) {
+		t.Errorf("AddDays(3) = %s", got)
+	}
+	// Die Umstellung auf Sommerzeit am 29. März ändert die Anzahl Tage nicht
+	if got := d.DaysUntil(Date{2026, 4, 3}); got != 7 {
+		t.Errorf("DaysUntil() = %d, want 7", got)
+	}
+	if got := d.DaysUntil(Date{2026, 3, 20}); got != -7 {
+		t.Errorf("DaysUntil() = %d, want -7", got)
+	}
+}
+
+func TestDateEncoding(t *testing.T) {
+	var project struct {
+		StartDate Date `json:"startDate,omitzero" bson:"startDate,omitempty"`
+		EndDate   Date `json:"endDate,omitzero" bson:"endDate,omitempty"`
+	}
+	if err := json.Unmarshal([]byte(`{"startDate": "2026-03-02", "endDate": null}`), &project); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	data, err := json.Marshal(project)
+	if err != nil || string(data) != `{"startDate":"2026-03-02"}` {
+		t.Errorf("json.Marshal() = %s, %v", data, err)
+	}
+
+	var dateErr *DateError
+	if err := json.Unmarshal([]byte(`{"startDate": "2026-02-30"}`), &project); !errors.As(err, &dateErr) {
+		t.Errorf("json.Unmarshal() error = %v, want DateError", err)
+	}
+
+	doc, err := bson.Marshal(project)
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+	var raw bson.M
+	if err := bson.Unmarshal(doc, &raw); err != nil || raw["startDate"] != "2026-03-02" || len(raw) != 1 {
+		t.Errorf("bson document = %v, %v", raw, err)
+	}
+	project.StartDate = Date{}
+	if err := bson.Unmarshal(doc, &project); err != nil || project.StartDate != (Date{2026, 3, 2}) {
+		t.Errorf("bson.Unmarshal() = %+v, %v", project, err)
+	}
+}
@@ -3,62 +3,102 @@ package models
 import (
 	"errors"
 	"fmt"
-
-	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"time"
 )
 
 // IpaProject speichert die persönlichen Informationen.
 type MongoIpaProject struct {
-	ID           int         `json:"id" bson:"id"` // ^[A-Z]{2}\d{2}$
-	Firstname    string      `json:"firstname" bson:"firstname"`
-	Lastname     string      `json:"lastname" bson:"lastname"`
-	Topic        string      `json:"topic" bson:"topic"`
-	Date         string      `json:"date" bson:"date"`
-	PasswordHash string      `json:"-" bson:"passwordHash"` // Never expose password hash in JSON
-	Criteria     []Criterion `json:"criteria" bson:"criteria"`
+	ID                     string          `json:"id" bson:"publicId"`    // Zufällige ID mit Prüfzeichen oder altes Format ^[A-Z]{2}\d{2}$
+	LegacyID               int             `json:"-" bson:"id,omitempty"` // Fortlaufende Nummer, nur bei Projekten aus dem alten ID-Schema
+	Firstname              string          `json:"firstname" bson:"firstname"`
+	Lastname               string          `json:"lastname" bson:"lastname"`
+	Topic                  string          `json:"topic" bson:"topic"`
+	Date                   string          `json:"date" bson:"date"`                              // Abgabetag als Text für ältere Clients, mit IPA-Zeitraum gleich EndDate
+	StartDate              Date            `json:"startDate,omitzero" bson:"startDate,omitempty"` // Erster Tag der IPA
+	EndDate                Date            `json:"endDate,omitzero" bson:"endDate,omitempty"`     // Abgabetag der IPA
+	Milestones             []Milestone     `json:"milestones" bson:"milestones,omitempty"`
+	PasswordHash           string          `json:"-" bson:"passwordHash"`                                // Never expose password hash in JSON
+	PasswordChangeRequired bool            `json:"passwordChangeRequired" bson:"passwordChangeRequired"` // Set for generated one-time passwords
+	Archived               bool            `json:"archived" bson:"archived"`
+	CatalogueVersion       string          `json:"catalogueVersion" bson:"catalogueVersion"` // Version des Kriterienkatalogs bei der Erstellung
+	Criteria               []Criterion     `json:"criteria" bson:"criteria"`
+	CriteriaRevision       int             `json:"-" bson:"criteriaRevision,omitempty"`          // Zählt die Änderungen an den Kriterien, siehe store.ChangeStatus
+	Journal                []JournalEntry  `json:"journal" bson:"journal,omitempty"`             // Arbeitsjournal, nach Datum sortiert
+	Tasks                  []Task          `json:"tasks" bson:"tasks,omitempty"`                 // Zeitplan
+	Evidence               []Evidence      `json:"evidence" bson:"evidence,omitempty"`           // Belege zu den Anforderungen der Kriterien
+	Comments               []Comment       `json:"comments" bson:"comments,omitempty"`           // Diskussionen zu den Kriterien
+	NoteRevisions          []NoteRevision  `json:"noteRevisions" bson:"noteRevisions,omitempty"` // Frühere Fassungen der Notizen
+	Status                 ProjectStatus   `json:"status" bson:"status,omitempty"`               // Leer bei Projekten aus älteren Versionen, siehe CurrentStatus
+	StatusHistory          []StatusChange  `json:"statusHistory" bson:"statusHistory,omitempty"`
+	GradeSnapshots         []GradeSnapshot `json:"gradeSnapshots" bson:"gradeSnapshots,omitempty"` // Verkettete, signierte Bewertungen, eine pro Übergang zu "graded"
+}
+
+// CurrentStatus liefert den Status des Projekts. Projekte ohne Status sind in Bearbeitung.
+func (d MongoIpaProject) CurrentStatus() ProjectStatus {
+	if d.Status == "" {
+		return StatusInProgress
+	}
+	return d.Status
 }
 
 func (d MongoIpaProject) Map() IpaProject {
 	return IpaProject{
-		ID:        common.FormatProjectID(d.ID),
-		Firstname: d.Firstname,
-		Lastname:  d.Lastname,
-		Topic:     d.Topic,
-		Date:      d.Date,
-		Criteria:  d.Criteria,
+		ID:                     d.ID,
+		Firstname:              d.Firstname,
+		Lastname:               d.Lastname,
+		Topic:                  d.Topic,
+		Date:                   d.Date,
+		StartDate:              d.StartDate,
+		EndDate:                d.EndDate,
+		Milestones:             d.Milestones,
+		Archived:               d.Archived,
+		Criteria:               d.Criteria,
+		PasswordChangeRequired: d.PasswordChangeRequired,
+		Status:                 d.CurrentStatus(),
 	}
 }
 
 // DTO
 type IpaProject struct {
-	ID        string      `json:"id"` // ^[A-Z]{2}\d{2}$
-	Firstname string      `json:"firstname"`
-	Lastname  string      `json:"lastname"`
-	Topic     string      `json:"topic"`
-	Date      string      `json:"date"`
-	Password  string      `json:"password,omitempty"` // Only used for create/login, never returned
-	Criteria  []Criterion `json:"criteria"`
+	ID                     string        `json:"id"`
+	Firstname              string        `json:"firstname"`
+	Lastname               string        `json:"lastname"`
+	Topic                  string        `json:"topic"`
+	Date                   string        `json:"date"`
+	StartDate              Date          `json:"startDate,omitzero"`
+	EndDate                Date          `json:"endDate,omitzero"`
+	Milestones             []Milestone   `json:"milestones"`
+	Password               string        `json:"password,omitempty"` // Only used for create/login, never returned
+	Archived               bool          `json:"archived"`
+	Criteria               []Criterion   `json:"criteria"`
+	PasswordChangeRequired bool          `json:"passwordChangeRequired"`
+	Status                 ProjectStatus `json:"status,omitempty"` // Nur in Antworten, Änderungen über den Status-Endpunkt
 }
 
-func (d IpaProject) Map() (MongoIpaProject, error) {
-	id, err := common.ParseProjectID(d.ID)
+func (d IpaProject) Map() MongoIpaProject {
 	return MongoIpaProject{
-		ID:        id,
-		Firstname: d.Firstname,
-		Lastname:  d.Lastname,
-		Topic:     d.Topic,
-		Date:      d.Date,
-		Criteria:  d.Criteria,
-	}, err
+		ID:         d.ID,
+		Firstname:  d.Firstname,
+		Lastname:   d.Lastname,
+		Topic:      d.Topic,
+		Date:       d.Date,
+		StartDate:  d.StartDate,
+		EndDate:    d.EndDate,
+		Milestones: d.Milestones,
+		Criteria:   d.Criteria,
+	}
 }
 
 func (d IpaProject) MapWithoutId() MongoIpaProject {
 	return MongoIpaProject{
-		Firstname: d.Firstname,
-		Lastname:  d.Lastname,
-		Topic:     d.Topic,
-		Date:      d.Date,
-		Criteria:  d.Criteria,
+		Firstname:  d.Firstname,
+		Lastname:   d.Lastname,
+		Topic:      d.Topic,
+		Date:       d.Date,
+		StartDate:  d.StartDate,
+		EndDate:    d.EndDate,
+		Milestones: d.Milestones,
+		Criteria:   d.Criteria,
 	}
 }
 
@@ -70,7 +110,8 @@ type Criterion struct {
 	Requirements  []string                `json:"requirements"`
 	Checked       []int                   `json:"checked"`
 	QualityLevels map[string]QualityLevel `json:"qualityLevels"`
-	Notes         string                  `json:"notes"`
+	Notes         string                  `json:"notes"`                        // Markdown
+	NotesHTML     string                  `json:"notesHtml,omitempty" bson:"-"` // Bereinigtes HTML der Notizen, nur in Antworten
 }
 
 type QualityLevel struct {
@@ -81,8 +122,9 @@ type QualityLevel struct {
 
 // GradeResult enthält die berechneten Noten und Gütestufen.
 type GradeResult struct {
-	Part1 GradeDetails `json:"part1"`
-	Part2 GradeDetails `json:"part2"`
+	Part1       GradeDetails `json:"part1"`
+	Part2       GradeDetails `json:"part2"`
+	Provisional bool         `json:"provisional"` // true solange die Kriterienauswahl die Auswahlregeln verletzt
 }
 
 // GradeDetails enthält die Grade und den Durchschnitt für einen Teil.
@@ -92,6 +134,504 @@ type GradeDetails struct {
 	CriterionGrades     []CriterionGrade `json:"criterionGrades"`
 }
 
+// MilestoneKind ist die Art eines Meilensteins.
+type MilestoneKind string
+
+const (
+	MilestoneKickoff     MilestoneKind = "kickoff"      // Start der IPA mit dem Fachvorgesetzten
+	MilestoneExpertVisit MilestoneKind = "expert_visit" // Besuch der Expertinnen und Experten
+	MilestoneHandIn      MilestoneKind = "hand_in"      // Abgabe der Dokumentation
+	MilestoneCustom      MilestoneKind = "custom"       // Eigener Termin, Titel erforderlich
+)
+
+// Milestone ist ein Termin im IPA-Zeitraum eines Projekts.
+type Milestone struct {
+	Kind  MilestoneKind `json:"kind" bson:"kind"`
+	Title string        `json:"title" bson:"title"`
+	Date  Date          `json:"date" bson:"date"`
+	Done  bool          `json:"done" bson:"done"`
+}
+
+// Timeline zeigt den Stand eines Projekts im IPA-Zeitraum.
+type Timeline struct {
+	StartDate         Date              `json:"startDate,omitzero"`
+	EndDate           Date              `json:"endDate,omitzero"`
+	Today             Date              `json:"today"`
+	Status            string            `json:"status"`            // unscheduled, upcoming, running oder finished
+	DaysTotal         int               `json:"daysTotal"`         // Kalendertage von Start bis Abgabe, beide eingeschlossen
+	DaysRemaining     int               `json:"daysRemaining"`     // Kalendertage bis zur Abgabe, heute und der Abgabetag eingeschlossen
+	WorkdaysRemaining int               `json:"workdaysRemaining"` // Davon Montag bis Freitag
+	Milestones        []MilestoneStatus `json:"milestones"`
+}
+
+// TimelineInput ersetzt den IPA-Zeitraum und die Meilensteine eines Projekts gemeinsam, damit
+// der Zeitraum mitsamt den Meilensteinen verschoben werden kann.
+type TimelineInput struct {
+	StartDate  Date        `json:"startDate,omitzero"`
+	EndDate    Date        `json:"endDate,omitzero"`
+	Milestones []Milestone `json:"milestones"`
+}
+
+// MilestoneStatus ist ein Meilenstein mit seinem Stand am heutigen Tag.
+type MilestoneStatus struct {
+	Milestone
+	Status        string `json:"status"`        // done, due, upcoming oder overdue
+	DaysRemaining int    `json:"daysRemaining"` // Tage bis zum Meilenstein, negativ wenn er vorbei ist
+}
+
+// JournalEntry ist ein Eintrag im Arbeitsjournal eines Projekts. Pro Tag sind mehrere Einträge möglich.
+type JournalEntry struct {
+	ID           string    `json:"id" bson:"id"`
+	Date         Date      `json:"date" bson:"date"`
+	Activities   string    `json:"activities" bson:"activities"` // Ausgeführte Arbeiten
+	Hours        float64   `json:"hours" bson:"hours"`           // Aufgewendete Stunden
+	Problems     string    `json:"problems" bson:"problems"`
+	HelpReceived string    `json:"helpReceived" bson:"helpReceived"` // Erhaltene Hilfe, z.B. von der Fachperson
+	NextSteps    string    `json:"nextSteps" bson:"nextSteps"`
+	TaskID       string    `json:"taskId" bson:"taskId,omitempty"` // Aufgabe im Zeitplan, leer wenn keiner zugeordnet
+	CreatedAt    time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Journal ist das Arbeitsjournal eines Projekts mit den Stunden pro Tag im Vergleich zur Planung.
+type Journal struct {
+	Entries      []JournalEntry `json:"entries"`
+	Days         []JournalDay   `json:"days"`
+	TotalHours   float64        `json:"totalHours"`
+	PlannedHours float64        `json:"plannedHours"`
+}
+
+// JournalDay enthält die Stunden eines Tages. Geplant sind die Arbeitstage im IPA-Zeitraum.
+type JournalDay struct {
+	Date         Date    `json:"date"`
+	Hours        float64 `json:"hours"`
+	PlannedHours float64 `json:"plannedHours"`
+	Difference   float64 `json:"difference"` // Hours - PlannedHours, negativ bei weniger Stunden als geplant
+}
+
+// Task ist eine Aufgabe im Zeitplan eines Projekts. Die Ist-Stunden stammen aus den
+// Journaleinträgen, die der Aufgabe zugeordnet sind.
+type Task struct {
+	ID           string  `json:"id" bson:"id"`
+	Title        string  `json:"title" bson:"title"`
+	Start        Date    `json:"start" bson:"start"`               // Geplanter Beginn
+	DurationDays int     `json:"durationDays" bson:"durationDays"` // Geplante Dauer in Kalendertagen, ab Start gezählt
+	PlannedHours float64 `json:"plannedHours" bson:"plannedHours"`
+}
+
+// Plan ist der Zeitplan eines Projekts im Vergleich zum Journal, aufbereitet für ein Gantt-Diagramm.
+type Plan struct {
+	Start           Date       `json:"start"` // Erster Tag mit geplanten oder erfassten Stunden
+	End             Date       `json:"end"`   // Letzter Tag mit geplanten oder erfassten Stunden
+	Tasks           []PlanTask `json:"tasks"`
+	Days            []PlanDay  `json:"days"`
+	PlannedHours    float64    `json:"plannedHours"`
+	ActualHours     float64    `json:"actualHours"`
+	Variance        float64    `json:"variance"`        // ActualHours - PlannedHours
+	UnassignedHours float64    `json:"unassignedHours"` // Stunden im Journal ohne Aufgabe
+}
+
+// PlanTask ist eine Aufgabe mit ihrem geplanten Ende und den erfassten Stunden.
+type PlanTask struct {
+	Task
+	End         Date    `json:"end"` // Letzter geplanter Tag
+	ActualHours float64 `json:"actualHours"`
+	Variance    float64 `json:"variance"`    // ActualHours - PlannedHours, positiv bei Mehraufwand
+	ActualStart Date    `json:"actualStart"` // Erster Tag mit Stunden im Journal, leer ohne Stunden
+	ActualEnd   Date    `json:"actualEnd"`   // Letzter Tag mit Stunden im Journal, leer ohne Stunden
+}
+
+// PlanDay enthält die geplanten und die erfassten Stunden eines Tages über alle Aufgaben.
+type PlanDay struct {
+	Date         Date    `json:"date"`
+	PlannedHours float64 `json:"plannedHours"`
+	ActualHours  float64 `json:"actualHours"` // Alle Journaleinträge des Tages, auch ohne Aufgabe
+	Variance     float64 `json:"variance"`
+}
+
+// EvidenceKind ist die Art eines Belegs.
+type EvidenceKind string
+
+const (
+	EvidenceFile EvidenceKind = "file" // Hochgeladene Datei
+	EvidenceLink EvidenceKind = "link" // Verweis auf eine Seite eines Dokuments
+)
+
+// Evidence belegt, dass eine Anforderung eines Kriteriums erfüllt ist. Je nach Art sind die
+// Angaben zur Datei oder zum Verweis gesetzt.
+type Evidence struct {
+	ID          string       `json:"id" bson:"id"`
+	CriterionID string       `json:"criterionId" bson:"criterionId"`
+	Requirement int          `json:"requirement" bson:"requirement"` // Index in Criterion.Requirements
+	Kind        EvidenceKind `json:"kind" bson:"kind"`
+	Title       string       `json:"title" bson:"title"`
+	FileName    string       `json:"fileName,omitempty" bson:"fileName,omitempty"`
+	ContentType string       `json:"contentType,omitempty" bson:"contentType,omitempty"` // Aus dem Inhalt erkannt, nicht vom Browser übernommen
+	Size        int64        `json:"size,omitempty" bson:"size,omitempty"`               // Bytes
+	SHA256      string       `json:"sha256,omitempty" bson:"sha256,omitempty"`           // Prüfsumme des Inhalts, hexadezimal
+	Document    string       `json:"document,omitempty" bson:"document,omitempty"`       // z.B. "IPA-Bericht"
+	Page        int          `json:"page,omitempty" bson:"page,omitempty"`               // Seite im Dokument, 0 ohne Angabe
+	URL         string       `json:"url,omitempty" bson:"url,omitempty"`
+	CreatedAt   time.Time    `json:"createdAt" bson:"createdAt"`
+}
+
+// EvidenceReport listet die Belege pro Kriterium und Anforderung eines Projekts.
+type EvidenceReport struct {
+	Criteria               []CriterionEvidence `json:"criteria"`
+	CheckedWithoutEvidence int                 `json:"checkedWithoutEvidence"` // Erfüllte Anforderungen ohne Beleg
+}
+
+// CriterionEvidence enthält die Belege zu den Anforderungen eines Kriteriums.
+type CriterionEvidence struct {
+	CriterionID  string                `json:"criterionId"`
+	Title        string                `json:"title"`
+	NotesHTML    string                `json:"notesHtml,omitempty"` // Bereinigtes HTML der Notizen
+	Requirements []RequirementEvidence `json:"requirements"`
+}
+
+// RequirementEvidence enthält die Belege zu einer Anforderung.
+type RequirementEvidence struct {
+	Index    int        `json:"index"`
+	Text     string     `json:"text"`
+	Checked  bool       `json:"checked"` // In Criterion.Checked als erfüllt markiert
+	Evidence []Evidence `json:"evidence"`
+}
+
+// Role ist die Rolle eines Benutzers in einem Projekt.
+type Role string
+
+const (
+	RoleCandidate Role = "candidate" // Kandidatin oder Kandidat, angemeldet mit dem Projektpasswort
+	RoleExpert    Role = "expert"    // Expertin oder Experte, mit einem vom Admin ausgestellten Token
+)
+
+// ProjectStatus ist der Stand eines Projekts in der Bewertung.
+type ProjectStatus string
+
+const (
+	StatusInProgress  ProjectStatus = "in_progress"  // Die Kandidatin oder der Kandidat arbeitet am Projekt
+	StatusSubmitted   ProjectStatus = "submitted"    // Abgegeben, wartet auf die Bewertung
+	StatusUnderReview ProjectStatus = "under_review" // Wird von den Expertinnen und Experten bewertet
+	StatusGraded      ProjectStatus = "graded"       // Bewertet, die Bewertung ist signiert
+	StatusClosed      ProjectStatus = "closed"       // Abgeschlossen
+)
+
+// StatusChange ist ein Übergang im Status eines Projekts.
+type StatusChange struct {
+	From ProjectStatus `json:"from" bson:"from"`
+	To   ProjectStatus `json:"to" bson:"to"`
+	By   string        `json:"by" bson:"by"` // Name des Benutzers
+	Role Role          `json:"role" bson:"role"`
+	At   time.Time     `json:"at" bson:"at"`
+}
+
+// StatusInput ist der neue Status eines Projekts.
+type StatusInput struct {
+	Status ProjectStatus `json:"status" binding:"required"`
+}
+
+// Workflow ist der Status eines Projekts aus Sicht des anfragenden Benutzers.
+type Workflow struct {
+	Status      ProjectStatus   `json:"status"`
+	Editable    bool            `json:"editable"`    // Der Benutzer darf die Kriterien ändern
+	Transitions []ProjectStatus `json:"transitions"` // Status, in die der Benutzer das Projekt versetzen darf
+	History     []StatusChange  `json:"history"`
+	Snapshots   []GradeSnapshot `json:"snapshots"`
+}
+
+// GradeSnapshot hält die Bewertung eines Projekts beim Übergang zu "graded" fest. Die
+// Momentaufnahmen eines Projekts bilden eine Kette: Hash deckt alle übrigen Felder ab, auch den
+// Hash der vorherigen Momentaufnahme, und die Signatur deckt Hash ab.
+type GradeSnapshot struct {
+	ID               string              `json:"id" bson:"id"`
+	ProjectID        string              `json:"projectId,omitempty" bson:"projectId,omitempty"` // Bindet die Momentaufnahme an das Projekt, fehlt bei älteren Momentaufnahmen
+	Sequence         int                 `json:"sequence" bson:"sequence"`                       // Position in der Kette, beginnt bei 1
+	CreatedAt        time.Time           `json:"createdAt" bson:"createdAt"`
+	GradedBy         string              `json:"gradedBy" bson:"gradedBy"`
+	CatalogueVersion string              `json:"catalogueVersion" bson:"catalogueVersion"`
+	Scheme           string              `json:"scheme" bson:"scheme"` // Berechnungsverfahren der Note, siehe grade.Scheme
+	Criteria         []SnapshotCriterion `json:"criteria" bson:"criteria"`
+	Grade            GradeResult         `json:"grade" bson:"grade"`
+	PreviousHash     string              `json:"previousHash" bson:"previousHash"` // Leer bei der ersten Momentaufnahme
+	Hash             string              `json:"hash" bson:"hash"`                 // SHA-256, hexadezimal
+	Signature        string              `json:"signature" bson:"signature"`       // HMAC-SHA256 über Hash, hexadezimal
+}
+
+// SnapshotCriterion ist der Stand eines Kriteriums in einem GradeSnapshot. Aus den Feldern lässt
+// sich die Gütestufe des Kriteriums erneut berechnen.
+type SnapshotCriterion struct {
+	ID            string                  `json:"id" bson:"id"`
+	Requirements  int                     `json:"requirements" bson:"requirements"` // Anzahl Anforderungen
+	Checked       []int                   `json:"checked" bson:"checked"`
+	QualityLevels map[string]QualityLevel `json:"qualityLevels" bson:"qualityLevels"`
+}
+
+// SnapshotProblem ist eine Unstimmigkeit, die bei der Prüfung einer Momentaufnahme auffällt.
+type SnapshotProblem string
+
+const (
+	SnapshotContentModified  SnapshotProblem = "content_modified"  // Der Inhalt passt nicht zum Hash
+	SnapshotSignatureInvalid SnapshotProblem = "signature_invalid" // Die Signatur passt nicht zum Hash
+	SnapshotChainBroken      SnapshotProblem = "chain_broken"      // Eine Momentaufnahme davor wurde entfernt, eingefügt oder verändert
+	SnapshotGradeMismatch    SnapshotProblem = "grade_mismatch"    // Die Note passt nicht zu den festgehaltenen Kriterien
+	SnapshotProjectMismatch  SnapshotProblem = "project_mismatch"  // Die Momentaufnahme gehört zu einem anderen Projekt
+)
+
+// SnapshotCheck ist das Ergebnis der Prüfung einer Momentaufnahme.
+type SnapshotCheck struct {
+	ID       string            `json:"id"`
+	Sequence int               `json:"sequence"`
+	Valid    bool              `json:"valid"`
+	Problems []SnapshotProblem `json:"problems"`
+}
+
+// SnapshotVerification ist das Ergebnis der Prüfung aller Momentaufnahmen eines Projekts.
+type SnapshotVerification struct {
+	ProjectID string          `json:"projectId"`
+	Valid     bool            `json:"valid"`
+	Missing   int             `json:"missing"` // Übergänge zu "graded" im Verlauf ohne Momentaufnahme
+	Snapshots []SnapshotCheck `json:"snapshots"`
+}
+
+// User ist ein angemeldeter Benutzer eines Projekts.
+type User struct {
+	Role Role
+	Name string
+}
+
+// Key identifiziert den Benutzer in einem Projekt, z.B. beim Lesestatus der Kommentare.
+func (u User) Key() string {
+	if u.Role == RoleCandidate {
+		return string(RoleCandidate) // Ein Projekt hat genau eine Kandidatin oder einen Kandidaten
+	}
+	return string(u.Role) + ":" + u.Name
+}
+
+// Comment ist ein Kommentar in einer Diskussion zu einem Kriterium. Der erste Kommentar
+// eröffnet die Diskussion und trägt ihren Status.
+type Comment struct {
+	ID          string    `json:"id" bson:"id"`
+	CriterionID string    `json:"criterionId" bson:"criterionId"`
+	ThreadID    string    `json:"threadId" bson:"threadId"` // ID des ersten Kommentars der Diskussion
+	Author      string    `json:"author" bson:"author"`
+	AuthorKey   string    `json:"-" bson:"authorKey"` // User.Key des Autors
+	Role        Role      `json:"role" bson:"role"`
+	Text        string    `json:"text" bson:"text"`
+	Resolved    bool      `json:"resolved" bson:"resolved"` // Nur beim ersten Kommentar einer Diskussion
+	Deleted     bool      `json:"deleted" bson:"deleted"`   // Der Text ist entfernt, die Antworten bleiben erhalten
+	CreatedAt   time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt" bson:"updatedAt"`
+	ReadBy      []string  `json:"-" bson:"readBy"` // User.Key der Benutzer, die den Kommentar gelesen haben
+}
+
+// CommentInput ist ein neuer oder geänderter Kommentar.
+type CommentInput struct {
+	Text     string `json:"text"`
+	ThreadID string `json:"threadId"` // Leer für eine neue Diskussion
+}
+
+// ThreadStatusInput setzt den Status einer Diskussion.
+type ThreadStatusInput struct {
+	Resolved bool `json:"resolved"`
+}
+
+// CommentView ist ein Kommentar aus Sicht des anfragenden Benutzers.
+type CommentView struct {
+	Comment
+	Own  bool `json:"own"`  // Vom anfragenden Benutzer verfasst
+	Read bool `json:"read"` // Vom anfragenden Benutzer gelesen
+}
+
+// CommentThread ist eine Diskussion zu einem Kriterium.
+type CommentThread struct {
+	ID          string        `json:"id"`
+	CriterionID string        `json:"criterionId"`
+	Resolved    bool          `json:"resolved"`
+	Unread      int           `json:"unread"`
+	Comments    []CommentView `json:"comments"` // Nach Erstellung sortiert
+}
+
+// CommentSummary zählt die offenen Diskussionen und ungelesenen Kommentare eines Benutzers.
+type CommentSummary struct {
+	Unread     int                 `json:"unread"`
+	Unresolved int                 `json:"unresolved"`
+	Criteria   []CriterionComments `json:"criteria"` // Nur Kriterien mit Kommentaren
+}
+
+// CriterionComments zählt die Diskussionen zu einem Kriterium.
+type CriterionComments struct {
+	CriterionID string `json:"criterionId"`
+	Threads     int    `json:"threads"`
+	Unresolved  int    `json:"unresolved"`
+	Unread      int    `json:"unread"`
+}
+
+// SelectionRule beschreibt, wie viele optionale Kriterien aus den angegebenen Kategorien gewählt werden müssen.
+type SelectionRule struct {
+	Description string   `json:"description"`
+	Categories  []string `json:"categories"`
+	Min         int      `json:"min"`
+	Max         int      `json:"max,omitempty"` // 0 = keine Obergrenze
+}
+
+// SelectionViolation beschreibt eine verletzte Auswahlregel.
+type SelectionViolation struct {
+	Description string   `json:"description"`
+	Categories  []string `json:"categories"`
+	Min         int      `json:"min"`
+	Max         int      `json:"max,omitempty"`
+	Count       int      `json:"count"`
+}
+
+// SelectionResult enthält das Ergebnis der Prüfung der Kriterienauswahl eines Projekts.
+type SelectionResult struct {
+	Valid      bool                 `json:"valid"`
+	Violations []SelectionViolation `json:"violations"`
+}
+
+// SearchResult enthält die Treffer einer Suche im Kriterienkatalog, der beste Treffer zuerst.
+type SearchResult struct {
+	Query string      `json:"query"`
+	Hits  []SearchHit `json:"hits"`
+}
+
+// SearchHit ist ein Kriterium, das alle Suchbegriffe enthält.
+type SearchHit struct {
+	ID       string          `json:"id"`
+	Title    string          `json:"title"`
+	Score    float64         `json:"score"`
+	Snippets []SearchSnippet `json:"snippets"`
+}
+
+// SearchSnippet ist ein Textausschnitt mit Treffern. Text ist HTML-escaped, die gefundenen Wörter
+// sind mit <mark> ausgezeichnet.
+type SearchSnippet struct {
+	Field string `json:"field"`           // title, question oder requirements
+	Index *int   `json:"index,omitempty"` // Index der Anforderung bei Field requirements
+	Text  string `json:"text"`
+}
+
+// ProjectSummary ist die Kurzansicht eines Projekts in der Projektliste der Administration.
+type ProjectSummary struct {
+	ID            string  `json:"id"`
+	Firstname     string  `json:"firstname"`
+	Lastname      string  `json:"lastname"`
+	Topic         string  `json:"topic"`
+	Date          string  `json:"date"`
+	Archived      bool    `json:"archived"`
+	CriteriaCount int     `json:"criteriaCount"`
+	Part1Grade    float64 `json:"part1Grade"`
+	Part2Grade    float64 `json:"part2Grade"`
+	Grade         float64 `json:"grade"` // Mittelwert aus Teil 1 und Teil 2
+}
+
+// ProjectPage ist eine Seite der Projektliste.
+type ProjectPage struct {
+	Items    []ProjectSummary `json:"items"`
+	Total    int              `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"pageSize"`
+}
+
+// CriterionChange ist die Änderung an einem Kriterium durch den Import einer Tabelle.
+type CriterionChange struct {
+	CriterionID        string `json:"criterionId"`
+	Title              string `json:"title"`
+	Row                int    `json:"row"` // Zeile in der Tabelle, die Kopfzeile zählt mit
+	CheckedBefore      []int  `json:"checkedBefore"`
+	CheckedAfter       []int  `json:"checkedAfter"`
+	NotesBefore        string `json:"notesBefore"`
+	NotesAfter         string `json:"notesAfter"`
+	QualityLevelBefore int    `json:"qualityLevelBefore"`
+	QualityLevelAfter  int    `json:"qualityLevelAfter"`
+}
+
+// SpreadsheetImport beschreibt, was der Import einer Tabelle ändert oder geändert hat.
+type SpreadsheetImport struct {
+	DryRun           bool              `json:"dryRun"` // true, wenn die Änderungen nur angezeigt und nicht gespeichert wurden
+	Changes          []CriterionChange `json:"changes"`
+	Unchanged        int               `json:"unchanged"` // Zeilen ohne Änderung
+	Part1GradeBefore float64           `json:"part1GradeBefore"`
+	Part1GradeAfter  float64           `json:"part1GradeAfter"`
+	Part2GradeBefore float64           `json:"part2GradeBefore"`
+	Part2GradeAfter  float64           `json:"part2GradeAfter"`
+}
+
+// CohortStatistics fasst die Bewertung einer Gruppe von Projekten zusammen, z.B. einer Klasse.
+type CohortStatistics struct {
+	Projects     int                     `json:"projects"`
+	Part1        GradeDistribution       `json:"part1"`
+	Part2        GradeDistribution       `json:"part2"`
+	Criteria     []CriterionStatistics   `json:"criteria"`     // Nach ID sortiert
+	Requirements []RequirementStatistics `json:"requirements"` // Die am häufigsten nicht erfüllten zuerst
+}
+
+// GradeDistribution ist die Verteilung der Noten eines Teils. Projekte ohne Kriterien im Teil
+// sind nicht enthalten.
+type GradeDistribution struct {
+	Projects int          `json:"projects"`
+	Average  float64      `json:"average"`
+	Median   float64      `json:"median"`
+	Min      float64      `json:"min"`
+	Max      float64      `json:"max"`
+	Counts   []GradeCount `json:"counts"` // Von 1 bis 6 in halben Noten
+}
+
+// GradeCount ist die Anzahl Projekte, deren Note auf die halbe Note Grade gerundet wird.
+type GradeCount struct {
+	Grade float64 `json:"grade"`
+	Count int     `json:"count"`
+}
+
+// CriterionStatistics beschreibt, wie oft ein Kriterium gewählt und wie es bewertet wurde.
+type CriterionStatistics struct {
+	ID                  string  `json:"id"`
+	Title               string  `json:"title"`
+	Part                int     `json:"part"`          // 1 oder 2
+	Selected            int     `json:"selected"`      // Anzahl Projekte mit dem Kriterium
+	SelectionRate       float64 `json:"selectionRate"` // Anteil der Projekte, 0 bis 1
+	AverageQualityLevel float64 `json:"averageQualityLevel"`
+	QualityLevels       [4]int  `json:"qualityLevels"` // Anzahl Projekte je Gütestufe 0 bis 3
+}
+
+// RequirementStatistics beschreibt, wie oft eine Anforderung nicht erfüllt wurde.
+type RequirementStatistics struct {
+	CriterionID   string  `json:"criterionId"`
+	Index         int     `json:"index"`
+	Text          string  `json:"text"`
+	Unchecked     int     `json:"unchecked"`     // Anzahl Projekte, in denen die Anforderung nicht erfüllt ist
+	UncheckedRate float64 `json:"uncheckedRate"` // Anteil der Projekte mit dem Kriterium, 0 bis 1
+}
+
+// ProjectCredentials enthält die Zugangsdaten eines importierten Projekts für das Zugangsdatenblatt.
+type ProjectCredentials struct {
+	ID        string `json:"id"`
+	Firstname string `json:"firstname"`
+	Lastname  string `json:"lastname"`
+	Topic     string `json:"topic"`
+	Date      string `json:"date"`
+	Password  string `json:"password"` // Einmal-Passwort, muss beim ersten Login geändert werden
+}
+
+// ExpertTokenRequest is used to issue a token for an expert of a project
+type ExpertTokenRequest struct {
+	Name string `json:"name" binding:"required"` // Author of the expert's comments
+}
+
+// ExpertToken is the token of an expert, used as Bearer token for the routes of the project
+type ExpertToken struct {
+	ProjectID string    `json:"projectId"`
+	Name      string    `json:"name"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ChangePasswordRequest is used to replace the current (e.g. one-time) password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required"`
+}
+
 // LoginRequest is used for authenticating to an IPA project
 type LoginRequest struct {
 	ID       string `json:"id" binding:"required"`
@@ -124,3 +664,22 @@ func SetQualityLevelDefaultValuesIfMissing(ql *QualityLevel) {
 		ql.RequiredIndexes = make([]int, 0)
 	}
 }
+
+// NoteRevision ist eine frühere Fassung der Notizen zu einem Kriterium. Sie entsteht, wenn die
+// Notizen überschrieben werden.
+type NoteRevision struct {
+	ID          string    `json:"id" bson:"id"`
+	CriterionID string    `json:"criterionId" bson:"criterionId"`
+	Notes       string    `json:"notes" bson:"notes"`
+	ReplacedAt  time.Time `json:"replacedAt" bson:"replacedAt"`
+	ReplacedBy  string    `json:"replacedBy" bson:"replacedBy"` // Name des Benutzers, der die Notizen überschrieben hat
+	Role        Role      `json:"role" bson:"role"`
+}
+
+// CriterionNotes sind die Notizen zu einem Kriterium mit ihren früheren Fassungen.
+type CriterionNotes struct {
+	CriterionID string         `json:"criterionId"`
+	Notes       string         `json:"notes"`
+	NotesHTML   string         `json:"notesHtml"`
+	Revisions   []NoteRevision `json:"revisions"` // Die neueste zuerst
+}
@@ -0,0 +1,106 @@
+// Package notes prüft die Notizen zu den Kriterien eines Projekts, stellt sie als bereinigtes
+// HTML dar und verwaltet ihre früheren Fassungen.
+package notes
+
+import (
+	"bytes"
+	"errors"
+	"html"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// MaxLength begrenzt die Länge der Notizen zu einem Kriterium in Zeichen.
+const MaxLength = 20000
+
+var ErrTooLong = errors.New("notes are too long")
+
+var (
+	markdown = goldmark.New(goldmark.WithExtensions(extension.Table, extension.Strikethrough, extension.Linkify))
+	// policy entfernt alles, was nicht zu Markdown gehört, z.B. Skripte, Event-Handler und
+	// javascript:-Links. Rohes HTML verwirft goldmark bereits selbst.
+	policy = bluemonday.UGCPolicy()
+)
+
+// Validate prüft die Länge der Notizen.
+func Validate(text string) error {
+	if utf8.RuneCountInString(text) > MaxLength {
+		return ErrTooLong
+	}
+	return nil
+}
+
+// Render wandelt Notizen in Markdown in bereinigtes HTML um.
+func Render(text string) string {
+	if text == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(text), &buf); err != nil {
+		// Bei einem Fehler des Parsers erscheint der Text wenigstens unformatiert
+		return "<p>" + html.EscapeString(text) + "</p>"
+	}
+	return policy.Sanitize(buf.String())
+}
+
+// RenderCriteria liefert eine Kopie der Kriterien, in der NotesHTML gesetzt ist.
+func RenderCriteria(criteria []models.Criterion) []models.Criterion {
+	if criteria == nil {
+		return nil
+	}
+	rendered := slices.Clone(criteria)
+	for i := range rendered {
+		rendered[i].NotesHTML = Render(rendered[i].Notes)
+	}
+	return rendered
+}
+
+// Revision liefert die bisherige Fassung der Notizen zum Kriterium criterionID, wenn text sie
+// ersetzt. Leere Notizen ergeben keine Fassung.
+func Revision(id string, project models.MongoIpaProject, criterionID string, text string, user models.User, now time.Time) (models.NoteRevision, bool) {
+	index := slices.IndexFunc(project.Criteria, func(criterion models.Criterion) bool {
+		return criterion.ID == criterionID
+	})
+	if index < 0 || project.Criteria[index].Notes == "" || project.Criteria[index].Notes == text {
+		return models.NoteRevision{}, false
+	}
+	return models.NoteRevision{
+		ID:          id,
+		CriterionID: criterionID,
+		Notes:       project.Criteria[index].Notes,
+		ReplacedAt:  now,
+		ReplacedBy:  user.Name,
+		Role:        user.Role,
+	}, true
+}
+
+// History liefert die früheren Fassungen der Notizen zum Kriterium criterionID, die neueste zuerst.
+func History(project models.MongoIpaProject, criterionID string) []models.NoteRevision {
+	revisions := make([]models.NoteRevision, 0)
+	for _, revision := range project.NoteRevisions {
+		if revision.CriterionID == criterionID {
+			revisions = append(revisions, revision)
+		}
+	}
+	slices.SortStableFunc(revisions, func(a, b models.NoteRevision) int {
+		return b.ReplacedAt.Compare(a.ReplacedAt)
+	})
+	return revisions
+}
+
+// Find liefert die Fassung mit der ID id zum Kriterium criterionID.
+func Find(project models.MongoIpaProject, criterionID string, id string) (models.NoteRevision, bool) {
+	index := slices.IndexFunc(project.NoteRevisions, func(revision models.NoteRevision) bool {
+		return revision.ID == id && revision.CriterionID == criterionID
+	})
+	if index < 0 {
+		return models.NoteRevision{}, false
+	}
+	return project.NoteRevisions[index], true
+}
@@ -0,0 +1,90 @@
+package notes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+func TestValidate(t *testing.T) {
+	if err := Validate(strings.Repeat("ä", MaxLength)); err != nil {
+		t.Errorf("Validate(max length) error = %v", err)
+	}
+	if err := Validate(strings.Repeat("a", MaxLength+1)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Validate(too long) error = %v, want %v", err, ErrTooLong)
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []string
+		notWant []string
+	}{
+		{"empty", "", nil, []string{"<p>"}},
+		{"markdown", "# Analyse\n\n- **Ziel** erreicht\n- ~~offen~~", []string{"<h1", "<li><strong>Ziel</strong> erreicht</li>", "<del>offen</del>"}, nil},
+		{"table", "| A | B |\n|---|---|\n| 1 | 2 |", []string{"<table>", "<td>2</td>"}, nil},
+		{"link", "[Bericht](https://example.org/bericht.pdf)", []string{`href="https://example.org/bericht.pdf"`, `rel="nofollow"`}, nil},
+		{"raw html", "<script>alert(1)</script><img src=x onerror=alert(1)>", nil, []string{"<script", "onerror", "alert(1)"}},
+		{"javascript link", "[klick](javascript:alert(1))", nil, []string{"javascript:"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.text)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render() = %q, want %q", got, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Render() = %q, must not contain %q", got, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderCriteriaCopies(t *testing.T) {
+	criteria := []models.Criterion{{ID: "A01", Notes: "*kursiv*"}}
+	rendered := RenderCriteria(criteria)
+	if rendered[0].NotesHTML != "<p><em>kursiv</em></p>\n" || criteria[0].NotesHTML != "" {
+		t.Errorf("RenderCriteria() = %q, original %q", rendered[0].NotesHTML, criteria[0].NotesHTML)
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	user := models.User{Role: models.RoleExpert, Name: "Eva Experte"}
+	now := time.Date(2026, 5, 6, 9, 0, 0, 0, time.UTC)
+	project := models.MongoIpaProject{Criteria: []models.Criterion{{ID: "A01", Notes: "alt"}, {ID: "A02"}}}
+
+	if _, ok := Revision("r1", project, "A01", "alt", user, now); ok {
+		t.Error("Revision() for unchanged notes")
+	}
+	if _, ok := Revision("r1", project, "A02", "neu", user, now); ok {
+		t.Error("Revision() for empty notes")
+	}
+	revision, ok := Revision("r1", project, "A01", "neu", user, now)
+	if !ok || revision.Notes != "alt" || revision.CriterionID != "A01" || revision.ReplacedBy != "Eva Experte" || revision.Role != models.RoleExpert {
+		t.Fatalf("Revision() = %+v, %v", revision, ok)
+	}
+
+	later := revision
+	later.ID, later.Notes, later.ReplacedAt = "r2", "neu", now.Add(time.Hour)
+	other := revision
+	other.ID, other.CriterionID = "r3", "A02"
+	project.NoteRevisions = []models.NoteRevision{revision, later, other}
+	if history := History(project, "A01"); len(history) != 2 || history[0].ID != "r2" || history[1].ID != "r1" {
+		t.Errorf("History() = %+v, want r2, r1", history)
+	}
+	if history := History(project, "A03"); history == nil || len(history) != 0 {
+		t.Errorf("History(A03) = %#v, want empty slice", history)
+	}
+	if _, ok := Find(project, "A01", "r3"); ok {
+		t.Error("Find() returned a revision of another criterion")
+	}
+}
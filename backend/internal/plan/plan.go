@@ -0,0 +1,129 @@
+// Package plan prüft die Aufgaben im Zeitplan eines Projekts und vergleicht die geplanten
+// Stunden mit den Stunden im Arbeitsjournal.
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/timeline"
+)
+
+// MaxDurationDays begrenzt die Dauer einer Aufgabe auf die Länge des IPA-Zeitraums.
+const MaxDurationDays = timeline.MaxPeriodDays
+
+var (
+	ErrTitleMissing  = errors.New("title is missing")
+	ErrStartMissing  = errors.New("start date is missing")
+	ErrDuration      = fmt.Errorf("duration must be between 1 and %d days", MaxDurationDays)
+	ErrHours         = errors.New("planned hours must be greater than 0 and fit into the duration")
+	ErrOutsidePeriod = errors.New("task is outside the IPA period")
+)
+
+// End liefert den letzten geplanten Tag einer Aufgabe.
+func End(task models.Task) models.Date {
+	return task.Start.AddDays(task.DurationDays - 1)
+}
+
+// Validate prüft eine neue oder geänderte Aufgabe des Projekts. Ist ein IPA-Zeitraum gesetzt,
+// muss die Aufgabe darin liegen.
+func Validate(task models.Task, project models.MongoIpaProject) error {
+	switch {
+	case task.Title == "":
+		return ErrTitleMissing
+	case task.Start.IsZero():
+		return ErrStartMissing
+	case task.DurationDays < 1 || task.DurationDays > MaxDurationDays:
+		return ErrDuration
+	case task.PlannedHours <= 0 || task.PlannedHours > float64(task.DurationDays*24):
+		return ErrHours
+	case !project.StartDate.IsZero() && (task.Start.Before(project.StartDate) || End(task).After(project.EndDate)):
+		return ErrOutsidePeriod
+	}
+	return nil
+}
+
+// Build vergleicht den Zeitplan eines Projekts mit dem Journal. Die geplanten Stunden einer
+// Aufgabe verteilen sich gleichmässig auf ihre Arbeitstage, enthält sie keinen, auf alle ihre
+// Tage. Journaleinträge ohne bekannte Aufgabe zählen nur zu den Stunden pro Tag.
+func Build(project models.MongoIpaProject) models.Plan {
+	plan := models.Plan{Tasks: make([]models.PlanTask, len(project.Tasks))}
+	days := make(map[models.Date]*models.PlanDay)
+	day := func(date models.Date) *models.PlanDay {
+		if days[date] == nil {
+			days[date] = &models.PlanDay{Date: date}
+		}
+		return days[date]
+	}
+
+	tasks := make(map[string]*models.PlanTask, len(project.Tasks))
+	for i, task := range project.Tasks {
+		plan.Tasks[i] = models.PlanTask{Task: task, End: End(task)}
+		tasks[task.ID] = &plan.Tasks[i]
+		dates := plannedDays(task)
+		for _, date := range dates {
+			day(date).PlannedHours += task.PlannedHours / float64(len(dates))
+		}
+		plan.PlannedHours += task.PlannedHours
+	}
+	for _, entry := range project.Journal {
+		day(entry.Date).ActualHours += entry.Hours
+		plan.ActualHours += entry.Hours
+		task := tasks[entry.TaskID]
+		if task == nil {
+			plan.UnassignedHours += entry.Hours
+			continue
+		}
+		task.ActualHours += entry.Hours
+		if task.ActualStart.IsZero() || entry.Date.Before(task.ActualStart) {
+			task.ActualStart = entry.Date
+		}
+		if entry.Date.After(task.ActualEnd) {
+			task.ActualEnd = entry.Date
+		}
+	}
+
+	for i := range plan.Tasks {
+		plan.Tasks[i].ActualHours = common.Round(plan.Tasks[i].ActualHours)
+		plan.Tasks[i].Variance = common.Round(plan.Tasks[i].ActualHours - plan.Tasks[i].PlannedHours)
+	}
+	slices.SortStableFunc(plan.Tasks, func(a, b models.PlanTask) int {
+		return a.Start.Compare(b.Start)
+	})
+	plan.Days = make([]models.PlanDay, 0, len(days))
+	for _, d := range days {
+		d.PlannedHours = common.Round(d.PlannedHours)
+		d.ActualHours = common.Round(d.ActualHours)
+		d.Variance = common.Round(d.ActualHours - d.PlannedHours)
+		plan.Days = append(plan.Days, *d)
+	}
+	slices.SortFunc(plan.Days, func(a, b models.PlanDay) int {
+		return a.Date.Compare(b.Date)
+	})
+	if len(plan.Days) > 0 {
+		plan.Start, plan.End = plan.Days[0].Date, plan.Days[len(plan.Days)-1].Date
+	}
+	plan.PlannedHours = common.Round(plan.PlannedHours)
+	plan.ActualHours = common.Round(plan.ActualHours)
+	plan.UnassignedHours = common.Round(plan.UnassignedHours)
+	plan.Variance = common.Round(plan.ActualHours - plan.PlannedHours)
+	return plan
+}
+
+// plannedDays liefert die Tage, auf die sich die geplanten Stunden einer Aufgabe verteilen.
+func plannedDays(task models.Task) []models.Date {
+	var all, workdays []models.Date
+	for date := task.Start; !date.After(End(task)); date = date.AddDays(1) {
+		all = append(all, date)
+		if date.IsWorkday() {
+			workdays = append(workdays, date)
+		}
+	}
+	if len(workdays) == 0 {
+		return all
+	}
+	return workdays
+}
@@ -0,0 +1,122 @@
+package plan
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+func date(t *testing.T, value string) models.Date {
+	t.Helper()
+	d, err := models.ParseDate(value)
+	if err != nil {
+		t.Fatalf("ParseDate(%q) error = %v", value, err)
+	}
+	return d
+}
+
+func TestValidate(t *testing.T) {
+	project := models.MongoIpaProject{StartDate: date(t, "2026-03-02"), EndDate: date(t, "2026-03-13")}
+	task := func(start string, days int, hours float64) models.Task {
+		return models.Task{Title: "Umsetzung", Start: date(t, start), DurationDays: days, PlannedHours: hours}
+	}
+	tests := []struct {
+		name string
+		task models.Task
+		want error
+	}{
+		{"valid", task("2026-03-02", 3, 24), nil},
+		{"last day", task("2026-03-13", 1, 8), nil},
+		{"title missing", models.Task{Start: date(t, "2026-03-02"), DurationDays: 1, PlannedHours: 8}, ErrTitleMissing},
+		{"start missing", models.Task{Title: "Umsetzung", DurationDays: 1, PlannedHours: 8}, ErrStartMissing},
+		{"no duration", task("2026-03-02", 0, 8), ErrDuration},
+		{"too long", task("2026-03-02", MaxDurationDays+1, 8), ErrDuration},
+		{"no hours", task("2026-03-02", 1, 0), ErrHours},
+		{"more hours than the day has", task("2026-03-02", 1, 25), ErrHours},
+		{"before period", task("2026-03-01", 2, 8), ErrOutsidePeriod},
+		{"ends after period", task("2026-03-12", 3, 8), ErrOutsidePeriod},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.task, project)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if err := Validate(task("2025-01-01", 200, 8), models.MongoIpaProject{}); !errors.Is(err, ErrDuration) {
+		t.Errorf("Validate() without period error = %v, want %v", err, ErrDuration)
+	}
+	if err := Validate(task("2025-01-01", 2, 8), models.MongoIpaProject{}); err != nil {
+		t.Errorf("Validate() without period error = %v, want nil", err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	project := models.MongoIpaProject{
+		Tasks: []models.Task{
+			{ID: "b", Title: "Umsetzung", Start: date(t, "2026-03-06"), DurationDays: 4, PlannedHours: 12}, // Freitag bis Montag
+			{ID: "a", Title: "Planung", Start: date(t, "2026-03-05"), DurationDays: 1, PlannedHours: 4},
+			{ID: "c", Title: "Wochenende", Start: date(t, "2026-03-07"), DurationDays: 2, PlannedHours: 3},
+		},
+		Journal: []models.JournalEntry{
+			{Date: date(t, "2026-03-05"), Hours: 5, TaskID: "a"},
+			{Date: date(t, "2026-03-09"), Hours: 4, TaskID: "b"},
+			{Date: date(t, "2026-03-06"), Hours: 8.5, TaskID: "b"},
+			{Date: date(t, "2026-03-10"), Hours: 1},                   // Ohne Aufgabe
+			{Date: date(t, "2026-03-10"), Hours: 0.5, TaskID: "gone"}, // Gelöschte Aufgabe
+		},
+	}
+	got := Build(project)
+
+	if got.Start != date(t, "2026-03-05") || got.End != date(t, "2026-03-10") {
+		t.Errorf("Build() period = %s to %s, want 2026-03-05 to 2026-03-10", got.Start, got.End)
+	}
+	if got.PlannedHours != 19 || got.ActualHours != 19 || got.Variance != 0 || got.UnassignedHours != 1.5 {
+		t.Errorf("Build() totals = %v planned, %v actual, %v variance, %v unassigned; want 19, 19, 0, 1.5",
+			got.PlannedHours, got.ActualHours, got.Variance, got.UnassignedHours)
+	}
+
+	wantTasks := []struct {
+		id                     string
+		end                    string
+		actual, variance       float64
+		actualStart, actualEnd string
+	}{
+		{"a", "2026-03-05", 5, 1, "2026-03-05", "2026-03-05"},
+		{"b", "2026-03-09", 12.5, 0.5, "2026-03-06", "2026-03-09"},
+		{"c", "2026-03-08", 0, -3, "", ""},
+	}
+	for i, want := range wantTasks {
+		task := got.Tasks[i]
+		if task.ID != want.id || task.End.String() != want.end || task.ActualHours != want.actual || task.Variance != want.variance ||
+			task.ActualStart.String() != want.actualStart || task.ActualEnd.String() != want.actualEnd {
+			t.Errorf("task %d = %+v, want %+v", i, task, want)
+		}
+	}
+
+	// Die Aufgabe b verteilt sich auf Freitag und Montag, die Aufgabe c auf das Wochenende
+	wantDays := []models.PlanDay{
+		{Date: date(t, "2026-03-05"), PlannedHours: 4, ActualHours: 5, Variance: 1},
+		{Date: date(t, "2026-03-06"), PlannedHours: 6, ActualHours: 8.5, Variance: 2.5},
+		{Date: date(t, "2026-03-07"), PlannedHours: 1.5, ActualHours: 0, Variance: -1.5},
+		{Date: date(t, "2026-03-08"), PlannedHours: 1.5, ActualHours: 0, Variance: -1.5},
+		{Date: date(t, "2026-03-09"), PlannedHours: 6, ActualHours: 4, Variance: -2},
+		{Date: date(t, "2026-03-10"), PlannedHours: 0, ActualHours: 1.5, Variance: 1.5},
+	}
+	if len(got.Days) != len(wantDays) {
+		t.Fatalf("Build() days = %+v, want %+v", got.Days, wantDays)
+	}
+	for i := range wantDays {
+		if got.Days[i] != wantDays[i] {
+			t.Errorf("day %d = %+v, want %+v", i, got.Days[i], wantDays[i])
+		}
+	}
+
+	empty := Build(models.MongoIpaProject{})
+	if empty.Tasks == nil || empty.Days == nil || !empty.Start.IsZero() {
+		t.Errorf("Build() of empty project = %+v", empty)
+	}
+}
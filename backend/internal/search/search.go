@@ -0,0 +1,247 @@
+// Package search durchsucht den Kriterienkatalog nach ID, Titel, Leitfrage und Anforderungen.
+package search
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+// Gewichtung der Felder: ein Treffer in der ID zählt mehr als einer im Titel usw.
+const (
+	weightID          = 8.0
+	weightTitle       = 4.0
+	weightQuestion    = 2.0
+	weightRequirement = 1.0
+
+	// prefixFactor gewichtet Präfixtreffer ("Doku" für "Dokumentation") schwächer als ganze Wörter.
+	prefixFactor = 0.5
+	// maxSnippets begrenzt die Textausschnitte pro Treffer.
+	maxSnippets = 3
+	// snippetLength ist die ungefähre Länge eines Textausschnitts in Bytes.
+	snippetLength = 160
+)
+
+// Index ist ein invertierter Index über einen Katalog. Er wird beim Laden des Katalogs
+// aufgebaut und danach nur gelesen.
+type Index struct {
+	criteria []models.Criterion
+	fields   [][]field            // Felder pro Kriterium
+	terms    []string             // Sortierte Stämme für die Präfixsuche
+	postings map[string][]posting // Vorkommen pro Stamm
+}
+
+// field ist ein durchsuchbarer Text eines Kriteriums.
+type field struct {
+	name   string // id, title, question oder requirements
+	index  int    // Index der Anforderung
+	weight float64
+	text   string
+	tokens []token
+}
+
+// posting ist ein Vorkommen eines Stamms.
+type posting struct {
+	criterion, field, token int
+}
+
+// NewIndex baut den Index über criteria auf.
+func NewIndex(criteria []models.Criterion) *Index {
+	idx := &Index{
+		criteria: criteria,
+		fields:   make([][]field, len(criteria)),
+		postings: make(map[string][]posting),
+	}
+	for c, criterion := range criteria {
+		fields := []field{
+			{name: "id", weight: weightID, text: criterion.ID},
+			{name: "title", weight: weightTitle, text: criterion.Title},
+			{name: "question", weight: weightQuestion, text: criterion.Question},
+		}
+		for i, requirement := range criterion.Requirements {
+			fields = append(fields, field{name: "requirements", index: i, weight: weightRequirement, text: requirement})
+		}
+		for f := range fields {
+			fields[f].tokens = tokenize(fields[f].text)
+			for t, tok := range fields[f].tokens {
+				idx.postings[tok.term] = append(idx.postings[tok.term], posting{c, f, t})
+			}
+		}
+		idx.fields[c] = fields
+	}
+	idx.terms = make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		idx.terms = append(idx.terms, term)
+	}
+	sort.Strings(idx.terms)
+	return idx
+}
+
+// match ist ein Kriterium, das bisher alle Suchbegriffe enthält.
+type match struct {
+	criterion int
+	score     float64
+	marked    map[[2]int]bool // Gefundene Wörter als {Feld, Token}
+}
+
+// Search liefert die höchstens limit besten Kriterien, die jeden Begriff aus query als Wort oder
+// Wortanfang enthalten. Die Bewertung berücksichtigt das Feld, ganze Wörter gegenüber Präfixen und
+// wie selten ein Begriff im Katalog ist. Bei gleicher Bewertung gilt die Reihenfolge im Katalog.
+func (idx *Index) Search(query string, limit int) []models.SearchHit {
+	var matches map[int]*match
+	seen := make(map[string]bool)
+	for _, queryToken := range tokenize(query) {
+		if seen[queryToken.term] {
+			continue
+		}
+		seen[queryToken.term] = true
+
+		found := idx.find(queryToken.term)
+		if matches == nil {
+			matches = found
+		} else {
+			for c, m := range matches {
+				other, ok := found[c]
+				if !ok {
+					delete(matches, c)
+					continue
+				}
+				m.score += other.score
+				for key := range other.marked {
+					m.marked[key] = true
+				}
+			}
+		}
+		if len(matches) == 0 {
+			break
+		}
+	}
+
+	ranked := make([]*match, 0, len(matches))
+	for _, m := range matches {
+		ranked = append(ranked, m)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].criterion < ranked[j].criterion
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	hits := make([]models.SearchHit, len(ranked))
+	for i, m := range ranked {
+		criterion := idx.criteria[m.criterion]
+		hits[i] = models.SearchHit{
+			ID:       criterion.ID,
+			Title:    criterion.Title,
+			Score:    math.Round(m.score*100) / 100,
+			Snippets: idx.snippets(m),
+		}
+	}
+	return hits
+}
+
+// find sucht einen Begriff als ganzes Wort und als Präfix längerer Wörter.
+func (idx *Index) find(term string) map[int]*match {
+	found := make(map[int]*match)
+	// Bester Treffer pro Kriterium und Feld, damit Wiederholungen in einem Feld nicht mehrfach zählen
+	best := make(map[[2]int]float64)
+	for i := sort.SearchStrings(idx.terms, term); i < len(idx.terms) && strings.HasPrefix(idx.terms[i], term); i++ {
+		factor := 1.0
+		if idx.terms[i] != term {
+			factor = prefixFactor
+		}
+		for _, p := range idx.postings[idx.terms[i]] {
+			m, ok := found[p.criterion]
+			if !ok {
+				m = &match{criterion: p.criterion, marked: make(map[[2]int]bool)}
+				found[p.criterion] = m
+			}
+			m.marked[[2]int{p.field, p.token}] = true
+			key := [2]int{p.criterion, p.field}
+			best[key] = max(best[key], idx.fields[p.criterion][p.field].weight*factor)
+		}
+	}
+
+	// Seltene Begriffe unterscheiden die Kriterien besser und zählen deshalb mehr
+	idf := math.Log(1 + float64(len(idx.criteria))/float64(max(len(found), 1)))
+	for key, score := range best {
+		found[key[0]].score += score * idf
+	}
+	return found
+}
+
+// snippets liefert die Felder mit Treffern als Textausschnitte, wichtige Felder zuerst.
+// Die ID wird nicht ausgeschnitten, sie steht ohnehin im Treffer.
+func (idx *Index) snippets(m *match) []models.SearchSnippet {
+	snippets := make([]models.SearchSnippet, 0, maxSnippets)
+	for f, fld := range idx.fields[m.criterion] {
+		if fld.name == "id" {
+			continue
+		}
+		var marked []token
+		for t, tok := range fld.tokens {
+			if m.marked[[2]int{f, t}] {
+				marked = append(marked, tok)
+			}
+		}
+		if len(marked) == 0 {
+			continue
+		}
+		snippet := models.SearchSnippet{Field: fld.name, Text: highlight(fld.text, marked)}
+		if fld.name == "requirements" {
+			snippet.Index = &fld.index
+		}
+		snippets = append(snippets, snippet)
+		if len(snippets) == maxSnippets {
+			break
+		}
+	}
+	return snippets
+}
+
+// highlight schneidet aus text einen Ausschnitt um das erste markierte Wort aus, escaped ihn
+// für HTML und zeichnet die markierten Wörter mit <mark> aus.
+func highlight(text string, marked []token) string {
+	start, end := 0, len(text)
+	if len(text) > snippetLength {
+		// An Wortgrenzen ausrichten, damit keine Wörter oder UTF-8-Zeichen zerschnitten werden
+		if context := marked[0].start - snippetLength/4; context > 0 {
+			start = strings.LastIndexFunc(text[:context], unicode.IsSpace) + 1
+		}
+		end = min(start+snippetLength, len(text))
+		if space := strings.IndexFunc(text[end:], unicode.IsSpace); space >= 0 {
+			end += space
+		} else {
+			end = len(text)
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("… ")
+	}
+	pos := start
+	for _, tok := range marked {
+		if tok.start < pos || tok.end > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:tok.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[tok.start:tok.end]))
+		b.WriteString("</mark>")
+		pos = tok.end
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		b.WriteString(" …")
+	}
+	return b.String()
+}
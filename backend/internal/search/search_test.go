@@ -0,0 +1,143 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Prüfung":  "prufung",
+		"Pruefung": "prufung",
+		"Prufung":  "prufung",
+		"Maßnahme": "massnahme",
+		"Qualität": "qualitat",
+		"Quelle":   "quelle",
+		"Qualité":  "qualite",
+		"A01":      "a01",
+	}
+	for word, want := range tests {
+		if got := normalize(word); got != want {
+			t.Errorf("normalize(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestStem(t *testing.T) {
+	stems := map[string][]string{
+		"dokumenti":   {"dokumentiert", "dokumentieren", "dokumentierte"},
+		"anforderung": {"anforderung", "anforderungen"},
+		"tes":         {"test", "tests", "testen"},
+		"a01":         {"a01"},
+	}
+	for want, words := range stems {
+		for _, word := range words {
+			if got := stem(word); got != want {
+				t.Errorf("stem(%q) = %q, want %q", word, got, want)
+			}
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	text := "Use-Case-Diagramme für Anforderungen"
+	var got []string
+	for _, tok := range tokenize(text) {
+		got = append(got, tok.term+"="+text[tok.start:tok.end])
+	}
+	want := []string{"use=Use", "cas=Case", "diagramm=Diagramme", "fur=für", "anforderung=Anforderungen"}
+	if !slices.Equal(got, want) {
+		t.Errorf("tokenize(%q) = %v, want %v", text, got, want)
+	}
+}
+
+var testCriteria = []models.Criterion{
+	{
+		ID:           "A01",
+		Title:        "Auftragsanalyse und Wahl einer Projektmethode",
+		Question:     "Wie erfolgt die Auftragsanalyse?",
+		Requirements: []string{"Die Analyse ist dokumentiert.", "Eine passende Projektmethode wurde gewählt."},
+	},
+	{
+		ID:           "A04",
+		Title:        "Zeitplan",
+		Question:     "Liegt ein Zeitplan vor?",
+		Requirements: []string{"Der Zeitplan enthält alle Tätigkeiten gemäss Projektmethode.", "Abweichungen werden <b>begründet</b>."},
+	},
+	{
+		ID:           "G01",
+		Title:        "Dokumentation fachlicher Anforderungen",
+		Question:     "Wie wurden die Anforderungen dokumentiert?",
+		Requirements: []string{"Die Anforderungen sind vollständig."},
+	},
+}
+
+func hitIDs(hits []models.SearchHit) []string {
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+func TestSearch(t *testing.T) {
+	idx := NewIndex(testCriteria)
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"Projektmethode", []string{"A01", "A04"}},   // Titel vor Anforderung
+		{"projektmethoden", []string{"A01", "A04"}},  // Flexion
+		{"Doku", []string{"G01", "A01"}},             // Präfix
+		{"taetigkeit", []string{"A04"}},              // Umschreibung des Umlauts
+		{"a04", []string{"A04"}},                     // ID
+		{"Zeitplan Projektmethode", []string{"A04"}}, // Alle Begriffe müssen vorkommen
+		{"Zeitplan Budget", nil},
+		{"?!", nil},
+	}
+	for _, tt := range tests {
+		if got := hitIDs(idx.Search(tt.query, 10)); !slices.Equal(got, tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if got := idx.Search("Anforderungen", 1); len(got) != 1 || got[0].ID != "G01" {
+		t.Errorf("Search() with limit 1 = %v, want only G01", hitIDs(got))
+	}
+}
+
+func TestSearchSnippets(t *testing.T) {
+	idx := NewIndex(testCriteria)
+
+	hits := idx.Search("Zeitplan", 10)
+	if len(hits) != 1 || len(hits[0].Snippets) != 3 {
+		t.Fatalf("Search() = %+v, want one hit with three snippets", hits)
+	}
+	title, requirement := hits[0].Snippets[0], hits[0].Snippets[2]
+	if title.Field != "title" || title.Text != "<mark>Zeitplan</mark>" || title.Index != nil {
+		t.Errorf("title snippet = %+v", title)
+	}
+	if requirement.Field != "requirements" || requirement.Index == nil || *requirement.Index != 0 ||
+		requirement.Text != "Der <mark>Zeitplan</mark> enthält alle Tätigkeiten gemäss Projektmethode." {
+		t.Errorf("requirement snippet = %+v", requirement)
+	}
+
+	hits = idx.Search("begründet", 10)
+	if got := hits[0].Snippets[0].Text; got != "Abweichungen werden &lt;b&gt;<mark>begründet</mark>&lt;/b&gt;." {
+		t.Errorf("snippet is not escaped: %q", got)
+	}
+}
+
+func TestHighlightLongText(t *testing.T) {
+	text := strings.Repeat("Füllwort ", 40) + "Zeitplan " + strings.Repeat("Füllwort ", 40)
+	got := highlight(text, tokenize(text)[40:41])
+	if !strings.HasPrefix(got, "… Füllwort") || !strings.HasSuffix(got, "Füllwort …") || !strings.Contains(got, "<mark>Zeitplan</mark>") {
+		t.Errorf("highlight() = %q", got)
+	}
+	if len(got) > snippetLength+40 {
+		t.Errorf("highlight() returned %d bytes, want about %d", len(got), snippetLength)
+	}
+}
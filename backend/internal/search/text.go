@@ -0,0 +1,90 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token ist ein Wort eines Textes mit seiner Position im Originaltext.
+type token struct {
+	term       string // Normalisierter Wortstamm
+	start, end int    // Byte-Positionen im Originaltext
+}
+
+// tokenize zerlegt text in Wörter aus Buchstaben und Ziffern und bildet deren Stämme.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text + " " {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			if term := stem(normalize(text[start:i])); term != "" {
+				tokens = append(tokens, token{term: term, start: start, end: i})
+			}
+			start = -1
+		}
+	}
+	return tokens
+}
+
+// folding bildet Umlaute, ß und Akzente auf ASCII ab, damit "Prüfung", "Pruefung" und
+// "Prufung" gleich behandelt werden.
+var folding = map[rune]string{
+	'ä': "a", 'ö': "o", 'ü': "u", 'ß': "ss",
+	'à': "a", 'á': "a", 'â': "a",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ò': "o", 'ó': "o", 'ô': "o",
+	'ù': "u", 'ú': "u", 'û': "u",
+	'ç': "c", 'œ': "oe", 'æ': "ae",
+}
+
+// normalize schreibt word klein und ersetzt Umlaute und Akzente. Umschreibungen wie "ae", "oe"
+// und "ue" gelten wie im Snowball-Stemmer "german2" als Umlaut, ausser "ue" nach "q".
+func normalize(word string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(word) {
+		if folded, ok := folding[r]; ok {
+			b.WriteString(folded)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	folded := b.String()
+
+	b.Reset()
+	for i := 0; i < len(folded); i++ {
+		b.WriteByte(folded[i])
+		if i+1 < len(folded) && folded[i+1] == 'e' && strings.IndexByte("aou", folded[i]) >= 0 &&
+			(folded[i] != 'u' || i == 0 || folded[i-1] != 'q') {
+			i++ // "e" der Umschreibung überspringen
+		}
+	}
+	return b.String()
+}
+
+// stem entfernt Flexionsendungen nach dem Stemmer CISTEM (Weissweiler & Fraser 2017), damit
+// z.B. "dokumentiert", "dokumentieren" und "Dokumentierte" denselben Stamm erhalten. Wörter
+// mit Ziffern wie Kriterien-IDs bleiben unverändert.
+func stem(word string) string {
+	if strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+		return word
+	}
+	for utf8.RuneCountInString(word) > 3 {
+		switch {
+		case utf8.RuneCountInString(word) > 5 && (strings.HasSuffix(word, "em") || strings.HasSuffix(word, "er") || strings.HasSuffix(word, "nd")):
+			word = word[:len(word)-2]
+		case strings.IndexByte("tesn", word[len(word)-1]) >= 0:
+			word = word[:len(word)-1]
+		default:
+			return word
+		}
+	}
+	return word
+}
@@ -0,0 +1,58 @@
+package selection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+// ValidateRules prüft, ob die im Katalog konfigurierten Auswahlregeln in sich stimmig sind.
+func ValidateRules(rules []models.SelectionRule) error {
+	for i, rule := range rules {
+		if len(rule.Categories) == 0 {
+			return fmt.Errorf("selection rule %d has no categories", i)
+		}
+		if rule.Min < 0 || rule.Max < 0 {
+			return fmt.Errorf("selection rule %d has a negative count", i)
+		}
+		if rule.Max > 0 && rule.Min > rule.Max {
+			return fmt.Errorf("selection rule %d: min %d is greater than max %d", i, rule.Min, rule.Max)
+		}
+	}
+	return nil
+}
+
+// Validate prüft die gewählten optionalen Kriterien eines Projekts gegen die Auswahlregeln.
+// Pflichtkriterien werden nicht mitgezählt.
+func Validate(rules []models.SelectionRule, criteria []models.Criterion) models.SelectionResult {
+	countByCategory := make(map[string]int)
+	for _, criterion := range criteria {
+		if common.IsOptionalCriterion(criterion.ID) {
+			countByCategory[common.CriterionCategory(criterion.ID)]++
+		}
+	}
+
+	result := models.SelectionResult{
+		Valid:      true,
+		Violations: make([]models.SelectionViolation, 0),
+	}
+	for _, rule := range rules {
+		count := 0
+		for _, category := range rule.Categories {
+			count += countByCategory[strings.ToUpper(category)]
+		}
+		if count < rule.Min || (rule.Max > 0 && count > rule.Max) {
+			result.Valid = false
+			result.Violations = append(result.Violations, models.SelectionViolation{
+				Description: rule.Description,
+				Categories:  rule.Categories,
+				Min:         rule.Min,
+				Max:         rule.Max,
+				Count:       count,
+			})
+		}
+	}
+	return result
+}
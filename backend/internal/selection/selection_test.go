@@ -0,0 +1,72 @@
+package selection
+
+import (
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+func criteriaWithIDs(ids ...string) []models.Criterion {
+	criteria := make([]models.Criterion, len(ids))
+	for i, id := range ids {
+		criteria[i] = models.Criterion{ID: id}
+	}
+	return criteria
+}
+
+func TestValidate(t *testing.T) {
+	rules := []models.SelectionRule{
+		{Description: "B bis H", Categories: []string{"B", "C", "G", "H"}, Min: 2, Max: 3},
+		{Description: "mindestens ein G", Categories: []string{"g"}, Min: 1},
+	}
+	tests := []struct {
+		name           string
+		criteria       []models.Criterion
+		wantValid      bool
+		wantViolations int
+	}{
+		{"valid selection", criteriaWithIDs("A01", "Doc01", "B01s", "G02"), true, 0},
+		{"mandatory criteria are not counted", criteriaWithIDs("A01", "A02", "G01"), false, 1},
+		{"too few", criteriaWithIDs("A01", "Doc01"), false, 2},
+		{"too many", criteriaWithIDs("B01s", "C01", "G01", "H01"), false, 1},
+		{"other optional categories are ignored", criteriaWithIDs("A13", "A14", "G01", "H02"), true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Validate(rules, tt.criteria)
+			if got.Valid != tt.wantValid || len(got.Violations) != tt.wantViolations {
+				t.Errorf("Validate() = %+v, want valid=%v with %d violations", got, tt.wantValid, tt.wantViolations)
+			}
+		})
+	}
+}
+
+func TestValidateReportsCount(t *testing.T) {
+	rules := []models.SelectionRule{{Categories: []string{"B", "C"}, Min: 3}}
+	got := Validate(rules, criteriaWithIDs("B02", "C03"))
+	if len(got.Violations) != 1 || got.Violations[0].Count != 2 {
+		t.Errorf("Validate() = %+v, want one violation with count 2", got)
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []models.SelectionRule
+		wantErr bool
+	}{
+		{"no rules", nil, false},
+		{"valid rule", []models.SelectionRule{{Categories: []string{"B"}, Min: 1, Max: 2}}, false},
+		{"unbounded max", []models.SelectionRule{{Categories: []string{"B"}, Min: 5}}, false},
+		{"missing categories", []models.SelectionRule{{Min: 1}}, true},
+		{"min greater than max", []models.SelectionRule{{Categories: []string{"B"}, Min: 3, Max: 2}}, true},
+		{"negative min", []models.SelectionRule{{Categories: []string{"B"}, Min: -1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateRules(tt.rules); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
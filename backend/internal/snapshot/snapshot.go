@@ -0,0 +1,182 @@
+// Package snapshot hält die Bewertung eines Projekts in verketteten, signierten Momentaufnahmen
+// fest, damit sich nachträgliche Änderungen an bereits festgehaltenen Bewertungen erkennen lassen.
+package snapshot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/grade"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+// Signer signiert Momentaufnahmen mit HMAC-SHA256.
+type Signer struct {
+	Key []byte
+}
+
+// New hält den Stand der Kriterien und die Note des Projekts fest, verkettet die Momentaufnahme
+// mit der letzten des Projekts und signiert sie. provisional gibt an, ob die Kriterienauswahl
+// die Auswahlregeln verletzt.
+func (s Signer) New(id string, project models.MongoIpaProject, provisional bool, user models.User, now time.Time) models.GradeSnapshot {
+	criteria := make([]models.SnapshotCriterion, len(project.Criteria))
+	for i, criterion := range project.Criteria {
+		checked := slices.Clone(criterion.Checked)
+		if checked == nil {
+			checked = make([]int, 0)
+		}
+		slices.Sort(checked)
+		criteria[i] = models.SnapshotCriterion{
+			ID:            criterion.ID,
+			Requirements:  len(criterion.Requirements),
+			Checked:       checked,
+			QualityLevels: criterion.QualityLevels,
+		}
+	}
+	result := grade.CalculateGrade(project.Criteria)
+	result.Provisional = provisional
+
+	snapshot := models.GradeSnapshot{
+		ID:               id,
+		ProjectID:        project.ID,
+		Sequence:         len(project.GradeSnapshots) + 1,
+		CreatedAt:        now,
+		GradedBy:         user.Name,
+		CatalogueVersion: project.CatalogueVersion,
+		Scheme:           grade.Scheme,
+		Criteria:         criteria,
+		Grade:            result,
+	}
+	if n := len(project.GradeSnapshots); n > 0 {
+		snapshot.PreviousHash = project.GradeSnapshots[n-1].Hash
+	}
+	snapshot.Hash = Hash(snapshot)
+	snapshot.Signature = s.sign(snapshot.Hash)
+	return snapshot
+}
+
+// Hash berechnet den SHA-256 über die JSON-Darstellung der Momentaufnahme ohne Hash und Signatur.
+func Hash(snapshot models.GradeSnapshot) string {
+	snapshot.Hash = ""
+	snapshot.Signature = ""
+	snapshot.CreatedAt = snapshot.CreatedAt.UTC()
+	payload, err := json.Marshal(snapshot)
+	if err != nil {
+		panic(err) // Die Momentaufnahme enthält nur Typen, die sich immer kodieren lassen
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify prüft Hash und Signatur einer einzelnen Momentaufnahme.
+func (s Signer) Verify(snapshot models.GradeSnapshot) bool {
+	return Hash(snapshot) == snapshot.Hash && s.validSignature(snapshot)
+}
+
+// VerifyProject prüft alle Momentaufnahmen des Projekts, ihre Verkettung und ob zu jedem
+// Übergang zu "graded" im Verlauf eine Momentaufnahme vorhanden ist.
+//
+// Erkannt werden veränderte Momentaufnahmen, entfernte, eingefügte oder vertauschte vor der
+// letzten sowie aus einem anderen Projekt kopierte. Bei älteren Momentaufnahmen ohne
+// Projekt-ID lässt sich eine Kopie nicht erkennen. Wird die letzte Momentaufnahme zusammen mit
+// ihrem Übergang im Verlauf entfernt, bleibt die verkürzte Kette gültig: Das Projekt enthält
+// keinen Anker für ihr Ende.
+func (s Signer) VerifyProject(project models.MongoIpaProject) models.SnapshotVerification {
+	verification := models.SnapshotVerification{
+		ProjectID: project.ID,
+		Valid:     true,
+		Snapshots: make([]models.SnapshotCheck, len(project.GradeSnapshots)),
+	}
+	previous := ""
+	for i, snapshot := range project.GradeSnapshots {
+		problems := make([]models.SnapshotProblem, 0)
+		if Hash(snapshot) != snapshot.Hash {
+			problems = append(problems, models.SnapshotContentModified)
+		}
+		if !s.validSignature(snapshot) {
+			problems = append(problems, models.SnapshotSignatureInvalid)
+		}
+		if snapshot.ProjectID != "" && snapshot.ProjectID != project.ID {
+			problems = append(problems, models.SnapshotProjectMismatch)
+		}
+		if snapshot.Sequence != i+1 || snapshot.PreviousHash != previous {
+			problems = append(problems, models.SnapshotChainBroken)
+		}
+		if snapshot.Scheme == grade.Scheme && !sameGrade(recalculate(snapshot), snapshot.Grade) {
+			problems = append(problems, models.SnapshotGradeMismatch)
+		}
+		verification.Snapshots[i] = models.SnapshotCheck{
+			ID:       snapshot.ID,
+			Sequence: snapshot.Sequence,
+			Valid:    len(problems) == 0,
+			Problems: problems,
+		}
+		verification.Valid = verification.Valid && len(problems) == 0
+		previous = snapshot.Hash
+	}
+
+	graded := 0
+	for _, change := range project.StatusHistory {
+		if change.To == models.StatusGraded {
+			graded++
+		}
+	}
+	if graded > len(project.GradeSnapshots) {
+		verification.Missing = graded - len(project.GradeSnapshots)
+		verification.Valid = false
+	}
+	return verification
+}
+
+// validSignature prüft die Signatur über den Hash der Momentaufnahme.
+func (s Signer) validSignature(snapshot models.GradeSnapshot) bool {
+	expected, err := hex.DecodeString(snapshot.Signature)
+	if err != nil {
+		return false
+	}
+	actual, _ := hex.DecodeString(s.sign(snapshot.Hash))
+	return hmac.Equal(expected, actual)
+}
+
+// sign berechnet die Signatur über einen Hash.
+func (s Signer) sign(hash string) string {
+	mac := hmac.New(sha256.New, s.Key)
+	mac.Write([]byte(hash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// recalculate berechnet die Note aus den festgehaltenen Kriterien erneut.
+func recalculate(snapshot models.GradeSnapshot) models.GradeResult {
+	criteria := make([]models.Criterion, len(snapshot.Criteria))
+	for i, criterion := range snapshot.Criteria {
+		criteria[i] = models.Criterion{
+			ID:            criterion.ID,
+			Requirements:  make([]string, criterion.Requirements),
+			Checked:       criterion.Checked,
+			QualityLevels: criterion.QualityLevels,
+		}
+	}
+	return grade.CalculateGrade(criteria)
+}
+
+// sameGrade vergleicht Noten und Gütestufen zweier Ergebnisse, die Titel der Kriterien bleiben
+// unberücksichtigt.
+func sameGrade(a, b models.GradeResult) bool {
+	return sameDetails(a.Part1, b.Part1) && sameDetails(a.Part2, b.Part2)
+}
+
+func sameDetails(a, b models.GradeDetails) bool {
+	if a.Grade != b.Grade || a.AverageQualityLevel != b.AverageQualityLevel || len(a.CriterionGrades) != len(b.CriterionGrades) {
+		return false
+	}
+	for i := range a.CriterionGrades {
+		if a.CriterionGrades[i].CriterionID != b.CriterionGrades[i].CriterionID || a.CriterionGrades[i].QualityLevel != b.CriterionGrades[i].QualityLevel {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/grade"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func testProject() models.MongoIpaProject {
+	levels := map[string]models.QualityLevel{
+		"0": {MinRequirements: 0},
+		"1": {MinRequirements: 1},
+		"2": {MinRequirements: 2},
+	}
+	return models.MongoIpaProject{
+		CatalogueVersion: "2025.1",
+		Criteria: []models.Criterion{
+			{ID: "A01", Requirements: []string{"a", "b"}, Checked: []int{1, 0}, QualityLevels: levels},
+			{ID: "Doc01", Requirements: []string{"a", "b"}, QualityLevels: levels},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	signer := Signer{Key: []byte("secret")}
+	expert := models.User{Role: models.RoleExpert, Name: "Eva Experte"}
+	now := time.Date(2026, 5, 20, 9, 0, 0, 0, time.UTC)
+	project := testProject()
+	project.ID = "AB12"
+	snapshot := signer.New("S1", project, true, expert, now)
+
+	if snapshot.ProjectID != "AB12" || snapshot.GradedBy != "Eva Experte" || snapshot.CatalogueVersion != "2025.1" || snapshot.Scheme != grade.Scheme || !snapshot.Grade.Provisional || len(snapshot.Criteria) != 2 {
+		t.Fatalf("New() = %+v", snapshot)
+	}
+	if checked := snapshot.Criteria[0].Checked; len(checked) != 2 || checked[0] != 0 || checked[1] != 1 {
+		t.Errorf("New() checked = %v, want sorted [0 1]", checked)
+	}
+	if snapshot.Sequence != 1 || snapshot.PreviousHash != "" || snapshot.Criteria[0].Requirements != 2 || len(snapshot.Criteria[0].QualityLevels) != 3 {
+		t.Errorf("New() = %+v, want first snapshot with criteria state", snapshot)
+	}
+	if snapshot.Criteria[1].Checked == nil {
+		t.Error("New() checked = nil, want empty slice")
+	}
+	if !signer.Verify(snapshot) {
+		t.Error("Verify() rejected a new snapshot")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	signer := Signer{Key: []byte("secret")}
+	snapshot := signer.New("S1", testProject(), false, models.User{Role: models.RoleExpert, Name: "Eva Experte"},
+		time.Date(2026, 5, 20, 9, 0, 0, 0, time.UTC))
+
+	// Gespeicherte und exportierte Momentaufnahmen bleiben gültig
+	var stored models.GradeSnapshot
+	data, err := bson.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+	if err := bson.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("bson.Unmarshal() error = %v", err)
+	}
+	if !signer.Verify(stored) {
+		t.Error("Verify() rejected a snapshot read from MongoDB")
+	}
+	var exported models.GradeSnapshot
+	data, _ = json.Marshal(snapshot)
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !signer.Verify(exported) {
+		t.Error("Verify() rejected a snapshot read from JSON")
+	}
+
+	tampered := snapshot
+	tampered.Criteria = []models.SnapshotCriterion{{ID: "A01", Checked: []int{0}}, snapshot.Criteria[1]}
+	if signer.Verify(tampered) {
+		t.Error("Verify() accepted changed checkmarks")
+	}
+	tampered = snapshot
+	tampered.Grade.Part1.Grade = 1
+	if signer.Verify(tampered) {
+		t.Error("Verify() accepted a changed grade")
+	}
+	if (Signer{Key: []byte("other")}).Verify(snapshot) {
+		t.Error("Verify() accepted a snapshot signed with another key")
+	}
+}
+
+func TestVerifyProject(t *testing.T) {
+	signer := Signer{Key: []byte("secret")}
+	expert := models.User{Role: models.RoleExpert, Name: "Eva Experte"}
+	now := time.Date(2026, 5, 20, 9, 0, 0, 0, time.UTC)
+	graded := models.StatusChange{From: models.StatusUnderReview, To: models.StatusGraded}
+
+	project := testProject()
+	project.ID = "AB12"
+	project.GradeSnapshots = []models.GradeSnapshot{signer.New("S1", project, false, expert, now)}
+	project.Criteria[1].Checked = []int{0, 1}
+	project.GradeSnapshots = append(project.GradeSnapshots, signer.New("S2", project, false, expert, now.Add(time.Hour)))
+	project.StatusHistory = []models.StatusChange{graded, graded}
+
+	second := project.GradeSnapshots[1]
+	if second.Sequence != 2 || second.PreviousHash != project.GradeSnapshots[0].Hash {
+		t.Fatalf("New() = %+v, want snapshot chained to S1", second)
+	}
+	if got := signer.VerifyProject(project); !got.Valid || got.ProjectID != "AB12" || len(got.Snapshots) != 2 || len(got.Snapshots[1].Problems) != 0 {
+		t.Errorf("VerifyProject() = %+v, want valid", got)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *models.MongoIpaProject)
+		want   []models.SnapshotProblem
+	}{
+		{"changed grade", func(p *models.MongoIpaProject) {
+			p.GradeSnapshots[1].Grade.Part2.Grade = 1
+		}, []models.SnapshotProblem{models.SnapshotContentModified, models.SnapshotGradeMismatch}},
+		{"rehashed without key", func(p *models.MongoIpaProject) {
+			p.GradeSnapshots[1].Grade.Part2.Grade = 1
+			p.GradeSnapshots[1].Hash = Hash(p.GradeSnapshots[1])
+		}, []models.SnapshotProblem{models.SnapshotSignatureInvalid, models.SnapshotGradeMismatch}},
+		{"copied to other project", func(p *models.MongoIpaProject) {
+			p.ID = "CD34"
+		}, []models.SnapshotProblem{models.SnapshotProjectMismatch}},
+		{"removed predecessor", func(p *models.MongoIpaProject) {
+			p.GradeSnapshots = p.GradeSnapshots[1:]
+			p.StatusHistory = p.StatusHistory[1:]
+		}, []models.SnapshotProblem{models.SnapshotChainBroken}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modified := project
+			modified.GradeSnapshots = slices.Clone(project.GradeSnapshots)
+			tt.modify(&modified)
+			got := signer.VerifyProject(modified)
+			last := got.Snapshots[len(got.Snapshots)-1]
+			if got.Valid || last.Valid || !slices.Equal(last.Problems, tt.want) {
+				t.Errorf("VerifyProject() = %+v, want problems %v", got, tt.want)
+			}
+		})
+	}
+
+	removed := project
+	removed.GradeSnapshots = project.GradeSnapshots[:1]
+	if got := signer.VerifyProject(removed); got.Valid || got.Missing != 1 {
+		t.Errorf("VerifyProject() = %+v, want one missing snapshot", got)
+	}
+
+	// Ohne Anker ausserhalb des Projekts bleibt eine am Ende verkürzte Kette gültig, siehe VerifyProject
+	truncated := removed
+	truncated.StatusHistory = project.StatusHistory[:1]
+	if got := signer.VerifyProject(truncated); !got.Valid {
+		t.Errorf("VerifyProject() = %+v, truncation at the end is documented as not detected", got)
+	}
+}
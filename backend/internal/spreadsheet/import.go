@@ -0,0 +1,302 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/admin"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/grade"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/notes"
+	"github.com/xuri/excelize/v2"
+)
+
+// Entry ist eine Zeile einer importierten Tabelle.
+type Entry struct {
+	Row     int // Zeilennummer in der Datei, die Kopfzeile zählt mit
+	ID      string
+	Checked []int   // Sortiert, ohne Duplikate
+	Notes   *string // nil, wenn die Tabelle keine Spalte notes hat
+}
+
+var (
+	ErrEmpty              = errors.New("spreadsheet is empty")
+	ErrNoCriteria         = errors.New("spreadsheet contains no criteria")
+	ErrInvalidCSV         = errors.New("invalid CSV")
+	ErrInvalidExcel       = errors.New("invalid Excel file")
+	ErrMissingColumn      = errors.New("missing column %q")
+	ErrIDMissing          = errors.New("id is missing")
+	ErrDuplicateRow       = errors.New("duplicate of row %d")
+	ErrInvalidIndex       = errors.New("invalid requirement index %q")
+	ErrCriterionUnknown   = errors.New("criterion %s is not part of the project")
+	ErrRequirementUnknown = errors.New("requirement %d does not exist, the criterion has %d requirements")
+)
+
+// SheetError enthält alle fehlerhaften Zeilen einer importierten Tabelle.
+type SheetError struct {
+	Rows []admin.RowError
+}
+
+func (e *SheetError) Error() string {
+	return fmt.Sprintf("spreadsheet contains %d invalid rows", len(e.Rows))
+}
+
+// xlsxSignature sind die ersten Bytes einer Excel-Datei, die ein ZIP-Archiv ist.
+var xlsxSignature = []byte("PK\x03\x04")
+
+// Read liest eine Tabelle im Format des Exports. Nur die Spalten id, checked und notes werden
+// ausgewertet, fehlt notes, bleiben die Notizen unverändert. Excel-Dateien werden am Inhalt
+// erkannt, alles andere wird als CSV mit Semikolon oder Komma gelesen. Die gesamte Tabelle wird
+// geprüft, bevor ein Fehler zurückgegeben wird. Fehler im Inhalt enthalten einen der Err-Fehler
+// dieses Pakets.
+func Read(data []byte) ([]Entry, error) {
+	var records [][]string
+	var lines []int
+	var err error
+	if bytes.HasPrefix(data, xlsxSignature) {
+		records, lines, err = readXLSX(data)
+	} else {
+		records, lines, err = readCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrEmpty
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, column := range []string{"id", "checked"} {
+		if _, ok := columns[column]; !ok {
+			return nil, admin.NewContentError(ErrMissingColumn, column)
+		}
+	}
+	value := func(record []string, column string) string {
+		if i := columns[column]; i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+	_, hasNotes := columns["notes"]
+
+	entries := make([]Entry, 0, len(records)-1)
+	sheetErr := &SheetError{}
+	seen := make(map[string]int)
+	for i, record := range records[1:] {
+		row := lines[i+1]
+		if isBlank(record) {
+			continue
+		}
+		entry := Entry{Row: row, ID: strings.TrimSpace(value(record, "id"))}
+
+		var problems []error
+		if entry.ID == "" {
+			problems = append(problems, ErrIDMissing)
+		} else if first, ok := seen[entry.ID]; ok {
+			problems = append(problems, admin.NewContentError(ErrDuplicateRow, first))
+		} else {
+			seen[entry.ID] = row
+		}
+		checked, err := parseIndexes(value(record, "checked"))
+		if err != nil {
+			problems = append(problems, err)
+		}
+		entry.Checked = checked
+		if hasNotes {
+			text := value(record, "notes")
+			entry.Notes = &text
+		}
+
+		if len(problems) > 0 {
+			sheetErr.Rows = append(sheetErr.Rows, admin.NewRowError(row, problems))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(sheetErr.Rows) > 0 {
+		return nil, sheetErr
+	}
+	if len(entries) == 0 {
+		return nil, ErrNoCriteria
+	}
+	return entries, nil
+}
+
+// Plan vergleicht die Zeilen mit den Kriterien des Projekts und beschreibt die Änderungen samt
+// den Noten davor und danach. Zeilen zu Kriterien, die das Projekt nicht hat, und Indizes
+// ausserhalb der Anforderungen eines Kriteriums sind Fehler.
+func Plan(project models.MongoIpaProject, entries []Entry) (models.SpreadsheetImport, error) {
+	positions := make(map[string]int, len(project.Criteria))
+	for i, criterion := range project.Criteria {
+		positions[criterion.ID] = i
+	}
+
+	result := models.SpreadsheetImport{Changes: make([]models.CriterionChange, 0)}
+	after := slices.Clone(project.Criteria)
+	sheetErr := &SheetError{}
+	for _, entry := range entries {
+		i, ok := positions[entry.ID]
+		if !ok {
+			sheetErr.Rows = append(sheetErr.Rows, admin.NewRowError(entry.Row, []error{admin.NewContentError(ErrCriterionUnknown, entry.ID)}))
+			continue
+		}
+		criterion := project.Criteria[i]
+
+		var problems []error
+		if len(entry.Checked) > 0 && entry.Checked[len(entry.Checked)-1] >= len(criterion.Requirements) {
+			problems = append(problems, admin.NewContentError(ErrRequirementUnknown,
+				entry.Checked[len(entry.Checked)-1], len(criterion.Requirements)))
+		}
+		notesAfter := criterion.Notes
+		if entry.Notes != nil {
+			notesAfter = *entry.Notes
+		}
+		if err := notes.Validate(notesAfter); err != nil {
+			problems = append(problems, err)
+		}
+		if len(problems) > 0 {
+			sheetErr.Rows = append(sheetErr.Rows, admin.NewRowError(entry.Row, problems))
+			continue
+		}
+
+		checkedBefore := slices.Clone(criterion.Checked)
+		if checkedBefore == nil {
+			checkedBefore = make([]int, 0)
+		}
+		slices.Sort(checkedBefore)
+		if slices.Equal(checkedBefore, entry.Checked) && notesAfter == criterion.Notes {
+			result.Unchanged++
+			continue
+		}
+		after[i].Checked = entry.Checked
+		after[i].Notes = notesAfter
+		result.Changes = append(result.Changes, models.CriterionChange{
+			CriterionID:   criterion.ID,
+			Title:         criterion.Title,
+			Row:           entry.Row,
+			CheckedBefore: checkedBefore,
+			CheckedAfter:  entry.Checked,
+			NotesBefore:   criterion.Notes,
+			NotesAfter:    notesAfter,
+		})
+	}
+	if len(sheetErr.Rows) > 0 {
+		return models.SpreadsheetImport{}, sheetErr
+	}
+
+	before, afterGrade := grade.CalculateGrade(project.Criteria), grade.CalculateGrade(after)
+	levelsBefore, levelsAfter := qualityLevels(before), qualityLevels(afterGrade)
+	for i := range result.Changes {
+		result.Changes[i].QualityLevelBefore = levelsBefore[result.Changes[i].CriterionID]
+		result.Changes[i].QualityLevelAfter = levelsAfter[result.Changes[i].CriterionID]
+	}
+	result.Part1GradeBefore, result.Part1GradeAfter = before.Part1.Grade, afterGrade.Part1.Grade
+	result.Part2GradeBefore, result.Part2GradeAfter = before.Part2.Grade, afterGrade.Part2.Grade
+	return result, nil
+}
+
+func readCSV(data []byte) ([][]string, []int, error) {
+	text := strings.TrimPrefix(string(data), "\ufeff") // BOM von Excel entfernen
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = detectDelimiter(text)
+	reader.Comment = '#' // Angaben aus dem Blatt summary
+	reader.FieldsPerRecord = -1
+
+	var records [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, lines, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCSV, err)
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+}
+
+func readXLSX(data []byte) ([][]string, []int, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidExcel, err)
+	}
+	defer f.Close()
+
+	sheet := SheetCriteria
+	if sheets := f.GetSheetList(); !slices.Contains(sheets, sheet) && len(sheets) > 0 {
+		sheet = sheets[0]
+	}
+	records, err := f.GetRows(sheet)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidExcel, err)
+	}
+	lines := make([]int, len(records))
+	for i := range lines {
+		lines[i] = i + 1
+	}
+	return records, lines, nil
+}
+
+// parseIndexes liest die Indizes der erfüllten Anforderungen, getrennt durch Komma, Semikolon
+// oder Leerzeichen.
+func parseIndexes(value string) ([]int, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+	indexes := make([]int, 0, len(fields))
+	for _, field := range fields {
+		index, err := strconv.Atoi(field)
+		if err != nil || index < 0 {
+			return nil, admin.NewContentError(ErrInvalidIndex, field)
+		}
+		indexes = append(indexes, index)
+	}
+	slices.Sort(indexes)
+	return slices.Compact(indexes), nil
+}
+
+// detectDelimiter wählt das Trennzeichen anhand der ersten Zeile, die kein Kommentar ist.
+func detectDelimiter(text string) rune {
+	for line := range strings.Lines(text) {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.Count(line, ";") >= strings.Count(line, ",") {
+			return ';'
+		}
+		return ','
+	}
+	return ';'
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func qualityLevels(result models.GradeResult) map[string]int {
+	levels := make(map[string]int)
+	for _, criterionGrade := range slices.Concat(result.Part1.CriterionGrades, result.Part2.CriterionGrades) {
+		levels[criterionGrade.CriterionID] = criterionGrade.QualityLevel
+	}
+	return levels
+}
@@ -0,0 +1,114 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/notes"
+)
+
+func TestReadRoundtrip(t *testing.T) {
+	export := testExport()
+	for _, format := range []string{FormatCSV, FormatXLSX} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := export.Write(&buf, format); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			entries, err := Read(buf.Bytes())
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if len(entries) != 2 || entries[0].ID != "A01" || !slices.Equal(entries[0].Checked, []int{0, 2}) ||
+				entries[0].Notes == nil || *entries[0].Notes != export.Rows[0].Notes || len(entries[1].Checked) != 0 {
+				t.Errorf("Read() = %+v", entries)
+			}
+			if format == FormatCSV && entries[0].Row != 11 {
+				t.Errorf("Read() row = %d, want line 11 after the summary", entries[0].Row)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Entry
+		wantErr string
+	}{
+		{"comma without notes", "id,checked\nA01,\"2, 0,2\"\n\n", []Entry{{Row: 2, ID: "A01", Checked: []int{0, 2}}}, ""},
+		{"missing column", "id;notes\nA01;x", nil, `missing column "checked"`},
+		{"empty", "", nil, "spreadsheet is empty"},
+		{"header only", "id;checked\n", nil, "no criteria"},
+		{"invalid rows", "id;checked\n;1\nA01;x\nA01;1\nA01;-1", nil, "4 invalid rows"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Read() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || len(got) != len(tt.want) || got[0].ID != tt.want[0].ID || got[0].Row != tt.want[0].Row ||
+				!slices.Equal(got[0].Checked, tt.want[0].Checked) || got[0].Notes != nil {
+				t.Errorf("Read() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	project := models.MongoIpaProject{Criteria: []models.Criterion{
+		{ID: "A01", Title: "Auftragsanalyse", Requirements: []string{"a", "b", "c"}, Checked: []int{2, 0},
+			QualityLevels: map[string]models.QualityLevel{"2": {MinRequirements: 2}}},
+		{ID: "A02", Requirements: []string{"a"}, Notes: "alt"},
+		{ID: "A03", Requirements: []string{"a"}},
+	}}
+	newNotes := "neu"
+	entries := []Entry{
+		{Row: 2, ID: "A01", Checked: []int{0, 1, 2}},
+		{Row: 3, ID: "A02", Checked: []int{}, Notes: &newNotes},
+		{Row: 4, ID: "A03", Checked: []int{}},
+	}
+
+	result, err := Plan(project, entries)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(result.Changes) != 2 || result.Unchanged != 1 {
+		t.Fatalf("Plan() = %+v", result)
+	}
+	a01 := result.Changes[0]
+	if !slices.Equal(a01.CheckedBefore, []int{0, 2}) || !slices.Equal(a01.CheckedAfter, []int{0, 1, 2}) ||
+		a01.QualityLevelBefore != 2 || a01.QualityLevelAfter != 3 || a01.Row != 2 || a01.Title != "Auftragsanalyse" {
+		t.Errorf("Plan() A01 = %+v", a01)
+	}
+	if a02 := result.Changes[1]; a02.NotesBefore != "alt" || a02.NotesAfter != "neu" || a02.CheckedBefore == nil {
+		t.Errorf("Plan() A02 = %+v", a02)
+	}
+	if result.Part1GradeBefore != 2.11 || result.Part1GradeAfter != 2.67 {
+		t.Errorf("Plan() grades = %v → %v", result.Part1GradeBefore, result.Part1GradeAfter)
+	}
+	if project.Criteria[0].Checked[0] != 2 {
+		t.Error("Plan() changed the project")
+	}
+
+	tooLong := strings.Repeat("a", notes.MaxLength+1)
+	_, err = Plan(project, []Entry{{Row: 2, ID: "B99"}, {Row: 3, ID: "A01", Checked: []int{3}}, {Row: 4, ID: "A02", Notes: &tooLong}})
+	var sheetErr *SheetError
+	if !errors.As(err, &sheetErr) || len(sheetErr.Rows) != 3 || sheetErr.Rows[1].Row != 3 {
+		t.Fatalf("Plan() error = %v, want three invalid rows", err)
+	}
+	for i, want := range []error{ErrCriterionUnknown, ErrRequirementUnknown, notes.ErrTooLong} {
+		if problems := sheetErr.Rows[i].Problems; len(problems) != 1 || !errors.Is(problems[0], want) {
+			t.Errorf("Plan() row %d problems = %v, want %v", sheetErr.Rows[i].Row, problems, want)
+		}
+	}
+}
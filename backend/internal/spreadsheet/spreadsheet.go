@@ -0,0 +1,265 @@
+// Package spreadsheet schreibt die Kriterien und Noten eines Projekts als CSV- oder Excel-Datei,
+// damit die Expertinnen und Experten sie in das kantonale Bewertungsformular übertragen können,
+// und liest offline bearbeitete Dateien im selben Format wieder ein.
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+// Formate der Datei.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Tabellenblätter der Excel-Datei.
+const (
+	SheetCriteria = "criteria"
+	SheetSummary  = "summary"
+)
+
+// ContentTypes enthält den Content-Type je Format.
+var ContentTypes = map[string]string{
+	FormatCSV:  "text/csv; charset=utf-8",
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+var ErrUnknownFormat = errors.New("unknown format")
+
+// Columns sind die Spalten der Kriterientabelle. checked enthält die Indizes der erfüllten
+// Anforderungen wie in der API, fulfilled und total die Anzahl.
+var Columns = []string{"id", "title", "part", "checked", "fulfilled", "total", "qualityLevel", "notes"}
+
+// Row ist eine Zeile der Kriterientabelle.
+type Row struct {
+	ID           string
+	Title        string
+	Part         int
+	Checked      []int
+	Requirements int
+	QualityLevel int
+	Notes        string
+}
+
+// Summary sind die Angaben auf dem Blatt summary.
+type Summary struct {
+	ProjectID        string
+	Firstname        string
+	Lastname         string
+	Topic            string
+	CatalogueVersion string
+	Part1Grade       float64
+	Part2Grade       float64
+	Provisional      bool
+	ExportedAt       time.Time
+}
+
+// Export ist der Inhalt einer exportierten Datei.
+type Export struct {
+	Summary Summary
+	Rows    []Row
+}
+
+// New stellt die Kriterien eines Projekts mit den Gütestufen aus result zusammen. Die Texte
+// werden unverändert übernommen, project sollte deshalb bereits übersetzt sein.
+func New(project models.MongoIpaProject, result models.GradeResult, now time.Time) Export {
+	levels := qualityLevels(result)
+	rows := make([]Row, len(project.Criteria))
+	for i, criterion := range project.Criteria {
+		checked := slices.Clone(criterion.Checked)
+		slices.Sort(checked)
+		part := 2
+		if common.IsCriterionPart1(criterion.ID) {
+			part = 1
+		}
+		rows[i] = Row{
+			ID:           criterion.ID,
+			Title:        criterion.Title,
+			Part:         part,
+			Checked:      checked,
+			Requirements: len(criterion.Requirements),
+			QualityLevel: levels[criterion.ID],
+			Notes:        criterion.Notes,
+		}
+	}
+	return Export{
+		Summary: Summary{
+			ProjectID:        project.ID,
+			Firstname:        project.Firstname,
+			Lastname:         project.Lastname,
+			Topic:            project.Topic,
+			CatalogueVersion: project.CatalogueVersion,
+			Part1Grade:       result.Part1.Grade,
+			Part2Grade:       result.Part2.Grade,
+			Provisional:      result.Provisional,
+			ExportedAt:       now,
+		},
+		Rows: rows,
+	}
+}
+
+// Write schreibt die Datei im angegebenen Format.
+func (e Export) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatCSV:
+		return e.WriteCSV(w)
+	case FormatXLSX:
+		return e.WriteXLSX(w)
+	}
+	return ErrUnknownFormat
+}
+
+// WriteCSV schreibt die Kriterientabelle als CSV mit Semikolon als Trennzeichen. Die Angaben
+// des Blatts summary stehen davor in Kommentarzeilen, die mit # beginnen.
+func (e Export) WriteCSV(w io.Writer) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil { // Byte Order Mark, damit Excel die Umlaute erkennt
+		return err
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+	for _, field := range e.summaryRows() {
+		if err := writer.Write([]string{"#" + field.key, format(field.value)}); err != nil {
+			return err
+		}
+	}
+	if err := writer.Write(Columns); err != nil {
+		return err
+	}
+	for _, row := range e.Rows {
+		if err := writer.Write(stringValues(row.values())); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteXLSX schreibt eine Excel-Datei mit den Blättern criteria und summary.
+func (e Export) WriteXLSX(w io.Writer) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName("Sheet1", SheetCriteria); err != nil {
+		return err
+	}
+	if _, err := f.NewSheet(SheetSummary); err != nil {
+		return err
+	}
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+
+	header := make([]any, len(Columns))
+	for i, column := range Columns {
+		header[i] = column
+	}
+	rows := [][]any{header}
+	for _, row := range e.Rows {
+		rows = append(rows, row.values())
+	}
+	if err := writeRows(f, SheetCriteria, rows); err != nil {
+		return err
+	}
+	last, _ := excelize.ColumnNumberToName(len(Columns))
+	if err := f.SetCellStyle(SheetCriteria, "A1", last+"1", bold); err != nil {
+		return err
+	}
+	for column, width := range map[string]float64{"B": 50, "D": 15, "H": 60} {
+		if err := f.SetColWidth(SheetCriteria, column, column, width); err != nil {
+			return err
+		}
+	}
+
+	summary := make([][]any, 0)
+	for _, field := range e.summaryRows() {
+		summary = append(summary, []any{field.key, field.value})
+	}
+	if err := writeRows(f, SheetSummary, summary); err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(SheetSummary, "A1", "A"+strconv.Itoa(len(summary)), bold); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(SheetSummary, "A", "B", 25); err != nil {
+		return err
+	}
+	return f.Write(w)
+}
+
+// values liefert die Zellen einer Zeile in der Reihenfolge von Columns.
+func (r Row) values() []any {
+	checked := make([]string, len(r.Checked))
+	for i, index := range r.Checked {
+		checked[i] = strconv.Itoa(index)
+	}
+	return []any{r.ID, r.Title, r.Part, strings.Join(checked, ","), len(r.Checked), r.Requirements, r.QualityLevel, r.Notes}
+}
+
+// summaryField ist eine Zeile des Blatts summary.
+type summaryField struct {
+	key   string
+	value any
+}
+
+// summaryRows liefert die Angaben des Blatts summary.
+func (e Export) summaryRows() []summaryField {
+	s := e.Summary
+	return []summaryField{
+		{"projectId", s.ProjectID},
+		{"firstname", s.Firstname},
+		{"lastname", s.Lastname},
+		{"topic", s.Topic},
+		{"catalogueVersion", s.CatalogueVersion},
+		{"part1Grade", s.Part1Grade},
+		{"part2Grade", s.Part2Grade},
+		{"provisional", s.Provisional},
+		{"exportedAt", s.ExportedAt.UTC().Format(time.RFC3339)},
+	}
+}
+
+func writeRows(f *excelize.File, sheet string, rows [][]any) error {
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stringValues(values []any) []string {
+	fields := make([]string, len(values))
+	for i, value := range values {
+		fields[i] = format(value)
+	}
+	return fields
+}
+
+func format(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	panic("spreadsheet: unsupported value") // values und summaryRows liefern nur die Typen oben
+}
@@ -0,0 +1,96 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/grade"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+func testExport() Export {
+	project := models.MongoIpaProject{
+		ID:        "AA01",
+		Firstname: "Anna",
+		Lastname:  "Muster",
+		Criteria: []models.Criterion{
+			{ID: "A01", Title: "Auftragsanalyse", Requirements: []string{"a", "b", "c"}, Checked: []int{2, 0},
+				QualityLevels: map[string]models.QualityLevel{"2": {MinRequirements: 2}}, Notes: "Gut; \"vollständig\""},
+			{ID: "Doc01", Title: "Kurzfassung", Requirements: []string{"a"}},
+		},
+	}
+	return New(project, grade.CalculateGrade(project.Criteria), time.Date(2026, 5, 20, 9, 0, 0, 0, time.UTC))
+}
+
+func TestNew(t *testing.T) {
+	export := testExport()
+	if len(export.Rows) != 2 || export.Summary.Part1Grade != 4.33 || export.Summary.Part2Grade != 1 {
+		t.Fatalf("New() = %+v", export)
+	}
+	want := Row{ID: "A01", Title: "Auftragsanalyse", Part: 1, Checked: []int{0, 2}, Requirements: 3, QualityLevel: 2, Notes: "Gut; \"vollständig\""}
+	if got := export.Rows[0]; got.ID != want.ID || !slices.Equal(got.Checked, want.Checked) || got.Requirements != 3 || got.QualityLevel != 2 || got.Part != 1 {
+		t.Errorf("New() row = %+v, want %+v", got, want)
+	}
+	if export.Rows[1].Part != 2 {
+		t.Errorf("New() Doc01 part = %d, want 2", export.Rows[1].Part)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testExport().Write(&buf, FormatCSV); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "\ufeff#projectId;AA01\n") || !strings.Contains(buf.String(), "#part1Grade;4.33\n") {
+		t.Errorf("Write() = %q, want summary comments first", buf.String())
+	}
+
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\ufeff")))
+	reader.Comma = ';'
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("csv.ReadAll() error = %v", err)
+	}
+	if len(records) != 3 || !slices.Equal(records[0], Columns) {
+		t.Fatalf("records = %q", records)
+	}
+	if want := []string{"A01", "Auftragsanalyse", "1", "0,2", "2", "3", "2", "Gut; \"vollständig\""}; !slices.Equal(records[1], want) {
+		t.Errorf("row = %q, want %q", records[1], want)
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testExport().Write(&buf, FormatXLSX); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("excelize.OpenReader() error = %v", err)
+	}
+	defer f.Close()
+
+	if sheets := f.GetSheetList(); !slices.Equal(sheets, []string{SheetCriteria, SheetSummary}) {
+		t.Errorf("sheets = %v", sheets)
+	}
+	rows, err := f.GetRows(SheetCriteria)
+	if err != nil || len(rows) != 3 || !slices.Equal(rows[0], Columns) || rows[1][3] != "0,2" || rows[2][0] != "Doc01" {
+		t.Errorf("criteria = %q, error %v", rows, err)
+	}
+	if grade, _ := f.GetCellValue(SheetSummary, "B7"); grade != "1" {
+		t.Errorf("part2Grade = %q, want 1", grade)
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := testExport().Write(&bytes.Buffer{}, "pdf"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Write(pdf) error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
@@ -0,0 +1,296 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ProjectFilter schränkt die Projekte ein, die FindIpaProjects und FindIpaProjectPage zurückgeben.
+type ProjectFilter struct {
+	DateFrom        string // Frühester Abgabetag (Feld date), inklusiv, Format YYYY-MM-DD
+	DateTo          string // Spätester Abgabetag (Feld date), inklusiv, Format YYYY-MM-DD
+	Topic           string // Teilstring des Themas, Gross-/Kleinschreibung wird ignoriert
+	IncludeArchived bool
+}
+
+// query liefert die Bedingung an die Projekte.
+func (filter ProjectFilter) query() bson.M {
+	query := bson.M{}
+	if !filter.IncludeArchived {
+		query["archived"] = bson.M{"$ne": true}
+	}
+	if filter.DateFrom != "" || filter.DateTo != "" {
+		dateRange := bson.M{}
+		if filter.DateFrom != "" {
+			dateRange["$gte"] = filter.DateFrom
+		}
+		if filter.DateTo != "" {
+			dateRange["$lte"] = filter.DateTo
+		}
+		query["date"] = dateRange
+	}
+	if filter.Topic != "" {
+		query["topic"] = bson.M{"$regex": regexp.QuoteMeta(filter.Topic), "$options": "i"}
+	}
+	return query
+}
+
+// ProjectPage wählt eine sortierte Seite der Projekte aus.
+type ProjectPage struct {
+	Sort  string // Sortierfeld aus ProjectSortFields, mit "-" für absteigende Reihenfolge
+	Skip  int
+	Limit int
+}
+
+// ProjectSortFields sind die Felder, nach denen die Datenbank Projekte sortieren kann.
+var ProjectSortFields = map[string]string{
+	"id":        "publicId",
+	"firstname": "firstname",
+	"lastname":  "lastname",
+	"topic":     "topic",
+	"date":      "date", // Abgabetag, siehe MigrateLegacyDates
+}
+
+// sort liefert die Sortierung der Seite. Bei gleichem Wert entscheidet die ID, damit die Seiten
+// sich nicht überschneiden.
+func (page ProjectPage) sort() (bson.D, error) {
+	key := page.Sort
+	if key == "" {
+		key = "id"
+	}
+	field, ok := ProjectSortFields[strings.TrimPrefix(key, "-")]
+	if !ok {
+		return nil, invalid(fmt.Errorf("unknown sort field %q", strings.TrimPrefix(key, "-")))
+	}
+	direction := 1
+	if strings.HasPrefix(key, "-") {
+		direction = -1
+	}
+	sort := bson.D{{Key: field, Value: direction}}
+	if field != "publicId" {
+		sort = append(sort, bson.E{Key: "publicId", Value: direction})
+	}
+	return sort, nil
+}
+
+// projectCollation vergleicht Namen und Themen ohne Beachtung der Gross-/Kleinschreibung.
+var projectCollation = &options.Collation{Locale: "de", Strength: 2}
+
+// FindIpaProjects liefert alle Projekte, die dem Filter entsprechen.
+func (s *MongoStore) FindIpaProjects(ctx context.Context, filter ProjectFilter) (projects []models.MongoIpaProject, err error) {
+	defer observe(ctx, "FindIpaProjects", time.Now(), &err)
+	ctx, cancel := context.WithTimeout(ctx, s.bulkTimeout)
+	defer cancel()
+
+	cursor, err := s.collection.Find(ctx, filter.query())
+	if err != nil {
+		return nil, err
+	}
+	projects = make([]models.MongoIpaProject, 0)
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// FindIpaProjectPage liefert eine Seite der Projekte, die dem Filter entsprechen, und die Anzahl
+// aller passenden Projekte. Filter, Sortierung und Paginierung übernimmt die Datenbank.
+func (s *MongoStore) FindIpaProjectPage(ctx context.Context, filter ProjectFilter, page ProjectPage) (projects []models.MongoIpaProject, total int, err error) {
+	defer observe(ctx, "FindIpaProjectPage", time.Now(), &err)
+	sort, err := page.sort()
+	if err != nil {
+		return nil, 0, err
+	}
+	query := filter.query()
+
+	ctx, cancel := context.WithTimeout(ctx, s.bulkTimeout)
+	defer cancel()
+
+	count, err := s.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := options.Find().SetSort(sort).SetCollation(projectCollation).SetSkip(int64(page.Skip)).SetLimit(int64(page.Limit))
+	cursor, err := s.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	projects = make([]models.MongoIpaProject, 0, page.Limit)
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, 0, err
+	}
+	return projects, int(count), nil
+}
+
+// CountIpaProjects zählt die aktiven und archivierten Projekte.
+func (s *MongoStore) CountIpaProjects(ctx context.Context) (active, archived int64, err error) {
+	defer observe(ctx, "CountIpaProjects", time.Now(), &err)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	if archived, err = s.collection.CountDocuments(ctx, bson.M{"archived": true}); err != nil {
+		return 0, 0, err
+	}
+	total, err := s.collection.CountDocuments(ctx, bson.M{})
+	return total - archived, archived, err
+}
+
+// SetIpaProjectArchived archiviert ein Projekt oder stellt es wieder her.
+func (s *MongoStore) SetIpaProjectArchived(ctx context.Context, personId string, archived bool) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "SetIpaProjectArchived", time.Now(), &err)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return matched(s.collection.UpdateOne(ctx, projectFilter(personId), bson.M{"$set": bson.M{"archived": archived}}))
+}
+
+// DeleteIpaProject löscht ein Projekt endgültig.
+func (s *MongoStore) DeleteIpaProject(ctx context.Context, personId string) (res *mongo.DeleteResult, err error) {
+	defer observe(ctx, "DeleteIpaProject", time.Now(), &err)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	res, err = s.collection.DeleteOne(ctx, projectFilter(personId))
+	if err == nil && res.DeletedCount == 0 {
+		return res, ErrNotFound
+	}
+	return res, err
+}
+
+// SaveIpaProjects speichert mehrere Projekte auf einmal. Kommt eine ID mehrfach vor, wird nichts
+// gespeichert. Schlägt das Speichern fehl, werden die bereits gespeicherten Projekte anhand ihrer
+// _id wieder entfernt, gleichzeitig von anderen angelegte Projekte bleiben erhalten.
+func (s *MongoStore) SaveIpaProjects(ctx context.Context, projects []models.MongoIpaProject) (err error) {
+	defer observe(ctx, "SaveIpaProjects", time.Now(), &err)
+	if id, ok := duplicateID(projects); ok {
+		return fmt.Errorf("project id %s appears more than once: %w", id, ErrConflict)
+	}
+	documents := make([]any, len(projects))
+	objectIDs := make([]bson.ObjectID, len(projects))
+	for i, project := range projects {
+		objectIDs[i] = bson.NewObjectID()
+		documents[i] = insertedProject{ObjectID: objectIDs[i], MongoIpaProject: project}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.bulkTimeout)
+	defer cancel()
+
+	_, err = s.collection.InsertMany(ctx, documents)
+	if err == nil {
+		return nil
+	}
+	err = translate(err)
+
+	rollbackCtx, rollbackCancel := context.WithTimeout(context.WithoutCancel(ctx), s.bulkTimeout)
+	defer rollbackCancel()
+	if _, rollbackErr := s.collection.DeleteMany(rollbackCtx, bson.M{"_id": bson.M{"$in": objectIDs}}); rollbackErr != nil {
+		return errors.Join(err, fmt.Errorf("rollback failed: %w", rollbackErr))
+	}
+	return err
+}
+
+// insertedProject ist ein Projekt mit vorab vergebener _id, damit SaveIpaProjects genau die
+// eigenen Dokumente zurücknehmen kann.
+type insertedProject struct {
+	ObjectID               bson.ObjectID `bson:"_id"`
+	models.MongoIpaProject `bson:",inline"`
+}
+
+// duplicateID liefert die erste ID, die in projects mehrfach vorkommt.
+func duplicateID(projects []models.MongoIpaProject) (string, bool) {
+	seen := make(map[string]bool, len(projects))
+	for _, project := range projects {
+		id := common.NormalizeProjectID(project.ID)
+		if seen[id] {
+			return id, true
+		}
+		seen[id] = true
+	}
+	return "", false
+}
+
+// ExistingProjectIDs gibt zurück, welche der angegebenen IDs bereits vergeben sind.
+func (s *MongoStore) ExistingProjectIDs(ctx context.Context, ids []string) (existing map[string]bool, err error) {
+	defer observe(ctx, "ExistingProjectIDs", time.Now(), &err)
+	existing = make(map[string]bool)
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.bulkTimeout)
+	defer cancel()
+
+	values, err := s.collection.Distinct(ctx, "publicId", bson.M{"publicId": bson.M{"$in": ids}}).Raw()
+	if err != nil {
+		return nil, err
+	}
+	elements, err := values.Values()
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range elements {
+		if id, ok := value.StringValueOK(); ok {
+			existing[id] = true
+		}
+	}
+	return existing, nil
+}
+
+// GetIpaProjectsByIDs liefert die Projekte mit den angegebenen IDs. Ohne IDs werden alle Projekte geliefert.
+func (s *MongoStore) GetIpaProjectsByIDs(ctx context.Context, personIds []string) (projects []models.MongoIpaProject, err error) {
+	defer observe(ctx, "GetIpaProjectsByIDs", time.Now(), &err)
+	query := bson.M{}
+	if len(personIds) > 0 {
+		ids := make([]string, len(personIds))
+		for i, personId := range personIds {
+			ids[i] = common.NormalizeProjectID(personId)
+		}
+		query["publicId"] = bson.M{"$in": ids}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.bulkTimeout)
+	defer cancel()
+
+	cursor, err := s.collection.Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	projects = make([]models.MongoIpaProject, 0)
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// MigrateLegacyDates setzt das Feld date bei Projekten mit IPA-Zeitraum auf den Abgabetag.
+// Frühere Versionen übernahmen den Starttag oder liessen date beim Ändern des Zeitraums
+// unverändert, danach filterte und sortierte die Projektliste nach einem veralteten Tag.
+func (s *MongoStore) MigrateLegacyDates(ctx context.Context) (err error) {
+	defer observe(ctx, "MigrateLegacyDates", time.Now(), &err)
+	ctx, cancel := context.WithTimeout(ctx, s.bulkTimeout)
+	defer cancel()
+
+	filter := bson.M{
+		"endDate": bson.M{"$nin": bson.A{"", nil}},
+		"$expr":   bson.M{"$ne": bson.A{"$date", "$endDate"}},
+	}
+	update := bson.A{bson.M{"$set": bson.M{"date": "$endDate"}}}
+	res, err := s.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount > 0 {
+		common.Logger(ctx).Info("legacy project dates migrated", "count", res.ModifiedCount)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestDuplicateID(t *testing.T) {
+	projects := []models.MongoIpaProject{{ID: "K7QX2M3"}, {ID: "B4TR8N5"}}
+	if id, ok := duplicateID(projects); ok {
+		t.Errorf("duplicateID() = %s for distinct ids", id)
+	}
+	projects = append(projects, models.MongoIpaProject{ID: "k7qx2m3"})
+	if id, ok := duplicateID(projects); !ok || id != "K7QX2M3" {
+		t.Errorf("duplicateID() = %q, %v, want K7QX2M3", id, ok)
+	}
+}
+
+func TestInsertedProjectKeepsFieldsAtTopLevel(t *testing.T) {
+	objectID := bson.NewObjectID()
+	data, err := bson.Marshal(insertedProject{ObjectID: objectID, MongoIpaProject: models.MongoIpaProject{ID: "K7QX2M3", Topic: "Webshop"}})
+	if err != nil {
+		t.Fatalf("bson.Marshal() = %v", err)
+	}
+	raw := bson.Raw(data)
+	if got, ok := raw.Lookup("_id").ObjectIDOK(); !ok || got != objectID {
+		t.Errorf("_id = %v, want %v", raw.Lookup("_id"), objectID)
+	}
+	if raw.Lookup("publicId").StringValue() != "K7QX2M3" || raw.Lookup("topic").StringValue() != "Webshop" {
+		t.Errorf("project fields are not inlined: %s", raw)
+	}
+}
+
+func TestSaveIpaProjectsRejectsDuplicateIDs(t *testing.T) {
+	// Die Prüfung erfolgt vor dem ersten Zugriff auf die Datenbank
+	s := &MongoStore{}
+	err := s.SaveIpaProjects(context.Background(), []models.MongoIpaProject{{ID: "K7QX2M3"}, {ID: "K7QX2M3"}})
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("SaveIpaProjects() with duplicate ids = %v, want ErrConflict", err)
+	}
+}
+
+func TestSaveIpaProjectsRollbackKeepsForeignProjects(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	// Ein anderer Benutzer legt gleichzeitig ein Projekt mit der ID an, die der Import ebenfalls verwendet
+	saveTestProject(t, s, models.MongoIpaProject{Topic: "fremd"})
+
+	err := s.SaveIpaProjects(ctx, []models.MongoIpaProject{{ID: "B4TR8N5"}, {ID: testProjectID}})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("SaveIpaProjects() = %v, want ErrConflict", err)
+	}
+	if _, err := s.GetIpaProject(ctx, "B4TR8N5"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("inserted project was not rolled back: %v", err)
+	}
+	foreign, err := s.GetIpaProject(ctx, testProjectID)
+	if err != nil || foreign.Topic != "fremd" {
+		t.Errorf("foreign project was removed by the rollback: %+v, %v", foreign, err)
+	}
+}
+
+func TestProjectFilterQuery(t *testing.T) {
+	got := ProjectFilter{DateFrom: "2026-01-01", Topic: "Web.shop"}.query()
+	want := bson.M{
+		"archived": bson.M{"$ne": true},
+		"date":     bson.M{"$gte": "2026-01-01"},
+		"topic":    bson.M{"$regex": `Web\.shop`, "$options": "i"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("query() = %v, want %v", got, want)
+	}
+	if got := (ProjectFilter{IncludeArchived: true}).query(); len(got) != 0 {
+		t.Errorf("query() with archived projects = %v, want no condition", got)
+	}
+}
+
+func TestProjectPageSort(t *testing.T) {
+	tests := []struct {
+		sort string
+		want bson.D
+	}{
+		{"", bson.D{{Key: "publicId", Value: 1}}},
+		{"-id", bson.D{{Key: "publicId", Value: -1}}},
+		{"lastname", bson.D{{Key: "lastname", Value: 1}, {Key: "publicId", Value: 1}}},
+		{"-date", bson.D{{Key: "date", Value: -1}, {Key: "publicId", Value: -1}}},
+	}
+	for _, tt := range tests {
+		got, err := ProjectPage{Sort: tt.sort}.sort()
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sort(%q) = %v, %v, want %v", tt.sort, got, err, tt.want)
+		}
+	}
+	// Die Note wird erst nach dem Laden berechnet
+	if _, err := (ProjectPage{Sort: "-grade"}).sort(); !errors.Is(err, ErrValidation) {
+		t.Errorf("sort(-grade) = %v, want ErrValidation", err)
+	}
+}
+
+func TestFindIpaProjectPage(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	projects := []models.MongoIpaProject{
+		{ID: "AA00", Lastname: "meier", Topic: "Webshop", Date: "2026-05-04"},
+		{ID: "AA01", Lastname: "Ammann", Topic: "REST API", Date: "2026-05-04"},
+		{ID: "AA02", Lastname: "Meier", Topic: "Webshop", Date: "2026-05-11"},
+		{ID: "AA03", Lastname: "Zürcher", Topic: "Webshop", Date: "2026-05-04", Archived: true},
+	}
+	if err := s.SaveIpaProjects(ctx, projects); err != nil {
+		t.Fatalf("SaveIpaProjects() = %v", err)
+	}
+
+	got, total, err := s.FindIpaProjectPage(ctx, ProjectFilter{Topic: "web"}, ProjectPage{Sort: "-lastname", Skip: 1, Limit: 1})
+	if err != nil || total != 2 || len(got) != 1 || got[0].ID != "AA00" {
+		t.Errorf("FindIpaProjectPage() = %v, %d, %v, want AA00 of 2", got, total, err)
+	}
+	got, total, err = s.FindIpaProjectPage(ctx, ProjectFilter{IncludeArchived: true}, ProjectPage{Sort: "lastname", Limit: 10})
+	if err != nil || total != 4 || len(got) != 4 || got[0].ID != "AA01" || got[1].ID != "AA00" || got[2].ID != "AA02" {
+		t.Errorf("FindIpaProjectPage() = %v, %d, %v, want case-insensitive order with id as tie-breaker", got, total, err)
+	}
+}
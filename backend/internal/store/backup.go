@@ -0,0 +1,174 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ExportCollection liefert alle Dokumente einer Collection unverändert als BSON.
+func (s *MongoStore) ExportCollection(ctx context.Context, name string) (documents []bson.Raw, err error) {
+	defer observe(ctx, "ExportCollection", time.Now(), &err)
+	ctx, cancel := context.WithTimeout(ctx, s.bulkTimeout)
+	defer cancel()
+
+	cursor, err := s.db.Collection(name).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	documents = make([]bson.Raw, 0)
+	for cursor.Next(ctx) {
+		documents = append(documents, append(bson.Raw(nil), cursor.Current...))
+	}
+	return documents, cursor.Err()
+}
+
+// ReplaceCollection ersetzt den gesamten Inhalt einer Collection durch die angegebenen Dokumente.
+// Die Dokumente werden zuerst mit den Indizes der Collection in eine temporäre Collection
+// geschrieben, die danach die bisherige in einem Schritt ersetzt. Schlägt das Schreiben fehl,
+// bleibt die Collection unverändert.
+func (s *MongoStore) ReplaceCollection(ctx context.Context, name string, documents []bson.Raw) (err error) {
+	defer observe(ctx, "ReplaceCollection", time.Now(), &err)
+	ctx, cancel := context.WithTimeout(ctx, s.bulkTimeout)
+	defer cancel()
+
+	temporary := s.db.Collection(name + restoreSuffix + rand.Text()[:8])
+	if err := s.fillCollection(ctx, temporary, name, documents); err != nil {
+		dropCtx, dropCancel := context.WithTimeout(context.WithoutCancel(ctx), s.bulkTimeout)
+		defer dropCancel()
+		if dropErr := temporary.Drop(dropCtx); dropErr != nil {
+			return errors.Join(err, fmt.Errorf("dropping %s: %w", temporary.Name(), dropErr))
+		}
+		return err
+	}
+	return nil
+}
+
+// restoreSuffix kennzeichnet die temporären Collections von ReplaceCollection.
+const restoreSuffix = ".restore-"
+
+// fillCollection legt temporary mit den Indizes der Collection name an, schreibt die Dokumente
+// hinein und benennt sie anschliessend in name um. Die bisherige Collection wird dabei gelöscht.
+func (s *MongoStore) fillCollection(ctx context.Context, temporary *mongo.Collection, name string, documents []bson.Raw) error {
+	if err := s.db.CreateCollection(ctx, temporary.Name()); err != nil {
+		return fmt.Errorf("creating %s: %w", temporary.Name(), err)
+	}
+	indexes, err := s.indexSpecifications(ctx, name)
+	if err != nil {
+		return fmt.Errorf("reading indexes of %s: %w", name, err)
+	}
+	if len(indexes) > 0 {
+		command := bson.D{{Key: "createIndexes", Value: temporary.Name()}, {Key: "indexes", Value: indexes}}
+		if err := s.db.RunCommand(ctx, command).Err(); err != nil {
+			return fmt.Errorf("copying indexes of %s: %w", name, err)
+		}
+	}
+	if len(documents) > 0 {
+		items := make([]any, len(documents))
+		for i, document := range documents {
+			items[i] = document
+		}
+		if _, err := temporary.InsertMany(ctx, items); err != nil {
+			return fmt.Errorf("restoring %s: %w", name, err)
+		}
+	}
+	rename := bson.D{
+		{Key: "renameCollection", Value: s.db.Name() + "." + temporary.Name()},
+		{Key: "to", Value: s.db.Name() + "." + name},
+		{Key: "dropTarget", Value: true},
+	}
+	if err := s.client.Database("admin").RunCommand(ctx, rename).Err(); err != nil {
+		return fmt.Errorf("replacing %s: %w", name, err)
+	}
+	return nil
+}
+
+// indexSpecifications liefert die Indizes der Collection name ohne den Index auf _id, so wie sie
+// createIndexes erwartet. Existiert die Collection nicht, ist das Ergebnis leer.
+func (s *MongoStore) indexSpecifications(ctx context.Context, name string) (bson.A, error) {
+	cursor, err := s.db.Collection(name).Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	indexes := bson.A{}
+	for cursor.Next(ctx) {
+		var index bson.D
+		if err := bson.Unmarshal(cursor.Current, &index); err != nil {
+			return nil, err
+		}
+		if spec := indexSpecification(index); spec != nil {
+			indexes = append(indexes, spec)
+		}
+	}
+	return indexes, cursor.Err()
+}
+
+// indexSpecification entfernt aus der Beschreibung eines Index die Felder, die createIndexes
+// nicht annimmt. Für den Index auf _id, den jede Collection erhält, ist das Ergebnis nil.
+func indexSpecification(index bson.D) bson.D {
+	spec := make(bson.D, 0, len(index))
+	for _, field := range index {
+		switch field.Key {
+		case "name":
+			if field.Value == "_id_" {
+				return nil
+			}
+		case "v", "ns":
+			continue
+		}
+		spec = append(spec, field)
+	}
+	return spec
+}
+
+// ReplaceIpaProjectDocument ersetzt ein einzelnes Projekt (anhand der ID im Dokument)
+// oder legt es neu an, falls es nicht mehr existiert. Dokumente aus der Zeit vor
+// den zufälligen Projekt-IDs erhalten dabei ihre lesbare ID.
+func (s *MongoStore) ReplaceIpaProjectDocument(ctx context.Context, document bson.Raw) (err error) {
+	defer observe(ctx, "ReplaceIpaProjectDocument", time.Now(), &err)
+	// _id ist unveränderlich und kann sich unterscheiden, wenn das Projekt inzwischen neu angelegt wurde
+	var fields bson.D
+	if err := bson.Unmarshal(document, &fields); err != nil {
+		return err
+	}
+	replacement := make(bson.D, 0, len(fields)+1)
+	var publicID string
+	for _, field := range fields {
+		switch field.Key {
+		case "_id":
+			continue
+		case "publicId":
+			publicID, _ = field.Value.(string)
+		}
+		replacement = append(replacement, field)
+	}
+	if publicID == "" {
+		legacyID, err := document.LookupErr("id")
+		if err != nil {
+			return fmt.Errorf("document has no id: %w", err)
+		}
+		n, ok := legacyID.AsInt64OK()
+		if !ok {
+			return fmt.Errorf("document has an invalid id")
+		}
+		publicID = common.FormatProjectID(int(n))
+		replacement = append(replacement, bson.E{Key: "publicId", Value: publicID})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	_, err = s.collection.ReplaceOne(ctx, bson.M{"publicId": publicID}, replacement, options.Replace().SetUpsert(true))
+	return err
+}
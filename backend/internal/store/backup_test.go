@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestIndexSpecification(t *testing.T) {
+	if spec := indexSpecification(bson.D{{Key: "v", Value: int32(2)}, {Key: "key", Value: bson.D{{Key: "_id", Value: int32(1)}}}, {Key: "name", Value: "_id_"}}); spec != nil {
+		t.Errorf("indexSpecification(_id_) = %v, want nil", spec)
+	}
+	index := bson.D{
+		{Key: "v", Value: int32(2)},
+		{Key: "key", Value: bson.D{{Key: "publicId", Value: int32(1)}}},
+		{Key: "name", Value: "publicId_1"},
+		{Key: "ns", Value: "criteria-catalogue.user-data"},
+		{Key: "unique", Value: true},
+	}
+	want := bson.D{{Key: "key", Value: bson.D{{Key: "publicId", Value: int32(1)}}}, {Key: "name", Value: "publicId_1"}, {Key: "unique", Value: true}}
+	if spec := indexSpecification(index); !reflect.DeepEqual(spec, want) {
+		t.Errorf("indexSpecification() = %v, want %v", spec, want)
+	}
+}
+
+func projectDocument(t *testing.T, id, topic string) bson.Raw {
+	t.Helper()
+	data, err := bson.Marshal(bson.D{{Key: "_id", Value: bson.NewObjectID()}, {Key: "publicId", Value: id}, {Key: "topic", Value: topic}})
+	if err != nil {
+		t.Fatalf("bson.Marshal() = %v", err)
+	}
+	return data
+}
+
+func TestReplaceCollection(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	if err := s.ReplaceCollection(ctx, ProjectsCollection, []bson.Raw{projectDocument(t, "K7QX2M3", "alt")}); err != nil {
+		t.Fatalf("ReplaceCollection() = %v", err)
+	}
+	if err := s.ReplaceCollection(ctx, ProjectsCollection, []bson.Raw{projectDocument(t, "B4TR8N5", "neu")}); err != nil {
+		t.Fatalf("ReplaceCollection() = %v", err)
+	}
+	documents, err := s.ExportCollection(ctx, ProjectsCollection)
+	if err != nil || len(documents) != 1 || documents[0].Lookup("publicId").StringValue() != "B4TR8N5" {
+		t.Errorf("ExportCollection() = %v, %v, want only B4TR8N5", documents, err)
+	}
+	// Der eindeutige Index auf publicId bleibt erhalten
+	if _, err := s.collection.InsertOne(ctx, projectDocument(t, "B4TR8N5", "doppelt")); !mongo.IsDuplicateKeyError(err) {
+		t.Errorf("InsertOne() of duplicate publicId = %v, want duplicate key error", err)
+	}
+}
+
+func TestReplaceCollectionKeepsDataOnFailure(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	saveTestProject(t, s, models.MongoIpaProject{Topic: "bestehend"})
+
+	// Der kopierte eindeutige Index lässt das Schreiben nach dem ersten Dokument scheitern
+	documents := []bson.Raw{projectDocument(t, "B4TR8N5", "neu"), projectDocument(t, "B4TR8N5", "doppelt")}
+	if err := s.ReplaceCollection(ctx, ProjectsCollection, documents); err == nil {
+		t.Fatal("ReplaceCollection() with duplicate publicId succeeded")
+	}
+
+	project, err := s.GetIpaProject(ctx, testProjectID)
+	if err != nil || project.Topic != "bestehend" {
+		t.Errorf("existing project after failed restore = %+v, %v", project, err)
+	}
+	if _, err := s.GetIpaProject(ctx, "B4TR8N5"); err == nil {
+		t.Error("documents of the failed restore are visible")
+	}
+	names, err := s.db.ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		t.Fatalf("ListCollectionNames() = %v", err)
+	}
+	for _, name := range names {
+		if strings.Contains(name, restoreSuffix) {
+			t.Errorf("temporary collection %s was not dropped", name)
+		}
+	}
+}
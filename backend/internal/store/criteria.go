@@ -1,56 +1,316 @@
 package store
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/metrics"
 	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/search"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/selection"
 )
 
+// CriteriaStore hält den Kriterienkatalog. Der Katalog kann im laufenden Betrieb
+// neu geladen werden, alle Zugriffe sind deshalb synchronisiert.
 type CriteriaStore struct {
-	AllCriteria       []models.Criterion
-	MandatoryCriteria []models.Criterion
+	path string
+
+	mu         sync.RWMutex
+	version    string
+	catalogues map[i18n.Lang]catalogue       // Katalog in jeder unterstützten Sprache
+	byID       map[string]catalogueCriterion // Mehrsprachige Kriterien für LocalizeCriteria
+}
+
+// catalogue ist der Katalog in einer Sprache.
+type catalogue struct {
+	allCriteria       []models.Criterion
+	mandatoryCriteria []models.Criterion
+	selectionRules    []models.SelectionRule
+	index             *search.Index // Suchindex über die Texte in dieser Sprache
+}
+
+// catalogueFile ist das Format der Kriteriendatei. Aus Kompatibilitätsgründen
+// wird auch eine Datei akzeptiert, die nur das Array der Kriterien enthält.
+// Alle Texte sind entweder Strings in der Standardsprache oder Objekte mit einer
+// Übersetzung pro Sprache, z.B. {"de": "Titel", "fr": "Titre", "it": "Titolo"}.
+type catalogueFile struct {
+	Version        string               `json:"version"`
+	SelectionRules []catalogueRule      `json:"selectionRules"`
+	Criteria       []catalogueCriterion `json:"criteria"`
+}
+
+type catalogueRule struct {
+	Description i18n.Text `json:"description"`
+	Categories  []string  `json:"categories"`
+	Min         int       `json:"min"`
+	Max         int       `json:"max"`
+}
+
+type catalogueCriterion struct {
+	ID            string                           `json:"id"`
+	Title         i18n.Text                        `json:"title"`
+	Question      i18n.Text                        `json:"question"`
+	Requirements  []i18n.Text                      `json:"requirements"`
+	QualityLevels map[string]catalogueQualityLevel `json:"qualityLevels"`
+}
+
+type catalogueQualityLevel struct {
+	Description     i18n.Text `json:"description"`
+	MinRequirements int       `json:"minRequirements"`
+	RequiredIndexes []int     `json:"requiredIndexes"`
+}
+
+// check prüft, ob alle Texte des Kriteriums nur unterstützte Sprachen enthalten.
+func (c catalogueCriterion) check() error {
+	texts := append([]i18n.Text{c.Title, c.Question}, c.Requirements...)
+	for _, level := range c.QualityLevels {
+		texts = append(texts, level.Description)
+	}
+	for _, text := range texts {
+		if err := text.Check(); err != nil {
+			return fmt.Errorf("criterion %s: %w", c.ID, err)
+		}
+	}
+	return nil
+}
+
+// in liefert das Kriterium in lang.
+func (c catalogueCriterion) in(lang i18n.Lang) (models.Criterion, error) {
+	criterion := models.Criterion{
+		ID:       c.ID,
+		Title:    c.Title.In(lang),
+		Question: c.Question.In(lang),
+	}
+	if c.Requirements != nil {
+		criterion.Requirements = make([]string, len(c.Requirements))
+		for i, requirement := range c.Requirements {
+			criterion.Requirements[i] = requirement.In(lang)
+		}
+	}
+	if c.QualityLevels != nil {
+		criterion.QualityLevels = make(map[string]models.QualityLevel, len(c.QualityLevels))
+		for key, level := range c.QualityLevels {
+			criterion.QualityLevels[key] = models.QualityLevel{
+				Description:     level.Description.In(lang),
+				MinRequirements: level.MinRequirements,
+				RequiredIndexes: level.RequiredIndexes,
+			}
+		}
+	}
+	err := models.SetCriterionDefaultValuesIfMissing(&criterion)
+	return criterion, err
 }
 
 // NewStore erstellt und initialisiert einen neuen Store.
 func NewCriteriaStore(cfg common.Config) (*CriteriaStore, error) {
-	var rawCriteria []models.Criterion
+	s := &CriteriaStore{path: cfg.CriteriaFilePath}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload lädt die Kriteriendatei neu. Ist die Datei ungültig, bleibt der
+// bisherige Katalog aktiv.
+func (s *CriteriaStore) Reload() error {
+	err := s.load()
+	metrics.ObserveCatalogueReload(err == nil)
+	return err
+}
+
+func (s *CriteriaStore) load() error {
+	var file catalogueFile
 
 	// Lade Kriterien aus der JSON-Datei
-	file, err := os.ReadFile(cfg.CriteriaFilePath)
+	data, err := os.ReadFile(s.path)
 	if err != nil {
-		return nil, fmt.Errorf("kann Kriteriendatei nicht lesen: %w", err)
+		return fmt.Errorf("kann Kriteriendatei nicht lesen: %w", err)
 	}
-	if err := json.Unmarshal(file, &rawCriteria); err != nil {
-		return nil, fmt.Errorf("kann Kriterien-JSON nicht parsen: %w", err)
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(data, &file.Criteria)
+	} else {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return invalid(fmt.Errorf("kann Kriterien-JSON nicht parsen: %w", err))
 	}
 
-	s := &CriteriaStore{
-		AllCriteria: make([]models.Criterion, 0, len(rawCriteria)),
+	byID := make(map[string]catalogueCriterion, len(file.Criteria))
+	for _, criterion := range file.Criteria {
+		if err := criterion.check(); err != nil {
+			return invalid(err)
+		}
+		byID[criterion.ID] = criterion
+	}
+	for i, rule := range file.SelectionRules {
+		if err := rule.Description.Check(); err != nil {
+			return invalid(fmt.Errorf("selection rule %d: %w", i, err))
+		}
 	}
 
-	for _, criterion := range rawCriteria {
-		err := models.SetCriterionDefaultValuesIfMissing(&criterion)
-		if err != nil {
-			return nil, err
+	catalogues := make(map[i18n.Lang]catalogue, len(i18n.Supported))
+	for _, lang := range i18n.Supported {
+		localized := catalogue{
+			allCriteria:    make([]models.Criterion, 0, len(file.Criteria)),
+			selectionRules: make([]models.SelectionRule, len(file.SelectionRules)), // Ältere Kataloge haben keine Auswahlregeln
+		}
+		for i, rule := range file.SelectionRules {
+			localized.selectionRules[i] = models.SelectionRule{
+				Description: rule.Description.In(lang),
+				Categories:  rule.Categories,
+				Min:         rule.Min,
+				Max:         rule.Max,
+			}
 		}
-		s.AllCriteria = append(s.AllCriteria, criterion)
-		if common.IsMandatoryCriterion(criterion.ID) {
-			s.MandatoryCriteria = append(s.MandatoryCriteria, criterion)
+		if err := selection.ValidateRules(localized.selectionRules); err != nil {
+			return invalid(fmt.Errorf("ungültige Auswahlregeln: %w", err))
 		}
+		for _, source := range file.Criteria {
+			criterion, err := source.in(lang)
+			if err != nil {
+				return invalid(err)
+			}
+			localized.allCriteria = append(localized.allCriteria, criterion)
+			if common.IsMandatoryCriterion(criterion.ID) {
+				localized.mandatoryCriteria = append(localized.mandatoryCriteria, criterion)
+			}
+		}
+		localized.index = search.NewIndex(localized.allCriteria)
+		catalogues[lang] = localized
 	}
 
-	return s, nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = file.Version
+	s.catalogues = catalogues
+	s.byID = byID
+	return nil
+}
+
+// Validate prüft, ob ein gültiger Katalog geladen ist.
+func (s *CriteriaStore) Validate() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	defaultCatalogue := s.catalogues[i18n.Default]
+	if len(defaultCatalogue.allCriteria) == 0 {
+		return errors.New("no criteria loaded")
+	}
+	if len(defaultCatalogue.mandatoryCriteria) == 0 {
+		return errors.New("no mandatory criteria loaded")
+	}
+	return selection.ValidateRules(defaultCatalogue.selectionRules)
+}
+
+// GetVersion gibt die Version des Katalogs zurück.
+func (s *CriteriaStore) GetVersion() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
+// GetAllCriteria gibt alle Kriterien in lang zurück.
+func (s *CriteriaStore) GetAllCriteria(lang i18n.Lang) []models.Criterion {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.catalogues[lang].allCriteria
+}
+
+// GetMandatoryCriteria gibt alle Pflichtkriterien in lang zurück. Neue Projekte werden mit
+// den Pflichtkriterien in der Standardsprache gespeichert.
+func (s *CriteriaStore) GetMandatoryCriteria(lang i18n.Lang) []models.Criterion {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.catalogues[lang].mandatoryCriteria
+}
+
+// GetSelectionRules gibt die Auswahlregeln für optionale Kriterien in lang zurück.
+func (s *CriteriaStore) GetSelectionRules(lang i18n.Lang) []models.SelectionRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.catalogues[lang].selectionRules
 }
 
-// GetAllCriteria gibt alle Kriterien zurück.
-func (s *CriteriaStore) GetAllCriteria() []models.Criterion {
-	return s.AllCriteria
+// SearchCriteria durchsucht den Katalog in lang und liefert höchstens limit Treffer.
+func (s *CriteriaStore) SearchCriteria(query string, lang i18n.Lang, limit int) []models.SearchHit {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.catalogues[lang].index.Search(query, limit)
 }
 
-// GetMandatoryCriteria gibt alle Pflichtkriterien zurück.
-func (s *CriteriaStore) GetMandatoryCriteria() []models.Criterion {
-	return s.MandatoryCriteria
+// LocalizeCriteria übersetzt die Kriterien eines Projekts in lang. Projekte speichern die Texte
+// in der Standardsprache; Kriterien aus dem Katalog erhalten die Übersetzungen des aktuellen
+// Katalogs, eigene Kriterien und fehlende Übersetzungen bleiben unverändert.
+func (s *CriteriaStore) LocalizeCriteria(criteria []models.Criterion, lang i18n.Lang) []models.Criterion {
+	if lang == i18n.Default || criteria == nil {
+		return criteria
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	localized := make([]models.Criterion, len(criteria))
+	for i, criterion := range criteria {
+		localized[i] = criterion
+		if source, ok := s.byID[criterion.ID]; ok {
+			localized[i] = translateCriterion(criterion, source, func(text i18n.Text, stored string) string {
+				if translation := text[lang]; translation != "" {
+					return translation
+				}
+				return stored
+			})
+		}
+	}
+	return localized
+}
+
+// CanonicalCriterion macht LocalizeCriteria für ein Kriterium rückgängig, das ein Client in lang
+// erhalten hat und zurücksendet: Texte, die der Übersetzung im Katalog entsprechen, werden durch
+// den Text in der Standardsprache ersetzt, damit Projekte nur Texte in der Standardsprache speichern.
+func (s *CriteriaStore) CanonicalCriterion(criterion models.Criterion, lang i18n.Lang) models.Criterion {
+	if lang == i18n.Default {
+		return criterion
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	source, ok := s.byID[criterion.ID]
+	if !ok {
+		return criterion
+	}
+	return translateCriterion(criterion, source, func(text i18n.Text, received string) string {
+		if translation := text[lang]; translation != "" && translation == received {
+			return text[i18n.Default]
+		}
+		return received
+	})
+}
+
+// translateCriterion ersetzt jeden Text des Kriteriums durch translate(Katalogtext, bisheriger Text).
+// Anforderungen werden nur ersetzt, wenn ihre Anzahl mit dem Katalog übereinstimmt.
+func translateCriterion(criterion models.Criterion, source catalogueCriterion, translate func(text i18n.Text, current string) string) models.Criterion {
+	criterion.Title = translate(source.Title, criterion.Title)
+	criterion.Question = translate(source.Question, criterion.Question)
+	if len(criterion.Requirements) == len(source.Requirements) {
+		requirements := make([]string, len(criterion.Requirements))
+		for i, requirement := range criterion.Requirements {
+			requirements[i] = translate(source.Requirements[i], requirement)
+		}
+		criterion.Requirements = requirements
+	}
+	if criterion.QualityLevels != nil {
+		levels := make(map[string]models.QualityLevel, len(criterion.QualityLevels))
+		for key, level := range criterion.QualityLevels {
+			if sourceLevel, ok := source.QualityLevels[key]; ok {
+				level.Description = translate(sourceLevel.Description, level.Description)
+			}
+			levels[key] = level
+		}
+		criterion.QualityLevels = levels
+	}
+	return criterion
 }
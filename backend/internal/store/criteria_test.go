@@ -0,0 +1,202 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/i18n"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+func writeCatalogue(t *testing.T, content string) common.Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "criteria.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing catalogue: %v", err)
+	}
+	return common.Config{CriteriaFilePath: path}
+}
+
+func TestNewCriteriaStore(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		wantErr       bool
+		wantCriteria  int
+		wantMandatory int
+		wantRules     int
+		wantVersion   string
+	}{
+		{"legacy array",
+			`[{"id":"A01","qualityLevels":{}},{"id":"B02","qualityLevels":{}}]`,
+			false, 2, 1, 0, ""},
+		{"catalogue object",
+			`{"version":"1","selectionRules":[{"categories":["B"],"min":1}],"criteria":[{"id":"Doc01","qualityLevels":{}}]}`,
+			false, 1, 1, 1, "1"},
+		{"invalid selection rule",
+			`{"selectionRules":[{"categories":[],"min":1}],"criteria":[]}`,
+			true, 0, 0, 0, ""},
+		{"missing quality levels",
+			`[{"id":"A01"}]`,
+			true, 0, 0, 0, ""},
+		{"invalid json", `{`, true, 0, 0, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewCriteriaStore(writeCatalogue(t, tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCriteriaStore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(s.GetAllCriteria(i18n.Default)) != tt.wantCriteria ||
+				len(s.GetMandatoryCriteria(i18n.Default)) != tt.wantMandatory ||
+				len(s.GetSelectionRules(i18n.Default)) != tt.wantRules ||
+				s.GetVersion() != tt.wantVersion {
+				t.Errorf("NewCriteriaStore() = %d criteria, version %q", len(s.GetAllCriteria(i18n.Default)), s.GetVersion())
+			}
+		})
+	}
+}
+
+func TestNewCriteriaStoreLoadsShippedCatalogue(t *testing.T) {
+	s, err := NewCriteriaStore(common.Config{CriteriaFilePath: "../../criteria.json"})
+	if err != nil {
+		t.Fatalf("NewCriteriaStore() error = %v", err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if len(s.GetAllCriteria(i18n.Default)) == 0 || len(s.GetSelectionRules(i18n.Default)) == 0 {
+		t.Errorf("shipped catalogue has %d criteria and %d selection rules", len(s.GetAllCriteria(i18n.Default)), len(s.GetSelectionRules(i18n.Default)))
+	}
+}
+
+func TestCriteriaStoreReload(t *testing.T) {
+	cfg := writeCatalogue(t, `{"version":"1","criteria":[{"id":"A01","qualityLevels":{}}]}`)
+	s, err := NewCriteriaStore(cfg)
+	if err != nil {
+		t.Fatalf("NewCriteriaStore() error = %v", err)
+	}
+
+	if err := os.WriteFile(cfg.CriteriaFilePath, []byte(`{"version":"2","criteria":[{"id":"A01","qualityLevels":{}},{"id":"B01","qualityLevels":{}}]}`), 0o600); err != nil {
+		t.Fatalf("writing catalogue: %v", err)
+	}
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if s.GetVersion() != "2" || len(s.GetAllCriteria(i18n.Default)) != 2 {
+		t.Errorf("Reload() loaded version %q with %d criteria", s.GetVersion(), len(s.GetAllCriteria(i18n.Default)))
+	}
+
+	// Eine defekte Datei darf den geladenen Katalog nicht ersetzen
+	if err := os.WriteFile(cfg.CriteriaFilePath, []byte(`{`), 0o600); err != nil {
+		t.Fatalf("writing catalogue: %v", err)
+	}
+	if err := s.Reload(); err == nil {
+		t.Error("Reload() accepted invalid catalogue")
+	}
+	if s.GetVersion() != "2" || len(s.GetAllCriteria(i18n.Default)) != 2 {
+		t.Errorf("failed Reload() replaced catalogue with version %q", s.GetVersion())
+	}
+}
+
+const localizedCatalogue = `{
+	"version": "1",
+	"selectionRules": [{"description": {"de": "Genau ein B", "fr": "Exactement un B"}, "categories": ["B"], "min": 1, "max": 1}],
+	"criteria": [{
+		"id": "A01",
+		"title": {"de": "Auftragsanalyse", "fr": "Analyse de la mission", "it": "Analisi del mandato"},
+		"question": {"de": "Wie erfolgt die Analyse?", "fr": "Comment l'analyse est-elle faite ?"},
+		"requirements": [{"de": "Analysiert", "fr": "Analysé"}, "Dokumentiert"],
+		"qualityLevels": {"3": {"description": {"de": "Alle Punkte", "it": "Tutti i punti"}, "minRequirements": 2}, "0": {"description": "Keine"}}
+	}]
+}`
+
+func TestCriteriaStoreLanguages(t *testing.T) {
+	s, err := NewCriteriaStore(writeCatalogue(t, localizedCatalogue))
+	if err != nil {
+		t.Fatalf("NewCriteriaStore() error = %v", err)
+	}
+
+	french := s.GetAllCriteria(i18n.French)[0]
+	if french.Title != "Analyse de la mission" || french.Question != "Comment l'analyse est-elle faite ?" ||
+		french.Requirements[0] != "Analysé" || french.Requirements[1] != "Dokumentiert" ||
+		french.QualityLevels["3"].Description != "Alle Punkte" || french.QualityLevels["3"].MinRequirements != 2 {
+		t.Errorf("GetAllCriteria(fr) = %+v", french)
+	}
+	italian := s.GetMandatoryCriteria(i18n.Italian)[0]
+	if italian.Title != "Analisi del mandato" || italian.Question != "Wie erfolgt die Analyse?" || italian.QualityLevels["3"].Description != "Tutti i punti" {
+		t.Errorf("GetMandatoryCriteria(it) = %+v", italian)
+	}
+	if got := s.GetSelectionRules(i18n.French)[0].Description; got != "Exactement un B" {
+		t.Errorf("GetSelectionRules(fr) description = %q", got)
+	}
+	if got := s.GetAllCriteria(i18n.German)[0].Title; got != "Auftragsanalyse" {
+		t.Errorf("GetAllCriteria(de) title = %q", got)
+	}
+}
+
+func TestCriteriaStoreRejectsUnknownLanguages(t *testing.T) {
+	_, err := NewCriteriaStore(writeCatalogue(t, `[{"id": "A01", "title": {"de": "Titel", "en": "Title"}, "qualityLevels": {}}]`))
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("NewCriteriaStore() error = %v, want ErrValidation", err)
+	}
+}
+
+func TestLocalizeCriteria(t *testing.T) {
+	s, err := NewCriteriaStore(writeCatalogue(t, localizedCatalogue))
+	if err != nil {
+		t.Fatalf("NewCriteriaStore() error = %v", err)
+	}
+	stored := []models.Criterion{
+		s.GetAllCriteria(i18n.German)[0],
+		{ID: "X01", Title: "Eigenes Kriterium", Requirements: []string{"Eigene Anforderung"}},
+	}
+	stored[0].Checked = []int{0}
+
+	localized := s.LocalizeCriteria(stored, i18n.French)
+	if localized[0].Title != "Analyse de la mission" || localized[0].Requirements[0] != "Analysé" || localized[0].Checked[0] != 0 {
+		t.Errorf("LocalizeCriteria() catalogue criterion = %+v", localized[0])
+	}
+	if localized[1].Title != "Eigenes Kriterium" {
+		t.Errorf("LocalizeCriteria() changed own criterion: %+v", localized[1])
+	}
+	if stored[0].Title != "Auftragsanalyse" || stored[0].Requirements[0] != "Analysiert" {
+		t.Errorf("LocalizeCriteria() modified its input: %+v", stored[0])
+	}
+	if s.LocalizeCriteria(nil, i18n.French) != nil {
+		t.Error("LocalizeCriteria(nil) != nil")
+	}
+
+	// Ein Client sendet das französische Kriterium mit geänderter Notiz und eigenem Titel zurück
+	received := localized[0]
+	received.Notes = "Notiz"
+	received.Requirements = []string{"Analysé", "Propre exigence"}
+	canonical := s.CanonicalCriterion(received, i18n.French)
+	if canonical.Title != "Auftragsanalyse" || canonical.Question != "Wie erfolgt die Analyse?" ||
+		canonical.Requirements[0] != "Analysiert" || canonical.Requirements[1] != "Propre exigence" ||
+		canonical.QualityLevels["3"].Description != "Alle Punkte" || canonical.Notes != "Notiz" {
+		t.Errorf("CanonicalCriterion() = %+v", canonical)
+	}
+}
+
+func TestSearchCriteria(t *testing.T) {
+	s, err := NewCriteriaStore(writeCatalogue(t, localizedCatalogue))
+	if err != nil {
+		t.Fatalf("NewCriteriaStore() error = %v", err)
+	}
+	if hits := s.SearchCriteria("mission", i18n.French, 10); len(hits) != 1 || hits[0].Title != "Analyse de la mission" {
+		t.Errorf("SearchCriteria(fr) = %+v", hits)
+	}
+	if hits := s.SearchCriteria("mission", i18n.German, 10); len(hits) != 0 {
+		t.Errorf("SearchCriteria(de) = %+v, want no hits", hits)
+	}
+	if hits := s.SearchCriteria("auftragsanalysen", i18n.Italian, 10); len(hits) != 0 {
+		t.Errorf("SearchCriteria(it) found German title %+v", hits)
+	}
+}
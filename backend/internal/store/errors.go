@@ -0,0 +1,50 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+// Fehlerarten des Stores. Die Handler bilden sie auf HTTP-Statuscodes ab und prüfen sie
+// mit errors.Is, Fehler des Datenbanktreibers werden nie direkt ausgewertet.
+var (
+	// ErrNotFound: das Projekt oder Kriterium existiert nicht.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict: die Daten widersprechen einem bestehenden Eintrag, z.B. eine doppelte ID.
+	ErrConflict = errors.New("conflict")
+	// ErrLocked: die Kriterien des Projekts sind im aktuellen Status gesperrt. Der Fehler ist
+	// immer ein *LockedError mit dem Status.
+	ErrLocked = errors.New("locked")
+	// ErrValidation: die Eingabe oder die geladene Datei ist ungültig. Die Meldung richtet
+	// sich an den Benutzer und darf ausgegeben werden.
+	ErrValidation = errors.New("validation failed")
+)
+
+// invalid markiert err als Validierungsfehler, ohne die Meldung zu verändern.
+func invalid(err error) error {
+	return validationError{err}
+}
+
+type validationError struct {
+	error
+}
+
+func (e validationError) Unwrap() []error {
+	return []error{e.error, ErrValidation}
+}
+
+// LockedError meldet, dass das Projekt beim Schreiben im Status Status war, in dem die Kriterien
+// gesperrt sind.
+type LockedError struct {
+	Status models.ProjectStatus
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("criteria are locked in status %s", e.Status)
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrLocked
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestTranslate(t *testing.T) {
+	if err := translate(mongo.ErrNoDocuments); !errors.Is(err, ErrNotFound) {
+		t.Errorf("translate(ErrNoDocuments) = %v, want ErrNotFound", err)
+	}
+	duplicate := mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error"}}}
+	if err := translate(duplicate); !errors.Is(err, ErrConflict) {
+		t.Errorf("translate(duplicate key) = %v, want ErrConflict", err)
+	}
+	if err := translate(nil); err != nil {
+		t.Errorf("translate(nil) = %v", err)
+	}
+}
+
+func TestMatched(t *testing.T) {
+	if _, err := matched(&mongo.UpdateResult{MatchedCount: 0}, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("matched() without match = %v, want ErrNotFound", err)
+	}
+	if _, err := matched(&mongo.UpdateResult{MatchedCount: 1}, nil); err != nil {
+		t.Errorf("matched() with match = %v", err)
+	}
+}
+
+func TestCatalogueErrorsAreValidationErrors(t *testing.T) {
+	_, err := NewCriteriaStore(writeCatalogue(t, `{"criteria": [`))
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("NewCriteriaStore() with invalid JSON = %v, want ErrValidation", err)
+	}
+	if err.Error() != "kann Kriterien-JSON nicht parsen: unexpected end of JSON input" {
+		t.Errorf("error message changed: %q", err)
+	}
+
+	// Eine fehlende Datei ist ein Betriebsfehler, der Pfad darf nicht ausgegeben werden
+	_, err = NewCriteriaStore(common.Config{CriteriaFilePath: filepath.Join(t.TempDir(), "missing.json")})
+	if err == nil || errors.Is(err, ErrValidation) {
+		t.Errorf("NewCriteriaStore() with missing file = %v, want error other than ErrValidation", err)
+	}
+}
+
+func TestStatusCondition(t *testing.T) {
+	candidate := statusCondition([]models.ProjectStatus{models.StatusInProgress})
+	want := bson.M{"$in": bson.A{models.StatusInProgress, "", nil}}
+	if !reflect.DeepEqual(candidate, want) {
+		t.Errorf("statusCondition(in_progress) = %v, want %v", candidate, want)
+	}
+	expert := statusCondition([]models.ProjectStatus{models.StatusInProgress, models.StatusUnderReview})
+	want = bson.M{"$in": bson.A{models.StatusInProgress, "", nil, models.StatusUnderReview}}
+	if !reflect.DeepEqual(expert, want) {
+		t.Errorf("statusCondition(in_progress, under_review) = %v, want %v", expert, want)
+	}
+	// Ein eingereichtes Projekt trifft die Bedingung nicht mehr, der Schreibvorgang wird abgelehnt
+	if slices.Contains(expert["$in"].(bson.A), any(models.StatusSubmitted)) {
+		t.Errorf("statusCondition() contains submitted: %v", expert)
+	}
+}
+
+func TestLockedError(t *testing.T) {
+	err := fmt.Errorf("updating criterion: %w", &LockedError{Status: models.StatusGraded})
+	if !errors.Is(err, ErrLocked) || errors.Is(err, ErrConflict) || !expected(err) {
+		t.Errorf("LockedError %v should be ErrLocked and expected", err)
+	}
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// maxIDAttempts begrenzt die Anzahl Versuche, eine freie zufällige ID zu finden.
+const maxIDAttempts = 10
+
+var errNoFreeID = errors.New("no free project id found, consider increasing PROJECT_ID_LENGTH")
+
+// GetNewID liefert eine zufällige, noch nicht vergebene Projekt-ID. Bis zum Speichern kann
+// eine gleichzeitige Anfrage dieselbe ID erhalten, neue Projekte werden deshalb mit
+// CreateIpaProject oder CreateIpaProjects gespeichert.
+func (s *MongoStore) GetNewID(ctx context.Context) (id string, err error) {
+	defer observe(ctx, "GetNewID", time.Now(), &err)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	for range maxIDAttempts {
+		if err = ctx.Err(); err != nil {
+			return "", err
+		}
+		if id, err = s.idScheme.Generate(); err != nil {
+			return "", err
+		}
+		var count int64
+		count, err = s.collection.CountDocuments(ctx, bson.M{"publicId": id})
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return id, nil
+		}
+	}
+	return "", errNoFreeID
+}
+
+// CreateIpaProject speichert ein neues Projekt unter einer zufälligen, freien ID und liefert
+// diese. Hat eine gleichzeitige Anfrage die ID inzwischen vergeben, wird das Projekt unter
+// einer neuen ID gespeichert.
+func (s *MongoStore) CreateIpaProject(ctx context.Context, project models.MongoIpaProject) (id string, err error) {
+	defer observe(ctx, "CreateIpaProject", time.Now(), &err)
+	for range maxIDAttempts {
+		if project.ID, err = s.GetNewID(ctx); err != nil {
+			return "", err
+		}
+		if _, err = s.SavePersonData(ctx, project); err == nil {
+			return project.ID, nil
+		}
+		if !errors.Is(err, ErrConflict) {
+			return "", err
+		}
+	}
+	return "", err
+}
+
+// CreateIpaProjects speichert neue Projekte wie SaveIpaProjects gemeinsam oder gar nicht, jedes
+// unter einer eigenen zufälligen, freien ID, und liefert die IDs in der Reihenfolge der Projekte.
+// Hat eine gleichzeitige Anfrage eine der IDs inzwischen vergeben, werden alle IDs neu gewählt.
+func (s *MongoStore) CreateIpaProjects(ctx context.Context, projects []models.MongoIpaProject) (ids []string, err error) {
+	defer observe(ctx, "CreateIpaProjects", time.Now(), &err)
+	projects = slices.Clone(projects)
+	for range maxIDAttempts {
+		ids = make([]string, len(projects))
+		for i := range projects {
+			if ids[i], err = s.newBatchID(ctx, ids[:i]); err != nil {
+				return nil, err
+			}
+			projects[i].ID = ids[i]
+		}
+		if err = s.SaveIpaProjects(ctx, projects); err == nil {
+			return ids, nil
+		}
+		if !errors.Is(err, ErrConflict) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// newBatchID liefert eine freie ID, die nicht schon in picked für dasselbe Speichern gewählt wurde.
+func (s *MongoStore) newBatchID(ctx context.Context, picked []string) (string, error) {
+	for range maxIDAttempts {
+		id, err := s.GetNewID(ctx)
+		if err != nil || !slices.Contains(picked, id) {
+			return id, err
+		}
+	}
+	return "", errNoFreeID
+}
+
+// ValidateProjectID prüft eine eingegebene Projekt-ID gegen das konfigurierte Schema.
+func (s *MongoStore) ValidateProjectID(personId string) error {
+	if err := s.idScheme.Validate(common.NormalizeProjectID(personId)); err != nil {
+		return invalid(err)
+	}
+	return nil
+}
+
+func (s *MongoStore) ensureIndexes(ctx context.Context) (err error) {
+	defer observe(ctx, "ensureIndexes", time.Now(), &err)
+	ctx, cancel := context.WithTimeout(ctx, s.bulkTimeout)
+	defer cancel()
+
+	_, err = s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "publicId", Value: 1}},
+		// Alte Projekte erhalten die publicId erst durch MigrateLegacyProjectIDs
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"publicId": bson.M{"$exists": true}}),
+	})
+	return err
+}
+
+// MigrateLegacyProjectIDs ergänzt Projekte aus dem alten, fortlaufenden ID-Schema
+// um ihre lesbare ID ("AA01"), damit sie wie neue Projekte gefunden werden.
+func (s *MongoStore) MigrateLegacyProjectIDs(ctx context.Context) (err error) {
+	defer observe(ctx, "MigrateLegacyProjectIDs", time.Now(), &err)
+	ctx, cancel := context.WithTimeout(ctx, s.bulkTimeout)
+	defer cancel()
+
+	cursor, err := s.collection.Find(ctx, bson.M{"publicId": bson.M{"$exists": false}, "id": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"id": 1}))
+	if err != nil {
+		return err
+	}
+	var legacy []struct {
+		ID bson.ObjectID `bson:"_id"`
+		N  int           `bson:"id"`
+	}
+	if err := cursor.All(ctx, &legacy); err != nil {
+		return err
+	}
+
+	for _, project := range legacy {
+		_, err = s.collection.UpdateByID(ctx, project.ID, bson.M{"$set": bson.M{"publicId": common.FormatProjectID(project.N)}})
+		if err != nil {
+			return err
+		}
+	}
+	if len(legacy) > 0 {
+		common.Logger(ctx).Info("legacy project ids migrated", "count", len(legacy))
+	}
+	return nil
+}
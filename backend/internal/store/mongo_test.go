@@ -0,0 +1,248 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// testStore verbindet sich mit der MongoDB aus MONGO_TEST_URI und arbeitet in einer eigenen
+// Datenbank, die nach dem Test gelöscht wird. Ohne MONGO_TEST_URI wird der Test übersprungen.
+func testStore(t *testing.T) *MongoStore {
+	t.Helper()
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI is not set")
+	}
+	ctx := context.Background()
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("connecting to %s: %v", uri, err)
+	}
+	s := &MongoStore{client: client, timeout: 5 * time.Second, bulkTimeout: 30 * time.Second}
+	s.db = client.Database("criteria-catalogue-test-" + rand.Text()[:8])
+	s.collection = s.db.Collection(ProjectsCollection)
+	t.Cleanup(func() {
+		_ = s.db.Drop(ctx)
+		_ = s.Disconnect(ctx)
+	})
+	if err := s.ensureIndexes(ctx); err != nil {
+		t.Fatalf("ensureIndexes() = %v", err)
+	}
+	return s
+}
+
+const testProjectID = "K7QX2M3"
+
+func saveTestProject(t *testing.T, s *MongoStore, project models.MongoIpaProject) {
+	t.Helper()
+	project.ID = testProjectID
+	if _, err := s.SavePersonData(context.Background(), project); err != nil {
+		t.Fatalf("SavePersonData() = %v", err)
+	}
+}
+
+func TestCriteriaWritesFailAfterStatusChange(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	saveTestProject(t, s, models.MongoIpaProject{Criteria: []models.Criterion{{ID: "A01"}, {ID: "A02", Notes: "alt"}}})
+
+	// Der Handler liest das Projekt in Bearbeitung, bevor es abgegeben wird
+	project, err := s.GetIpaProject(ctx, testProjectID)
+	if err != nil {
+		t.Fatalf("GetIpaProject() = %v", err)
+	}
+	editable := []models.ProjectStatus{models.StatusInProgress}
+	if !slices.Contains(editable, project.CurrentStatus()) {
+		t.Fatalf("project status %q should be editable", project.CurrentStatus())
+	}
+	submit := models.StatusChange{From: models.StatusInProgress, To: models.StatusSubmitted}
+	if _, err := s.ChangeStatus(ctx, testProjectID, submit, nil, 0); err != nil {
+		t.Fatalf("ChangeStatus() = %v", err)
+	}
+
+	writes := map[string]func() error{
+		"add": func() error {
+			_, err := s.AddCriterionToIpaProject(ctx, testProjectID, models.Criterion{ID: "B01"}, editable)
+			return err
+		},
+		"update": func() error {
+			_, err := s.UpdateCriterionInIpaProject(ctx, testProjectID, "A01", models.Criterion{ID: "A01", Checked: []int{0}}, nil, editable)
+			return err
+		},
+		"restore notes": func() error {
+			revision := &models.NoteRevision{ID: "r1", CriterionID: "A02", Notes: "alt"}
+			_, err := s.UpdateCriterionInIpaProject(ctx, testProjectID, "A02", models.Criterion{ID: "A02", Notes: "neu"}, revision, editable)
+			return err
+		},
+		"apply": func() error {
+			changes := []models.CriterionChange{{CriterionID: "A01", CheckedAfter: []int{1}}}
+			_, err := s.ApplyCriterionChanges(ctx, testProjectID, changes, nil, editable)
+			return err
+		},
+		"delete": func() error {
+			_, err := s.DeleteCriterionFromIpaProject(ctx, testProjectID, "A01", editable)
+			return err
+		},
+	}
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			err := write()
+			var locked *LockedError
+			if !errors.As(err, &locked) || locked.Status != models.StatusSubmitted {
+				t.Errorf("got %v, want LockedError in status submitted", err)
+			}
+		})
+	}
+
+	stored, err := s.GetIpaProject(ctx, testProjectID)
+	if err != nil {
+		t.Fatalf("GetIpaProject() = %v", err)
+	}
+	if len(stored.Criteria) != 2 || len(stored.Criteria[0].Checked) != 0 || stored.Criteria[1].Notes != "alt" || len(stored.NoteRevisions) != 0 {
+		t.Errorf("locked project was changed: %+v", stored)
+	}
+}
+
+func TestCriteriaWritesDistinguishLockedFromNotFound(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	saveTestProject(t, s, models.MongoIpaProject{Status: models.StatusUnderReview, Criteria: []models.Criterion{{ID: "A01"}}})
+	expert := []models.ProjectStatus{models.StatusInProgress, models.StatusUnderReview}
+
+	if _, err := s.UpdateCriterionInIpaProject(ctx, testProjectID, "A01", models.Criterion{ID: "A01", Checked: []int{0}}, nil, expert); err != nil {
+		t.Errorf("UpdateCriterionInIpaProject() under review as expert = %v", err)
+	}
+	if _, err := s.UpdateCriterionInIpaProject(ctx, testProjectID, "Z99", models.Criterion{ID: "Z99"}, nil, expert); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateCriterionInIpaProject() of missing criterion = %v, want ErrNotFound", err)
+	}
+	if _, err := s.DeleteCriterionFromIpaProject(ctx, "AA00", "A01", expert); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteCriterionFromIpaProject() of missing project = %v, want ErrNotFound", err)
+	}
+	stale := []models.CriterionChange{{CriterionID: "A01", CheckedAfter: []int{1}}} // A01 ist inzwischen abgehakt
+	if _, err := s.ApplyCriterionChanges(ctx, testProjectID, stale, nil, expert); !errors.Is(err, ErrConflict) || errors.Is(err, ErrLocked) {
+		t.Errorf("ApplyCriterionChanges() with stale state = %v, want ErrConflict", err)
+	}
+}
+
+func TestChangeStatusRejectsSnapshotOfChangedCriteria(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	saveTestProject(t, s, models.MongoIpaProject{Status: models.StatusUnderReview, Criteria: []models.Criterion{{ID: "A01"}}})
+	expert := []models.ProjectStatus{models.StatusInProgress, models.StatusUnderReview}
+	graded := models.StatusChange{From: models.StatusUnderReview, To: models.StatusGraded}
+
+	// Die Bewertung wird aus dem geladenen Stand berechnet, bevor eine Expertin ein Kriterium ändert
+	project, err := s.GetIpaProject(ctx, testProjectID)
+	if err != nil {
+		t.Fatalf("GetIpaProject() = %v", err)
+	}
+	if _, err := s.UpdateCriterionInIpaProject(ctx, testProjectID, "A01", models.Criterion{ID: "A01", Checked: []int{0}}, nil, expert); err != nil {
+		t.Fatalf("UpdateCriterionInIpaProject() = %v", err)
+	}
+	snapshot := &models.GradeSnapshot{ID: "S1", Sequence: 1}
+	if _, err := s.ChangeStatus(ctx, testProjectID, graded, snapshot, project.CriteriaRevision); !errors.Is(err, ErrConflict) {
+		t.Fatalf("ChangeStatus() with stale criteria = %v, want ErrConflict", err)
+	}
+	stored, err := s.GetIpaProject(ctx, testProjectID)
+	if err != nil || stored.Status != models.StatusUnderReview || len(stored.GradeSnapshots) != 0 {
+		t.Fatalf("project after rejected grading = %+v, %v", stored, err)
+	}
+
+	// Mit dem aktuellen Stand wird die Bewertung gespeichert
+	if _, err := s.ChangeStatus(ctx, testProjectID, graded, snapshot, stored.CriteriaRevision); err != nil {
+		t.Errorf("ChangeStatus() with current criteria = %v", err)
+	}
+}
+
+func TestCommentWritesKeepConcurrentChanges(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	created := time.Now().UTC().Truncate(time.Millisecond)
+	thread := models.Comment{ID: "C1", CriterionID: "A01", ThreadID: "C1", AuthorKey: "candidate", Text: "alt", CreatedAt: created, UpdatedAt: created, ReadBy: []string{"candidate", "expert"}}
+	saveTestProject(t, s, models.MongoIpaProject{Criteria: []models.Criterion{{ID: "A01"}}, Comments: []models.Comment{thread}})
+
+	// Die Expertin erledigt die Diskussion, während die Kandidatin den Text mit dem alten Stand ändert
+	if _, err := s.ResolveThread(ctx, testProjectID, "C1", true); err != nil {
+		t.Fatalf("ResolveThread() = %v", err)
+	}
+	edited := created.Add(time.Minute)
+	if _, err := s.EditComment(ctx, testProjectID, "C1", "neu", edited, "candidate"); err != nil {
+		t.Fatalf("EditComment() = %v", err)
+	}
+	stored, err := s.GetIpaProject(ctx, testProjectID)
+	if err != nil {
+		t.Fatalf("GetIpaProject() = %v", err)
+	}
+	got := stored.Comments[0]
+	if !got.Resolved || got.Text != "neu" || !got.UpdatedAt.Equal(edited) || !slices.Equal(got.ReadBy, []string{"candidate"}) {
+		t.Errorf("comment after concurrent writes = %+v", got)
+	}
+
+	// Ein gelöschter Kommentar lässt sich nicht mehr ändern
+	if _, err := s.DeleteComment(ctx, testProjectID, "C1", edited); err != nil {
+		t.Fatalf("DeleteComment() = %v", err)
+	}
+	if _, err := s.EditComment(ctx, testProjectID, "C1", "wieder", edited, "candidate"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("EditComment() on deleted comment = %v, want ErrNotFound", err)
+	}
+}
+
+func TestProjectDateFollowsEndDate(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	start, end := models.Date{Year: 2026, Month: 5, Day: 4}, models.Date{Year: 2026, Month: 5, Day: 15}
+	// Frühere Versionen speicherten den Starttag in date
+	saveTestProject(t, s, models.MongoIpaProject{Date: start.String(), StartDate: start, EndDate: end})
+
+	if err := s.MigrateLegacyDates(ctx); err != nil {
+		t.Fatalf("MigrateLegacyDates() = %v", err)
+	}
+	stored, err := s.GetIpaProject(ctx, testProjectID)
+	if err != nil || stored.Date != "2026-05-15" {
+		t.Fatalf("date after migration = %q, %v, want the end date", stored.Date, err)
+	}
+
+	later := models.Date{Year: 2026, Month: 5, Day: 22}
+	if _, err := s.UpdateTimeline(ctx, testProjectID, start, later, nil); err != nil {
+		t.Fatalf("UpdateTimeline() = %v", err)
+	}
+	found, err := s.FindIpaProjects(ctx, ProjectFilter{DateFrom: "2026-05-20"})
+	if err != nil || len(found) != 1 {
+		t.Errorf("FindIpaProjects() after moving the end date = %d projects, %v, want the project", len(found), err)
+	}
+}
+
+func TestCreateIpaProjectsPicksDistinctIDs(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	// Mit 16 möglichen IDs treffen zufällige IDs innerhalb der Liste oft aufeinander
+	s.idScheme = common.ProjectIDScheme{Alphabet: "AB", Length: 4}
+	id, err := s.CreateIpaProject(ctx, models.MongoIpaProject{Lastname: "Muster"})
+	if err != nil {
+		t.Fatalf("CreateIpaProject() = %v", err)
+	}
+
+	ids, err := s.CreateIpaProjects(ctx, make([]models.MongoIpaProject, 4))
+	if err != nil {
+		t.Fatalf("CreateIpaProjects() = %v", err)
+	}
+	all := append([]string{id}, ids...)
+	slices.Sort(all)
+	if len(ids) != 4 || len(slices.Compact(all)) != 5 {
+		t.Errorf("CreateIpaProjects() = %v after %s, want four new distinct ids", ids, id)
+	}
+	stored, err := s.GetIpaProjectsByIDs(ctx, ids)
+	if err != nil || len(stored) != 4 {
+		t.Errorf("GetIpaProjectsByIDs() = %d projects, %v, want 4", len(stored), err)
+	}
+}
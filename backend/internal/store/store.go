@@ -3,9 +3,12 @@ package store
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/Liuuner/criteria-catalogue/backend/internal/common"
+	"github.com/Liuuner/criteria-catalogue/backend/internal/metrics"
 	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -13,15 +16,32 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 )
 
+const (
+	databaseName = "criteria-catalogue"
+	// maxNoteRevisions begrenzt die früheren Fassungen der Notizen pro Projekt, die ältesten
+	// werden verworfen.
+	maxNoteRevisions = 500
+	// ProjectsCollection enthält die IPA-Projekte.
+	ProjectsCollection = "user-data"
+)
+
 type MongoStore struct {
-	client     *mongo.Client
-	db         *mongo.Database
-	collection *mongo.Collection
-	ctx        context.Context
+	client      *mongo.Client
+	db          *mongo.Database
+	collection  *mongo.Collection
+	idScheme    common.ProjectIDScheme
+	timeout     time.Duration // Timeout für Operationen auf einzelnen Projekten
+	bulkTimeout time.Duration // Timeout für Operationen auf vielen Projekten, z.B. Export und Import
 }
 
-func NewMongoStore(cfg common.Config) (*MongoStore, error) {
-	s := &MongoStore{}
+// NewMongoStore verbindet sich mit MongoDB. ctx begrenzt nur den Verbindungsaufbau,
+// jede spätere Operation erhält den Kontext der auslösenden Anfrage.
+func NewMongoStore(ctx context.Context, cfg common.Config) (*MongoStore, error) {
+	s := &MongoStore{
+		idScheme:    cfg.ProjectIDScheme(),
+		timeout:     cfg.MongoTimeout,
+		bulkTimeout: cfg.MongoBulkTimeout,
+	}
 	var err error
 	s.client, err = mongo.Connect(options.Client().ApplyURI(cfg.MongoURI))
 
@@ -29,126 +49,544 @@ func NewMongoStore(cfg common.Config) (*MongoStore, error) {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	pingCtx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
 
-	err = s.client.Ping(ctx, readpref.Primary())
+	err = s.client.Ping(pingCtx, readpref.Primary())
 	if err != nil {
 		return nil, errors.New("unable to ping MongoDB: " + err.Error())
 	}
 
-	s.db = s.client.Database("criteria-catalogue")
-	s.collection = s.db.Collection("user-data")
+	s.db = s.client.Database(databaseName)
+	s.collection = s.db.Collection(ProjectsCollection)
+
+	if err := s.ensureIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("unable to create indexes: %w", err)
+	}
+	if err := s.MigrateLegacyProjectIDs(ctx); err != nil {
+		return nil, fmt.Errorf("unable to migrate project ids: %w", err)
+	}
+	if err := s.MigrateLegacyDates(ctx); err != nil {
+		return nil, fmt.Errorf("unable to migrate project dates: %w", err)
+	}
+
+	return s, nil
+}
+
+// projectFilter liefert den Filter für das Projekt mit der angegebenen (eingegebenen) ID.
+func projectFilter(personId string) bson.M {
+	return bson.M{"publicId": common.NormalizeProjectID(personId)}
+}
+
+// Ping prüft, ob der primäre MongoDB-Server erreichbar ist.
+func (s *MongoStore) Ping(ctx context.Context) (err error) {
+	defer observe(ctx, "Ping", time.Now(), &err)
+	return s.client.Ping(ctx, readpref.Primary())
+}
+
+// Disconnect schliesst die Verbindung zu MongoDB. Laufende Operationen dürfen bis
+// zum Ablauf von ctx abgeschlossen werden.
+func (s *MongoStore) Disconnect(ctx context.Context) error {
+	return s.client.Disconnect(ctx)
+}
+
+// observe protokolliert eine Datenbankoperation mit ihrer Dauer. Der Logger aus dem
+// Kontext enthält Request-ID, Route und Projekt-ID der auslösenden Anfrage.
+func observe(ctx context.Context, operation string, start time.Time, err *error) {
+	latency := time.Since(start)
+	failed := *err != nil && !expected(*err)
+	metrics.ObserveMongoOperation(operation, latency, failed)
+
+	level := slog.LevelDebug
+	attrs := []slog.Attr{slog.String("operation", operation), slog.Duration("latency", latency)}
+	if failed {
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("error", (*err).Error()))
+	}
+	common.Logger(ctx).LogAttrs(ctx, level, "mongo operation", attrs...)
+}
+
+// expected meldet Fehler, die aus der Anfrage folgen und keine Störung der Datenbank sind.
+func expected(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) || errors.Is(err, ErrLocked) || errors.Is(err, ErrValidation)
+}
 
-	return s, err
+// translate ersetzt Fehler des Treibers durch die Fehlerarten des Stores.
+func translate(err error) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return fmt.Errorf("%w: %w", ErrConflict, err)
+	}
+	return err
 }
 
-func (s *MongoStore) Disconnect() {
-	if err := s.client.Disconnect(s.ctx); err != nil {
-		panic(err)
+// matched liefert ErrNotFound, wenn eine Aktualisierung kein Dokument getroffen hat.
+func matched(res *mongo.UpdateResult, err error) (*mongo.UpdateResult, error) {
+	if err == nil && res.MatchedCount == 0 {
+		return res, ErrNotFound
 	}
+	return res, translate(err)
 }
 
 // SetPersonData speichert die Personendaten.
-func (s *MongoStore) SavePersonData(data models.MongoIpaProject) (res *mongo.InsertOneResult, err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (s *MongoStore) SavePersonData(ctx context.Context, data models.MongoIpaProject) (res *mongo.InsertOneResult, err error) {
+	defer observe(ctx, "SavePersonData", time.Now(), &err)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
-	return s.collection.InsertOne(ctx, data)
+	res, err = s.collection.InsertOne(ctx, data)
+	return res, translate(err)
 }
 
 // GetPersonData ruft die Personendaten ab.
-func (s *MongoStore) GetPersonData(personId string) (models.MongoIpaProject, error) {
-	result, err := s.GetIpaProject(personId)
+func (s *MongoStore) GetPersonData(ctx context.Context, personId string) (models.MongoIpaProject, error) {
+	result, err := s.GetIpaProject(ctx, personId)
 	result.Criteria = nil
 	return result, err
 }
 
-func (s *MongoStore) GetIpaProject(personId string) (models.MongoIpaProject, error) {
-	// GetPersonData ruft die Personendaten ab.
-	var result models.MongoIpaProject
+func (s *MongoStore) GetIpaProject(ctx context.Context, personId string) (result models.MongoIpaProject, err error) {
+	defer observe(ctx, "GetIpaProject", time.Now(), &err)
 
-	id, err := common.ParseProjectID(personId)
-	if err != nil {
-		return result, nil
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	err = translate(s.collection.FindOne(ctx, projectFilter(personId)).Decode(&result))
+	return result, err
+}
+
+// UpdateIpaProject aktualisiert die Personendaten und den IPA-Zeitraum eines Projekts.
+// Kriterien, Meilensteine, Passwort und Archivstatus bleiben unverändert.
+func (s *MongoStore) UpdateIpaProject(ctx context.Context, personId string, data models.MongoIpaProject) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "UpdateIpaProject", time.Now(), &err)
+	filter := projectFilter(personId)
+	update := bson.M{"$set": bson.M{
+		"firstname": data.Firstname,
+		"lastname":  data.Lastname,
+		"topic":     data.Topic,
+		"date":      data.Date,
+		"startDate": data.StartDate,
+		"endDate":   data.EndDate,
+	}}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return matched(s.collection.UpdateOne(ctx, filter, update))
+}
+
+// UpdateTimeline ersetzt den IPA-Zeitraum und die Meilensteine eines Projekts. Das Feld date
+// folgt dem Abgabetag, damit die Projektliste nach dem aktuellen Zeitraum filtert.
+func (s *MongoStore) UpdateTimeline(ctx context.Context, personId string, start, end models.Date, milestones []models.Milestone) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "UpdateTimeline", time.Now(), &err)
+	set := bson.M{
+		"startDate":  start,
+		"endDate":    end,
+		"milestones": milestones,
+	}
+	if !end.IsZero() {
+		set["date"] = end.String() // Ohne Zeitraum bleibt die Angabe aus älteren Versionen erhalten
 	}
+	update := bson.M{"$set": set}
 
-	filter := bson.D{{"id", id}}
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
 
-	err = s.collection.FindOne(ctx, filter).Decode(&result)
-	return result, err
+	return matched(s.collection.UpdateOne(ctx, projectFilter(personId), update))
 }
 
-func (s *MongoStore) UpdateIpaProject(personId string, data models.MongoIpaProject) (*mongo.UpdateResult, error) {
-	id, err := common.ParseProjectID(personId)
+// AddCriterionToIpaProject fügt ein Kriterium hinzu, solange das Projekt in einem der Status
+// editable ist.
+func (s *MongoStore) AddCriterionToIpaProject(ctx context.Context, personId string, criterion models.Criterion, editable []models.ProjectStatus) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "AddCriterionToIpaProject", time.Now(), &err)
+	// Check if a criterion with the same id already exists
+	filter := projectFilter(personId)
+	filter["criteria.id"] = criterion.ID
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	count, err := s.collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
+	if count > 0 {
+		return nil, fmt.Errorf("criterion %s already exists: %w", criterion.ID, ErrConflict)
+	}
 
-	filter := bson.D{{"id", id}}
-	update := bson.D{{"$set", data}}
+	// Add the new criterion
+	filter = projectFilter(personId)
+	filter["status"] = statusCondition(editable)
+	update := bson.M{"$push": bson.M{"criteria": criterion}, "$inc": criteriaRevision}
+	res, err = s.collection.UpdateOne(ctx, filter, update)
+	if err == nil && res.MatchedCount == 0 {
+		return res, s.unmatched(ctx, filter, ErrNotFound)
+	}
+	return res, err
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// UpdateCriterionInIpaProject ersetzt ein Kriterium, solange das Projekt in einem der Status
+// editable ist. Ist revision gesetzt, wird die bisherige Fassung der Notizen im selben Schritt
+// aufbewahrt.
+func (s *MongoStore) UpdateCriterionInIpaProject(ctx context.Context, personId string, criterionId string, criterion models.Criterion, revision *models.NoteRevision, editable []models.ProjectStatus) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "UpdateCriterionInIpaProject", time.Now(), &err)
+	filter := projectFilter(personId)
+	filter["criteria.id"] = criterionId
+	filter["status"] = statusCondition(editable)
+	update := bson.M{"$set": bson.M{"criteria.$": criterion}, "$inc": criteriaRevision}
+	if revision != nil {
+		update["$push"] = bson.M{"noteRevisions": bson.M{"$each": bson.A{revision}, "$slice": -maxNoteRevisions}}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
 
-	return s.collection.UpdateOne(ctx, filter, update)
+	// Der Filter enthält die Kriterien-ID, fehlt das Kriterium, wird kein Projekt getroffen
+	res, err = s.collection.UpdateOne(ctx, filter, update)
+	if err == nil && res.MatchedCount == 0 {
+		return res, s.unmatched(ctx, filter, ErrNotFound)
+	}
+	return res, err
 }
 
-func (s *MongoStore) AddCriterionToIpaProject(personId string, criterion models.Criterion) (*mongo.UpdateResult, error) {
-	id, err := common.ParseProjectID(personId)
-	if err != nil {
-		return nil, err
+// ApplyCriterionChanges übernimmt die erfüllten Anforderungen und Notizen aus changes in einem
+// Schritt, solange das Projekt in einem der Status editable ist. Hat sich eines der Kriterien
+// seit dem Laden geändert, wird nichts gespeichert. changes darf nicht leer sein.
+func (s *MongoStore) ApplyCriterionChanges(ctx context.Context, personId string, changes []models.CriterionChange, revisions []models.NoteRevision, editable []models.ProjectStatus) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "ApplyCriterionChanges", time.Now(), &err)
+	conditions := make(bson.A, len(changes))
+	set := bson.M{}
+	arrayFilters := make([]any, len(changes))
+	for i, change := range changes {
+		// Die Reihenfolge der gespeicherten Indizes spielt keine Rolle
+		checked := bson.M{"$size": len(change.CheckedBefore), "$all": change.CheckedBefore}
+		if len(change.CheckedBefore) == 0 {
+			checked = bson.M{"$in": bson.A{nil, bson.A{}}}
+		}
+		notes := any(change.NotesBefore)
+		if change.NotesBefore == "" {
+			notes = bson.M{"$in": bson.A{"", nil}}
+		}
+		conditions[i] = bson.M{"criteria": bson.M{"$elemMatch": bson.M{"id": change.CriterionID, "checked": checked, "notes": notes}}}
+
+		name := fmt.Sprintf("c%d", i)
+		set["criteria.$["+name+"].checked"] = change.CheckedAfter
+		set["criteria.$["+name+"].notes"] = change.NotesAfter
+		arrayFilters[i] = bson.M{name + ".id": change.CriterionID}
+	}
+	filter := projectFilter(personId)
+	filter["$and"] = conditions
+	filter["status"] = statusCondition(editable)
+	update := bson.M{"$set": set, "$inc": criteriaRevision}
+	if len(revisions) > 0 {
+		update["$push"] = bson.M{"noteRevisions": bson.M{"$each": revisions, "$slice": -maxNoteRevisions}}
 	}
 
-	// Check if a criterion with the same id already exists
-	filter := bson.D{
-		{"id", id},
-		{"criteria.id", criterion.ID},
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	res, err = s.collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetArrayFilters(arrayFilters))
+	if err == nil && res.MatchedCount == 0 {
+		changed := fmt.Errorf("criteria of project %s changed in the meantime: %w", personId, ErrConflict)
+		return res, s.unmatched(ctx, filter, changed)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	return res, err
+}
+
+// DeleteCriterionFromIpaProject entfernt ein Kriterium, solange das Projekt in einem der Status
+// editable ist.
+func (s *MongoStore) DeleteCriterionFromIpaProject(ctx context.Context, personId string, criterionId string, editable []models.ProjectStatus) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "DeleteCriterionFromIpaProject", time.Now(), &err)
+	filter := projectFilter(personId)
+	filter["status"] = statusCondition(editable)
+	update := bson.M{"$pull": bson.M{"criteria": bson.M{"id": criterionId}}, "$inc": criteriaRevision}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
 
-	count, err := s.collection.CountDocuments(ctx, filter)
+	res, err = s.collection.UpdateOne(ctx, filter, update)
+	switch {
+	case err != nil:
+		return res, err
+	case res.MatchedCount == 0:
+		return res, s.unmatched(ctx, filter, ErrNotFound)
+	case res.ModifiedCount == 0:
+		return res, ErrNotFound // Das Projekt hat kein Kriterium mit dieser ID
+	}
+	return res, nil
+}
+
+// criteriaRevision erhöht bei jeder Änderung der Kriterien MongoIpaProject.CriteriaRevision.
+var criteriaRevision = bson.M{"criteriaRevision": 1}
+
+// statusCondition liefert die Bedingung an das Feld status für die angegebenen Status. Projekte
+// aus älteren Versionen haben keinen Status und gelten als in Bearbeitung.
+func statusCondition(statuses []models.ProjectStatus) bson.M {
+	values := bson.A{}
+	for _, status := range statuses {
+		values = append(values, status)
+		if status == models.StatusInProgress {
+			values = append(values, "", nil)
+		}
+	}
+	return bson.M{"$in": values}
+}
+
+// unmatched ermittelt, warum eine Aktualisierung mit der Statusbedingung in filter kein Projekt
+// getroffen hat. Trifft filter ohne die Bedingung ein Projekt, ist es inzwischen gesperrt und das
+// Ergebnis ein *LockedError, sonst wird fallback zurückgegeben.
+func (s *MongoStore) unmatched(ctx context.Context, filter bson.M, fallback error) error {
+	unlocked := bson.M{}
+	for key, value := range filter {
+		if key != "status" {
+			unlocked[key] = value
+		}
+	}
+	var project models.MongoIpaProject
+	err := s.collection.FindOne(ctx, unlocked, options.FindOne().SetProjection(bson.M{"status": 1})).Decode(&project)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fallback
+	}
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if count > 0 {
-		return nil, errors.New("criterion with the same id already exists")
+	return &LockedError{Status: project.CurrentStatus()}
+}
+
+// AddJournalEntry fügt dem Arbeitsjournal eines Projekts einen Eintrag hinzu.
+func (s *MongoStore) AddJournalEntry(ctx context.Context, personId string, entry models.JournalEntry) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "AddJournalEntry", time.Now(), &err)
+	update := bson.M{"$push": bson.M{"journal": entry}}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return matched(s.collection.UpdateOne(ctx, projectFilter(personId), update))
+}
+
+// UpdateJournalEntry ersetzt einen Eintrag im Arbeitsjournal.
+func (s *MongoStore) UpdateJournalEntry(ctx context.Context, personId string, entry models.JournalEntry) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "UpdateJournalEntry", time.Now(), &err)
+	filter := projectFilter(personId)
+	filter["journal.id"] = entry.ID
+	update := bson.M{"$set": bson.M{"journal.$": entry}}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// Der Filter enthält die ID des Eintrags, fehlt der Eintrag, wird kein Projekt getroffen
+	return matched(s.collection.UpdateOne(ctx, filter, update))
+}
+
+// DeleteJournalEntry löscht einen Eintrag aus dem Arbeitsjournal.
+func (s *MongoStore) DeleteJournalEntry(ctx context.Context, personId string, entryId string) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "DeleteJournalEntry", time.Now(), &err)
+	update := bson.M{"$pull": bson.M{"journal": bson.M{"id": entryId}}}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	res, err = matched(s.collection.UpdateOne(ctx, projectFilter(personId), update))
+	if err == nil && res.ModifiedCount == 0 {
+		return res, ErrNotFound // Das Projekt hat keinen Eintrag mit dieser ID
 	}
+	return res, err
+}
 
-	// Add the new criterion
-	filter = bson.D{{"id", id}}
-	update := bson.D{{"$push", bson.D{{"criteria", criterion}}}}
-	return s.collection.UpdateOne(ctx, filter, update)
+// AddTask fügt dem Zeitplan eines Projekts eine Aufgabe hinzu.
+func (s *MongoStore) AddTask(ctx context.Context, personId string, task models.Task) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "AddTask", time.Now(), &err)
+	update := bson.M{"$push": bson.M{"tasks": task}}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return matched(s.collection.UpdateOne(ctx, projectFilter(personId), update))
 }
 
-func (s *MongoStore) UpdateCriterionInIpaProject(personId string, criterionId string, criterion models.Criterion) (*mongo.UpdateResult, error) {
-	id, err := common.ParseProjectID(personId)
+// UpdateTask ersetzt eine Aufgabe im Zeitplan.
+func (s *MongoStore) UpdateTask(ctx context.Context, personId string, task models.Task) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "UpdateTask", time.Now(), &err)
+	filter := projectFilter(personId)
+	filter["tasks.id"] = task.ID
+	update := bson.M{"$set": bson.M{"tasks.$": task}}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// Der Filter enthält die ID der Aufgabe, fehlt die Aufgabe, wird kein Projekt getroffen
+	return matched(s.collection.UpdateOne(ctx, filter, update))
+}
+
+// DeleteTask löscht eine Aufgabe aus dem Zeitplan. Die zugeordneten Journaleinträge bleiben
+// erhalten und gelten danach als Stunden ohne Aufgabe.
+func (s *MongoStore) DeleteTask(ctx context.Context, personId string, taskId string) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "DeleteTask", time.Now(), &err)
+	update := bson.M{"$pull": bson.M{"tasks": bson.M{"id": taskId}}}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	res, err = matched(s.collection.UpdateOne(ctx, projectFilter(personId), update))
+	if err == nil && res.ModifiedCount == 0 {
+		return res, ErrNotFound // Das Projekt hat keine Aufgabe mit dieser ID
+	}
 	if err != nil {
-		return nil, err
+		return res, err
 	}
 
-	filter := bson.D{{"id", id}, {"criteria.id", criterionId}}
-	update := bson.D{{"$set", bson.D{{"criteria.$", criterion}}}}
+	// Array-Filter setzen ein vorhandenes Journal voraus, der Filter trifft Projekte ohne
+	// zugeordnete Einträge daher nicht
+	filter := projectFilter(personId)
+	filter["journal.taskId"] = taskId
+	unassign := bson.M{"$unset": bson.M{"journal.$[entry].taskId": ""}}
+	opts := options.UpdateOne().SetArrayFilters([]any{bson.M{"entry.taskId": taskId}})
+	_, err = s.collection.UpdateOne(ctx, filter, unassign, opts)
+	return res, err
+}
+
+// AddEvidence fügt einem Projekt einen Beleg hinzu.
+func (s *MongoStore) AddEvidence(ctx context.Context, personId string, evidence models.Evidence) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "AddEvidence", time.Now(), &err)
+	update := bson.M{"$push": bson.M{"evidence": evidence}}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
 
-	return s.collection.UpdateOne(ctx, filter, update)
+	return matched(s.collection.UpdateOne(ctx, projectFilter(personId), update))
 }
 
-func (s *MongoStore) DeleteCriterionFromIpaProject(personId string, criterionId string) (*mongo.UpdateResult, error) {
-	id, err := common.ParseProjectID(personId)
-	if err != nil {
-		return nil, err
+// DeleteEvidence löscht einen Beleg. Die Datei im BlobStore löscht der Aufrufer.
+func (s *MongoStore) DeleteEvidence(ctx context.Context, personId string, evidenceId string) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "DeleteEvidence", time.Now(), &err)
+	update := bson.M{"$pull": bson.M{"evidence": bson.M{"id": evidenceId}}}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	res, err = matched(s.collection.UpdateOne(ctx, projectFilter(personId), update))
+	if err == nil && res.ModifiedCount == 0 {
+		return res, ErrNotFound // Das Projekt hat keinen Beleg mit dieser ID
 	}
+	return res, err
+}
+
+// AddComment fügt einem Projekt einen Kommentar hinzu.
+func (s *MongoStore) AddComment(ctx context.Context, personId string, comment models.Comment) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "AddComment", time.Now(), &err)
+	update := bson.M{"$push": bson.M{"comments": comment}}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return matched(s.collection.UpdateOne(ctx, projectFilter(personId), update))
+}
+
+// EditComment ersetzt den Text eines Kommentars. Für die anderen Benutzer gilt der Kommentar
+// danach wieder als ungelesen, readBy enthält nur noch authorKey. Die übrigen Felder bleiben
+// unverändert, damit gleichzeitige Änderungen am Status der Diskussion nicht verloren gehen.
+func (s *MongoStore) EditComment(ctx context.Context, personId string, commentId string, text string, updatedAt time.Time, authorKey string) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "EditComment", time.Now(), &err)
+	update := bson.M{"$set": bson.M{
+		"comments.$.text":      text,
+		"comments.$.updatedAt": updatedAt,
+		"comments.$.readBy":    []string{authorKey},
+	}}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return matched(s.collection.UpdateOne(ctx, commentFilter(personId, commentId), update))
+}
+
+// DeleteComment entfernt den Text eines Kommentars und markiert ihn als gelöscht.
+func (s *MongoStore) DeleteComment(ctx context.Context, personId string, commentId string, updatedAt time.Time) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "DeleteComment", time.Now(), &err)
+	update := bson.M{"$set": bson.M{
+		"comments.$.text":      "",
+		"comments.$.deleted":   true,
+		"comments.$.updatedAt": updatedAt,
+	}}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return matched(s.collection.UpdateOne(ctx, commentFilter(personId, commentId), update))
+}
+
+// ResolveThread markiert die Diskussion, die mit dem Kommentar commentId beginnt, als erledigt
+// oder wieder als offen.
+func (s *MongoStore) ResolveThread(ctx context.Context, personId string, commentId string, resolved bool) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "ResolveThread", time.Now(), &err)
+	update := bson.M{"$set": bson.M{"comments.$.resolved": resolved}}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return matched(s.collection.UpdateOne(ctx, commentFilter(personId, commentId), update))
+}
+
+// commentFilter trifft das Projekt nur, solange es den Kommentar commentId enthält und dieser
+// nicht gelöscht ist. Der Positionsoperator $ in der Änderung verweist auf diesen Kommentar.
+func commentFilter(personId string, commentId string) bson.M {
+	filter := projectFilter(personId)
+	filter["comments"] = bson.M{"$elemMatch": bson.M{"id": commentId, "deleted": bson.M{"$ne": true}}}
+	return filter
+}
+
+// MarkCommentsRead vermerkt alle Kommentare zu einem Kriterium als gelesen vom Benutzer userKey.
+func (s *MongoStore) MarkCommentsRead(ctx context.Context, personId string, criterionId string, userKey string) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "MarkCommentsRead", time.Now(), &err)
+	// Array-Filter setzen vorhandene Kommentare voraus, ohne Kommentare wird kein Projekt getroffen
+	filter := projectFilter(personId)
+	filter["comments.criterionId"] = criterionId
+	update := bson.M{"$addToSet": bson.M{"comments.$[comment].readBy": userKey}}
+	opts := options.UpdateOne().SetArrayFilters([]any{bson.M{"comment.criterionId": criterionId}})
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return matched(s.collection.UpdateOne(ctx, filter, update, opts))
+}
+
+// ChangeStatus versetzt ein Projekt vom Status change.From in den Status change.To und ergänzt
+// die Historie, beim Übergang zu "graded" auch die Momentaufnahme der Bewertung. Diese wird nur
+// gespeichert, solange die Kriterien noch den Stand revision haben, aus dem sie berechnet wurde.
+// Hat sich der Status oder der Stand der Kriterien inzwischen geändert, wird ErrConflict
+// zurückgegeben.
+func (s *MongoStore) ChangeStatus(ctx context.Context, personId string, change models.StatusChange, snapshot *models.GradeSnapshot, revision int) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "ChangeStatus", time.Now(), &err)
+	filter := projectFilter(personId)
+	filter["status"] = statusCondition([]models.ProjectStatus{change.From})
+	push := bson.M{"statusHistory": change}
+	if snapshot != nil {
+		push["gradeSnapshots"] = snapshot
+		filter["criteriaRevision"] = revision
+		if revision == 0 {
+			// Projekte ohne Änderung seit der Einführung des Zählers haben das Feld nicht
+			filter["criteriaRevision"] = bson.M{"$in": bson.A{0, nil}}
+		}
+	}
+	update := bson.M{"$set": bson.M{"status": change.To}, "$push": push}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	res, err = s.collection.UpdateOne(ctx, filter, update)
+	if err == nil && res.MatchedCount == 0 {
+		return res, fmt.Errorf("status of project %s is no longer %s or its criteria changed: %w", personId, change.From, ErrConflict)
+	}
+	return res, err
+}
 
-	filter := bson.D{{"id", id}}
-	update := bson.D{{"$pull", bson.D{{"criteria", bson.D{{"id", criterionId}}}}}}
+// UpdatePassword setzt einen neuen Passwort-Hash und hebt die Pflicht zur Passwortänderung auf.
+func (s *MongoStore) UpdatePassword(ctx context.Context, personId string, passwordHash string) (res *mongo.UpdateResult, err error) {
+	defer observe(ctx, "UpdatePassword", time.Now(), &err)
+	filter := projectFilter(personId)
+	update := bson.M{"$set": bson.M{"passwordHash": passwordHash, "passwordChangeRequired": false}}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
 
-	return s.collection.UpdateOne(ctx, filter, update)
+	return matched(s.collection.UpdateOne(ctx, filter, update))
 }
@@ -0,0 +1,172 @@
+// Package timeline prüft den IPA-Zeitraum eines Projekts und berechnet den Stand der Meilensteine.
+package timeline
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+// MaxPeriodDays begrenzt die Länge des IPA-Zeitraums. Eine IPA dauert wenige Wochen,
+// längere Zeiträume sind Tippfehler im Jahr oder Monat.
+const MaxPeriodDays = 120
+
+// Stand des Projekts im IPA-Zeitraum.
+const (
+	StatusUnscheduled = "unscheduled" // Start- und Enddatum fehlen
+	StatusUpcoming    = "upcoming"    // Die IPA hat noch nicht begonnen
+	StatusRunning     = "running"
+	StatusFinished    = "finished" // Der Abgabetag ist vorbei
+)
+
+// Stand eines Meilensteins.
+const (
+	MilestoneDone     = "done"
+	MilestoneDue      = "due" // Heute fällig
+	MilestoneUpcoming = "upcoming"
+	MilestoneOverdue  = "overdue" // Vorbei, aber nicht erledigt
+)
+
+var (
+	ErrIncompletePeriod        = errors.New("start and end date must both be set")
+	ErrEndBeforeStart          = errors.New("end date is before start date")
+	ErrPeriodTooLong           = fmt.Errorf("period is longer than %d days", MaxPeriodDays)
+	ErrMilestonesWithoutPeriod = errors.New("milestones require a start and end date")
+	ErrMilestoneKind           = errors.New("unknown milestone kind")
+	ErrMilestoneTitle          = errors.New("custom milestone needs a title")
+	ErrMilestoneDate           = errors.New("milestone date is missing or outside the period")
+)
+
+// MilestoneError meldet einen ungültigen Meilenstein.
+type MilestoneError struct {
+	Index int // Position in der Liste, ab 0
+	Err   error
+}
+
+func (e *MilestoneError) Error() string {
+	return fmt.Sprintf("milestone %d: %v", e.Index, e.Err)
+}
+
+func (e *MilestoneError) Unwrap() error {
+	return e.Err
+}
+
+// ValidatePeriod prüft Start- und Enddatum. Beide fehlen oder beide sind gesetzt.
+func ValidatePeriod(start, end models.Date) error {
+	switch {
+	case start.IsZero() && end.IsZero():
+		return nil
+	case start.IsZero() || end.IsZero():
+		return ErrIncompletePeriod
+	case end.Before(start):
+		return ErrEndBeforeStart
+	case start.DaysUntil(end) >= MaxPeriodDays:
+		return ErrPeriodTooLong
+	}
+	return nil
+}
+
+// Validate prüft den Zeitraum und ob alle Meilensteine darin liegen.
+func Validate(start, end models.Date, milestones []models.Milestone) error {
+	if err := ValidatePeriod(start, end); err != nil {
+		return err
+	}
+	if len(milestones) > 0 && start.IsZero() {
+		return ErrMilestonesWithoutPeriod
+	}
+	for i, milestone := range milestones {
+		var err error
+		switch {
+		case !validKind(milestone.Kind):
+			err = ErrMilestoneKind
+		case milestone.Kind == models.MilestoneCustom && milestone.Title == "":
+			err = ErrMilestoneTitle
+		case milestone.Date.IsZero() || milestone.Date.Before(start) || milestone.Date.After(end):
+			err = ErrMilestoneDate
+		}
+		if err != nil {
+			return &MilestoneError{Index: i, Err: err}
+		}
+	}
+	return nil
+}
+
+func validKind(kind models.MilestoneKind) bool {
+	switch kind {
+	case models.MilestoneKickoff, models.MilestoneExpertVisit, models.MilestoneHandIn, models.MilestoneCustom:
+		return true
+	}
+	return false
+}
+
+// DefaultMilestones liefert die Meilensteine eines neuen Projekts: Kick-off am ersten Tag und
+// Abgabe am letzten. Die Expertenbesuche werden vereinbart und danach erfasst.
+func DefaultMilestones(start, end models.Date) []models.Milestone {
+	if start.IsZero() {
+		return nil
+	}
+	return []models.Milestone{
+		{Kind: models.MilestoneKickoff, Title: "Kick-off", Date: start},
+		{Kind: models.MilestoneHandIn, Title: "Abgabe", Date: end},
+	}
+}
+
+// Build berechnet den Stand eines Projekts am Tag today.
+func Build(project models.MongoIpaProject, today models.Date) models.Timeline {
+	timeline := models.Timeline{
+		StartDate:  project.StartDate,
+		EndDate:    project.EndDate,
+		Today:      today,
+		Status:     StatusUnscheduled,
+		Milestones: make([]models.MilestoneStatus, len(project.Milestones)),
+	}
+	for i, milestone := range project.Milestones {
+		timeline.Milestones[i] = milestoneStatus(milestone, today)
+	}
+	if project.StartDate.IsZero() || project.EndDate.IsZero() {
+		return timeline
+	}
+
+	timeline.DaysTotal = project.StartDate.DaysUntil(project.EndDate) + 1
+	first := project.StartDate
+	switch {
+	case today.Before(project.StartDate):
+		timeline.Status = StatusUpcoming
+	case today.After(project.EndDate):
+		timeline.Status = StatusFinished
+		return timeline
+	default:
+		timeline.Status = StatusRunning
+		first = today
+	}
+	timeline.DaysRemaining = first.DaysUntil(project.EndDate) + 1
+	timeline.WorkdaysRemaining = workdays(first, project.EndDate)
+	return timeline
+}
+
+func milestoneStatus(milestone models.Milestone, today models.Date) models.MilestoneStatus {
+	status := models.MilestoneStatus{Milestone: milestone, DaysRemaining: today.DaysUntil(milestone.Date)}
+	switch {
+	case milestone.Done:
+		status.Status = MilestoneDone
+	case status.DaysRemaining == 0:
+		status.Status = MilestoneDue
+	case status.DaysRemaining > 0:
+		status.Status = MilestoneUpcoming
+	default:
+		status.Status = MilestoneOverdue
+	}
+	return status
+}
+
+// workdays zählt die Tage von Montag bis Freitag zwischen from und to, beide eingeschlossen.
+func workdays(from, to models.Date) int {
+	count := 0
+	for day := from; !day.After(to); day = day.AddDays(1) {
+		if day.IsWorkday() {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,113 @@
+package timeline
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+func date(t *testing.T, value string) models.Date {
+	t.Helper()
+	d, err := models.ParseDate(value)
+	if err != nil {
+		t.Fatalf("ParseDate(%q) error = %v", value, err)
+	}
+	return d
+}
+
+func TestValidate(t *testing.T) {
+	start, end := date(t, "2026-03-02"), date(t, "2026-03-13")
+	milestone := func(kind models.MilestoneKind, title, day string) []models.Milestone {
+		return []models.Milestone{{Kind: models.MilestoneKickoff, Date: start}, {Kind: kind, Title: title, Date: date(t, day)}}
+	}
+	tests := []struct {
+		name       string
+		start, end models.Date
+		milestones []models.Milestone
+		want       error
+	}{
+		{"unscheduled", models.Date{}, models.Date{}, nil, nil},
+		{"period with milestones", start, end, milestone(models.MilestoneExpertVisit, "", "2026-03-10"), nil},
+		{"end missing", start, models.Date{}, nil, ErrIncompletePeriod},
+		{"end before start", end, start, nil, ErrEndBeforeStart},
+		{"single day", start, start, nil, nil},
+		{"wrong year", start, date(t, "2027-03-13"), nil, ErrPeriodTooLong},
+		{"milestones without period", models.Date{}, models.Date{}, milestone(models.MilestoneHandIn, "", "2026-03-13"), ErrMilestonesWithoutPeriod},
+		{"unknown kind", start, end, milestone("party", "", "2026-03-10"), ErrMilestoneKind},
+		{"custom without title", start, end, milestone(models.MilestoneCustom, "", "2026-03-10"), ErrMilestoneTitle},
+		{"custom with title", start, end, milestone(models.MilestoneCustom, "Zwischenstand", "2026-03-10"), nil},
+		{"milestone after period", start, end, milestone(models.MilestoneHandIn, "", "2026-03-14"), ErrMilestoneDate},
+		{"milestone without date", start, end, []models.Milestone{{Kind: models.MilestoneHandIn}}, ErrMilestoneDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.start, tt.end, tt.milestones)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	var milestoneErr *MilestoneError
+	if err := Validate(start, end, milestone(models.MilestoneCustom, "", "2026-03-10")); !errors.As(err, &milestoneErr) || milestoneErr.Index != 1 {
+		t.Errorf("Validate() error = %v, want error of milestone 1", err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	project := models.MongoIpaProject{
+		StartDate: date(t, "2026-03-02"), // Montag
+		EndDate:   date(t, "2026-03-13"), // Freitag der zweiten Woche
+		Milestones: []models.Milestone{
+			{Kind: models.MilestoneKickoff, Date: date(t, "2026-03-02"), Done: true},
+			{Kind: models.MilestoneExpertVisit, Date: date(t, "2026-03-05")},
+			{Kind: models.MilestoneExpertVisit, Date: date(t, "2026-03-10")},
+			{Kind: models.MilestoneHandIn, Date: date(t, "2026-03-13")},
+		},
+	}
+	tests := []struct {
+		today             string
+		status            string
+		daysRemaining     int
+		workdaysRemaining int
+		milestones        []string
+	}{
+		{"2026-02-20", StatusUpcoming, 12, 10, []string{MilestoneDone, MilestoneUpcoming, MilestoneUpcoming, MilestoneUpcoming}},
+		{"2026-03-07", StatusRunning, 7, 5, []string{MilestoneDone, MilestoneOverdue, MilestoneUpcoming, MilestoneUpcoming}},
+		{"2026-03-13", StatusRunning, 1, 1, []string{MilestoneDone, MilestoneOverdue, MilestoneOverdue, MilestoneDue}},
+		{"2026-03-16", StatusFinished, 0, 0, []string{MilestoneDone, MilestoneOverdue, MilestoneOverdue, MilestoneOverdue}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.today, func(t *testing.T) {
+			got := Build(project, date(t, tt.today))
+			if got.Status != tt.status || got.DaysTotal != 12 || got.DaysRemaining != tt.daysRemaining || got.WorkdaysRemaining != tt.workdaysRemaining {
+				t.Errorf("Build() = %s, %d total, %d remaining, %d workdays; want %s, 12, %d, %d",
+					got.Status, got.DaysTotal, got.DaysRemaining, got.WorkdaysRemaining, tt.status, tt.daysRemaining, tt.workdaysRemaining)
+			}
+			for i, want := range tt.milestones {
+				if got.Milestones[i].Status != want {
+					t.Errorf("milestone %d status = %s, want %s", i, got.Milestones[i].Status, want)
+				}
+			}
+		})
+	}
+
+	if got := Build(project, date(t, "2026-03-07")).Milestones[2].DaysRemaining; got != 3 {
+		t.Errorf("DaysRemaining of expert visit = %d, want 3", got)
+	}
+	if got := Build(models.MongoIpaProject{}, date(t, "2026-03-07")); got.Status != StatusUnscheduled || got.Milestones == nil {
+		t.Errorf("Build() of unscheduled project = %+v", got)
+	}
+}
+
+func TestDefaultMilestones(t *testing.T) {
+	start, end := date(t, "2026-03-02"), date(t, "2026-03-13")
+	milestones := DefaultMilestones(start, end)
+	if err := Validate(start, end, milestones); err != nil || len(milestones) != 2 {
+		t.Errorf("DefaultMilestones() = %+v, Validate() error = %v", milestones, err)
+	}
+	if DefaultMilestones(models.Date{}, models.Date{}) != nil {
+		t.Error("DefaultMilestones() without period != nil")
+	}
+}
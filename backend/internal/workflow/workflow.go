@@ -0,0 +1,95 @@
+// Package workflow regelt, wer ein Projekt in welchen Status versetzen darf und wann die
+// Kriterien eines Projekts gesperrt sind.
+package workflow
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+var (
+	ErrTransition = errors.New("status transition is not possible")
+	ErrRole       = errors.New("role may not perform the status transition")
+)
+
+// transition ist ein erlaubter Übergang für eine Rolle.
+type transition struct {
+	from, to models.ProjectStatus
+	role     models.Role
+}
+
+var transitions = []transition{
+	{models.StatusInProgress, models.StatusSubmitted, models.RoleCandidate},
+	{models.StatusSubmitted, models.StatusInProgress, models.RoleCandidate}, // Abgabe zurückziehen
+	{models.StatusSubmitted, models.StatusInProgress, models.RoleExpert},    // Zur Überarbeitung zurückgeben
+	{models.StatusSubmitted, models.StatusUnderReview, models.RoleExpert},
+	{models.StatusUnderReview, models.StatusInProgress, models.RoleExpert},
+	{models.StatusUnderReview, models.StatusGraded, models.RoleExpert},
+	{models.StatusGraded, models.StatusUnderReview, models.RoleExpert}, // Bewertung korrigieren, es entsteht eine neue Momentaufnahme
+	{models.StatusGraded, models.StatusClosed, models.RoleExpert},
+}
+
+// Check prüft, ob user das Projekt in den Status to versetzen darf.
+func Check(project models.MongoIpaProject, to models.ProjectStatus, user models.User) error {
+	from := project.CurrentStatus()
+	possible := false
+	for _, t := range transitions {
+		if t.from != from || t.to != to {
+			continue
+		}
+		if t.role == user.Role {
+			return nil
+		}
+		possible = true
+	}
+	if possible {
+		return ErrRole
+	}
+	return ErrTransition
+}
+
+// Transitions liefert die Status, in die user das Projekt versetzen darf.
+func Transitions(project models.MongoIpaProject, user models.User) []models.ProjectStatus {
+	allowed := make([]models.ProjectStatus, 0)
+	for _, t := range transitions {
+		if t.from == project.CurrentStatus() && t.role == user.Role && !slices.Contains(allowed, t.to) {
+			allowed = append(allowed, t.to)
+		}
+	}
+	return allowed
+}
+
+// Editable meldet, ob user die Kriterien des Projekts ändern darf. Nach der Abgabe sind sie
+// gesperrt, nur während der Bewertung dürfen die Expertinnen und Experten sie ändern.
+func Editable(project models.MongoIpaProject, user models.User) bool {
+	return slices.Contains(EditableStatuses(user), project.CurrentStatus())
+}
+
+// EditableStatuses liefert die Status, in denen user die Kriterien ändern darf. Der Store
+// schreibt Kriterien nur, solange das Projekt noch in einem dieser Status ist.
+func EditableStatuses(user models.User) []models.ProjectStatus {
+	if user.Role == models.RoleExpert {
+		return []models.ProjectStatus{models.StatusInProgress, models.StatusUnderReview}
+	}
+	return []models.ProjectStatus{models.StatusInProgress}
+}
+
+// Build stellt den Status des Projekts aus Sicht von user zusammen.
+func Build(project models.MongoIpaProject, user models.User) models.Workflow {
+	workflow := models.Workflow{
+		Status:      project.CurrentStatus(),
+		Editable:    Editable(project, user),
+		Transitions: Transitions(project, user),
+		History:     project.StatusHistory,
+		Snapshots:   project.GradeSnapshots,
+	}
+	if workflow.History == nil {
+		workflow.History = make([]models.StatusChange, 0)
+	}
+	if workflow.Snapshots == nil {
+		workflow.Snapshots = make([]models.GradeSnapshot, 0)
+	}
+	return workflow
+}
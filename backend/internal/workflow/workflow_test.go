@@ -0,0 +1,91 @@
+package workflow
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Liuuner/criteria-catalogue/backend/internal/models"
+)
+
+var (
+	candidate = models.User{Role: models.RoleCandidate, Name: "Anna Muster"}
+	expert    = models.User{Role: models.RoleExpert, Name: "Eva Experte"}
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		from models.ProjectStatus
+		to   models.ProjectStatus
+		user models.User
+		want error
+	}{
+		{"submit", models.StatusInProgress, models.StatusSubmitted, candidate, nil},
+		{"submit without status", "", models.StatusSubmitted, candidate, nil},
+		{"withdraw", models.StatusSubmitted, models.StatusInProgress, candidate, nil},
+		{"return for changes", models.StatusSubmitted, models.StatusInProgress, expert, nil},
+		{"start review", models.StatusSubmitted, models.StatusUnderReview, expert, nil},
+		{"grade", models.StatusUnderReview, models.StatusGraded, expert, nil},
+		{"reopen", models.StatusGraded, models.StatusUnderReview, expert, nil},
+		{"close", models.StatusGraded, models.StatusClosed, expert, nil},
+		{"expert submits", models.StatusInProgress, models.StatusSubmitted, expert, ErrRole},
+		{"candidate grades", models.StatusUnderReview, models.StatusGraded, candidate, ErrRole},
+		{"skip review", models.StatusSubmitted, models.StatusGraded, expert, ErrTransition},
+		{"reopen closed", models.StatusClosed, models.StatusUnderReview, expert, ErrTransition},
+		{"same status", models.StatusInProgress, models.StatusInProgress, candidate, ErrTransition},
+		{"unknown status", models.StatusInProgress, "done", candidate, ErrTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := models.MongoIpaProject{Status: tt.from}
+			if err := Check(project, tt.to, tt.user); !errors.Is(err, tt.want) {
+				t.Errorf("Check() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransitions(t *testing.T) {
+	submitted := models.MongoIpaProject{Status: models.StatusSubmitted}
+	if got := Transitions(submitted, expert); !slices.Equal(got, []models.ProjectStatus{models.StatusInProgress, models.StatusUnderReview}) {
+		t.Errorf("Transitions(expert) = %v", got)
+	}
+	if got := Transitions(submitted, candidate); !slices.Equal(got, []models.ProjectStatus{models.StatusInProgress}) {
+		t.Errorf("Transitions(candidate) = %v", got)
+	}
+	if got := Transitions(models.MongoIpaProject{Status: models.StatusClosed}, expert); got == nil || len(got) != 0 {
+		t.Errorf("Transitions(closed) = %#v, want empty slice", got)
+	}
+}
+
+func TestEditable(t *testing.T) {
+	tests := []struct {
+		status            models.ProjectStatus
+		candidate, expert bool
+	}{
+		{"", true, true},
+		{models.StatusInProgress, true, true},
+		{models.StatusSubmitted, false, false},
+		{models.StatusUnderReview, false, true},
+		{models.StatusGraded, false, false},
+		{models.StatusClosed, false, false},
+	}
+	for _, tt := range tests {
+		project := models.MongoIpaProject{Status: tt.status}
+		if got := Editable(project, candidate); got != tt.candidate {
+			t.Errorf("Editable(%q, candidate) = %v, want %v", tt.status, got, tt.candidate)
+		}
+		if got := Editable(project, expert); got != tt.expert {
+			t.Errorf("Editable(%q, expert) = %v, want %v", tt.status, got, tt.expert)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	workflow := Build(models.MongoIpaProject{}, candidate)
+	if workflow.Status != models.StatusInProgress || !workflow.Editable || workflow.History == nil || workflow.Snapshots == nil ||
+		!slices.Equal(workflow.Transitions, []models.ProjectStatus{models.StatusSubmitted}) {
+		t.Errorf("Build() = %+v", workflow)
+	}
+}
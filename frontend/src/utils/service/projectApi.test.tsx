@@ -68,6 +68,15 @@ describe("projectApi", () => {
         await expect(getIpa("whatever")).resolves.toBeNull();
     });
 
+    it("getIpa: returns null when API returns an empty id", async () => {
+        mockFetchOnce({
+            status: 200,
+            text: async () => JSON.stringify({id: ""}),
+        });
+
+        await expect(getIpa("whatever")).resolves.toBeNull();
+    });
+
     it("fetchJson: returns null on 204", async () => {
         mockFetchOnce({status: 204, text: async () => ""});
 
@@ -75,16 +84,26 @@ describe("projectApi", () => {
         expect(toast.error).not.toHaveBeenCalled();
     });
 
-    it("fetchJson: calls toast.error and returns null when payload has error", async () => {
+    it("fetchJson: calls toast.error and returns null when payload is a problem", async () => {
         mockFetchOnce({
-            status: 400,
-            text: async () => JSON.stringify({error: "Boom"}),
+            status: 404,
+            text: async () => JSON.stringify({type: "urn:criteria-catalogue:error:project_not_found", title: "Boom", status: 404, code: "project_not_found"}),
         });
 
         await expect(getGrades("X")).resolves.toBeNull();
         expect(toast.error).toHaveBeenCalledWith("Boom");
     });
 
+    it("fetchJson: shows the detail of a problem", async () => {
+        mockFetchOnce({
+            status: 400,
+            text: async () => JSON.stringify({title: "Ungültige Anfrage", detail: "Feld id hat einen ungültigen Typ", status: 400, code: "invalid_request"}),
+        });
+
+        await expect(getCriteria("X")).resolves.toEqual([]);
+        expect(toast.error).toHaveBeenCalledWith("Ungültige Anfrage: Feld id hat einen ungültigen Typ");
+    });
+
     it("createIpa: POSTs JSON and returns created IPA", async () => {
         const person = {firstName: "Ada"} as any;
 
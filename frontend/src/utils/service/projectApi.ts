@@ -3,6 +3,22 @@ import {toast} from "sonner";
 
 const API_BASE = import.meta.env.VITE_API_URL;
 
+// Fehlerantworten sind Problem Details (RFC 7807) mit stabilem code, title und optional detail.
+type Problem = {
+    code: string;
+    title: string;
+    detail?: string;
+};
+
+function isProblem(data: unknown): data is Problem {
+    return typeof data === "object" && data !== null && typeof (data as Problem).code === "string"
+        && typeof (data as Problem).title === "string";
+}
+
+function problemMessage(problem: Problem): string {
+    return problem.detail ? `${problem.title}: ${problem.detail}` : problem.title;
+}
+
 async function fetchJson<T>(input: RequestInfo, init?: RequestInit): Promise<T | null> {
     const res = await fetch(input, {
         ...init,
@@ -22,12 +38,11 @@ async function fetchJson<T>(input: RequestInfo, init?: RequestInit): Promise<T |
 
     const text = await res.text();
     const returnData = JSON.parse(text)
-    if (returnData && returnData?.error === undefined) {
-        return JSON.parse(text) as T;
-    } else {
-        toast.error(returnData.error);
+    if (isProblem(returnData)) {
+        toast.error(problemMessage(returnData));
+        return null;
     }
-    return null;
+    return returnData as T;
 }
 
 export async function login(request: LoginRequest): Promise<boolean> {
@@ -43,7 +58,7 @@ export async function login(request: LoginRequest): Promise<boolean> {
     } else {
         const text = await res.text();
         const returnData = JSON.parse(text)
-        toast.error(returnData.error || 'Login fehlgeschlagen');
+        toast.error(isProblem(returnData) ? problemMessage(returnData) : 'Login fehlgeschlagen');
         return false;
     }
 }
@@ -60,14 +75,14 @@ export async function logout(): Promise<boolean> {
     } else {
         const text = await res.text();
         const returnData = JSON.parse(text)
-        toast.error(returnData.error || 'Login fehlgeschlagen');
+        toast.error(isProblem(returnData) ? problemMessage(returnData) : 'Login fehlgeschlagen');
         return false;
     }
 }
 
 export async function getIpa(id: string): Promise<IPA | null> {
     const json = await fetchJson<IPA>(`${API_BASE}/api/ipa/${id}`);
-    return json && json.id !== "AA00" ? json : null;
+    return json && json.id && json.id !== "AA00" ? json : null;
 }
 
 export async function createIpa(personData: PersonData): Promise<IPA | null> {